@@ -42,6 +42,10 @@ are deleted they are no longer visible on the `/metrics` endpoint.
   * [Container Image](#container-image)
 * [Metrics Documentation](#metrics-documentation)
   * [Conflict resolution in label names](#conflict-resolution-in-label-names)
+  * [Renaming metrics](#renaming-metrics)
+  * [Human readable timestamps](#human-readable-timestamps)
+  * [Filtering metrics at scrape time](#filtering-metrics-at-scrape-time)
+  * [Metric inventory](#metric-inventory)
 * [Kube-state-metrics self metrics](#kube-state-metrics-self-metrics)
 * [Resource recommendation](#resource-recommendation)
 * [Latency](#latency)
@@ -49,6 +53,7 @@ are deleted they are no longer visible on the `/metrics` endpoint.
 * [kube-state-metrics vs. metrics-server](#kube-state-metrics-vs-metrics-server)
 * [Scaling kube-state-metrics](#scaling-kube-state-metrics)
   * [Resource recommendation](#resource-recommendation)
+  * [Profiles](#profiles)
   * [Horizontal sharding](#horizontal-sharding)
     * [Automated sharding](#automated-sharding)
   * [Daemonset sharding for pod metrics](#daemonset-sharding-for-pod-metrics)
@@ -59,6 +64,7 @@ are deleted they are no longer visible on the `/metrics` endpoint.
   * [Limited privileges environment](#limited-privileges-environment)
   * [Helm Chart](#helm-chart)
   * [Development](#development)
+  * [Comparing two versions](#comparing-two-versions)
   * [Developer Contributions](#developer-contributions)
   * [Community](#community)
 
@@ -124,12 +130,89 @@ Kube-state-metrics automatically adds a suffix `_conflictN` to resolve this conf
 so it converts the above labels to
 `label_foo_bar_conflict1` and `label_foo_bar_conflict2`.
 
+Suffixed keys which collide with keys ending in `_conflictN` themselves are suffixed again,
+e.g. `foo_bar_conflict1` becomes `label_foo_bar_conflict1_conflict1` if `foo-bar` and `foo_bar` are present as well.
+Label names of [custom resource state metrics](docs/metrics/extend/customresourcestate-metrics.md) which start with a digit
+after the conversion are prefixed with an underscore.
+
+The number of converted keys is exposed by `kube_state_metrics_label_keys_sanitized_total`, with the `reason` label
+`sanitized` for keys which were not valid Prometheus label names as is and `conflict` for keys which were suffixed.
+A steadily growing `conflict` count points at objects whose labels or annotations should be cleaned up.
+
 If you'd like to have more control over how this conflict is resolved,
 you might want to consider addressing this issue on a different level of the stack,
 e.g. by standardizing Kubernetes labels using an
 [Admission Webhook](https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/)
 that ensures that there are no possible conflicts.
 
+#### Renaming metrics
+
+Renaming a metric family breaks every query, alert and dashboard using its
+previous name. To migrate them gradually, a family can be exposed under its
+previous name as well for a deprecation window, using `--metric-aliases`:
+
+```
+--metric-aliases=kube_pod_container_restart_policy=kube_pod_container_restartpolicy
+```
+
+The alias is generated from the same objects as the family itself and its help
+text is prefixed with `(Deprecated alias of <family>)`.
+
+#### Human readable timestamps
+
+Timestamp families such as `kube_pod_created` expose Unix timestamps as their
+value. For dashboards which show them as text, they can additionally be exposed
+as `<family>_info` families with the timestamp formatted as RFC3339 in the
+`timestamp` label, using `--metric-timestamp-info`:
+
+```
+--metric-timestamp-info=kube_pod_created,kube_deployment_created
+```
+
+```
+kube_pod_created{namespace="default",pod="nginx",uid="..."} 1.5e+09
+kube_pod_created_info{namespace="default",pod="nginx",timestamp="2017-07-14T02:40:00Z",uid="..."} 1
+```
+
+#### Filtering metrics at scrape time
+
+The `/metrics` endpoint accepts the `include` and `exclude` query parameters,
+which take comma-separated lists of metric family names. Only the given
+families are written out, respectively all but the given ones, which is useful
+for debugging and for scrape jobs only interested in a few families:
+
+```
+curl 'localhost:8080/metrics?include=kube_pod_info,kube_pod_status_phase'
+```
+
+The families are still generated for every object, use `--metric-allowlist`
+or `--metric-denylist` to save the resources spent on families which are never
+scraped.
+
+#### Metric inventory
+
+The `/metrics-inventory` endpoint returns the metric families enabled by the
+running configuration as JSON, so that tooling such as alert rule generators
+can check which families and label keys are available:
+
+```
+curl -s localhost:8080/metrics-inventory | jq '.[] | select(.name == "kube_pod_info")'
+```
+
+```json
+{
+  "name": "kube_pod_info",
+  "help": "Information about pod.",
+  "type": "gauge",
+  "stability": "STABLE",
+  "collector": "*v1.Pod",
+  "labelKeys": ["created_by_kind", "created_by_name", "host_ip", "namespace", "node", "pod", "..."]
+}
+```
+
+Some label keys depend on the objects, e.g. the ones of the `*_labels`
+families, so `labelKeys` holds the label keys of the series generated so far.
+
 ### Kube-state-metrics self metrics
 
 kube-state-metrics exposes its own general process metrics under `--telemetry-host` and `--telemetry-port` (default 8081).
@@ -146,6 +229,66 @@ kube_state_metrics_list_total{resource="*v1.Node",result="error"} 52
 kube_state_metrics_watch_total{resource="*v1beta1.Ingress",result="success"} 1
 ```
 
+The resource version of the last successful list or watch of each resource is exposed as well. A resource version which
+stops increasing while the resource keeps changing in the cluster points to a watch which silently stalled, e.g.:
+
+```
+changes(kube_state_metrics_last_resource_version{resource="*v1.Pod"}[30m]) == 0
+```
+
+kube-state-metrics also exposes how long it took for the initial list of each resource to be populated into its store,
+measured from the moment the stores were built. The number of resources listed concurrently and the order in which they
+are listed can be tuned with `--initial-list-concurrency` and `--initial-list-order`, so that the heaviest resources
+warm up first on large clusters:
+
+```
+kube_state_metrics_warmup_duration_seconds{resource="*v1.Pod"} 12.7
+kube_state_metrics_warmup_duration_seconds{resource="*v1.Node"} 0.4
+```
+
+When `--track-namespace-recreation` is set together with `--namespaces`, the
+reflectors of a namespace are stopped when it is deleted and started again when
+it is recreated. These lifecycle events are counted per namespace:
+
+```
+kube_state_metrics_namespace_reflector_events_total{namespace="default",event="stopped"} 1
+kube_state_metrics_namespace_reflector_events_total{namespace="default",event="started"} 1
+```
+
+Objects whose metrics cannot be generated, e.g. because a generator panicked on a
+malformed object, are skipped instead of crashing kube-state-metrics. They are
+counted per collector:
+
+```
+kube_state_metrics_generation_errors_total{collector="*v1.Pod"} 0
+```
+
+The number of bytes which the `*_labels` and `*_annotations` families contribute to
+the exposition is accounted per resource and namespace. This helps to find the
+namespaces whose metadata inflates the payload before restricting
+`--metric-labels-allowlist` and `--metric-annotations-allowlist`:
+
+```
+kube_state_metrics_metadata_bytes{resource="*v1.Pod",namespace="default"} 18432
+```
+
+The number of cached objects of each resource per namespace and an estimate of the memory held by the metrics of each
+resource help with capacity planning of kube-state-metrics itself and with deciding when to shard:
+
+```
+kube_state_metrics_objects{resource="*v1.Pod",namespace="default"} 120
+kube_state_metrics_store_bytes{resource="*v1.Pod"} 1.572864e+06
+```
+
+The time each collector takes to write out its metrics and the number of
+uncompressed bytes it contributes to the last `/metrics` response show which
+resource dominates the scrape time and size:
+
+```
+kube_state_metrics_collector_generate_duration_seconds_sum{collector="pods"} 0.84
+kube_state_metrics_collector_bytes{collector="pods"} 4.1943e+07
+```
+
 kube-state-metrics also exposes some http request metrics, examples of those are:
 
 ```
@@ -157,6 +300,14 @@ http_request_duration_seconds_sum{handler="metrics",method="get"} 0.021113919999
 http_request_duration_seconds_count{handler="metrics",method="get"} 30
 ```
 
+The requests of kube-state-metrics to the apiserver are instrumented as well, so relist storms can be observed and
+throttled with `--kube-api-qps` and `--kube-api-burst`:
+
+```
+rest_client_request_duration_seconds_count{host="10.96.0.1:443",verb="GET"} 1204
+rest_client_requests_total{code="200",host="10.96.0.1:443",method="GET"} 1204
+```
+
 kube-state-metrics also exposes build and configuration metrics:
 
 ```
@@ -195,6 +346,17 @@ As a general rule, you should allocate:
 
 Note that if CPU limits are set too low, kube-state-metrics' internal queues will not be able to be worked off quickly enough, resulting in increased memory consumption as the queue length grows. If you experience problems resulting from high memory allocation or CPU throttling, try increasing the CPU limits.
 
+#### Profiles
+
+Instead of assembling the flags for the size of a cluster by hand, a preset
+profile can be selected with `--profile=small`, `--profile=medium` or
+`--profile=large`. A profile sets the defaults of flags such as
+`--use-apiserver-cache`, `--initial-list-concurrency`, `--initial-list-order`
+and `--metric-denylist`. Flags set on the command line and the options config
+file passed with `--config` take precedence over the profile. The profiles are
+embedded in the binary, their contents can be found in
+[pkg/options/profiles](pkg/options/profiles).
+
 ### Latency
 
 In a 100 node cluster scaling test the latency numbers were as follows:
@@ -239,7 +401,7 @@ In order to shard kube-state-metrics horizontally, some automated sharding capab
 * `--shard` (zero indexed)
 * `--total-shards`
 
-Sharding is done by taking an md5 sum of the Kubernetes Object's UID and performing a modulo operation on it with the total number of shards. Each shard decides whether the object is handled by the respective instance of kube-state-metrics or not. Note that this means all instances of kube-state-metrics, even if sharded, will have the network traffic and the resource consumption for unmarshaling objects for all objects, not just the ones they are responsible for. To optimize this further, the Kubernetes API would need to support sharded list/watch capabilities. In the optimal case, memory consumption for each shard will be 1/n compared to an unsharded setup. Typically, kube-state-metrics needs to be memory and latency optimized in order for it to return its metrics rather quickly to Prometheus. One way to reduce the latency between kube-state-metrics and the kube-apiserver is to run KSM with the `--use-apiserver-cache` flag. In addition to reducing the latency, this option will also lead to a reduction in the load on etcd.
+Sharding is done by taking an FNV-1a hash of the Kubernetes Object's UID and assigning it to a shard with [jump consistent hashing](https://arxiv.org/abs/1406.2294). Unlike a modulo operation, changing the total number of shards from n to n+1 only moves about 1/(n+1) of the objects, all of them to the new shard, which keeps the series churn in the TSDB low when scaling shards. Each shard decides whether the object is handled by the respective instance of kube-state-metrics or not. Note that this means all instances of kube-state-metrics, even if sharded, will have the network traffic and the resource consumption for unmarshaling objects for all objects, not just the ones they are responsible for. To optimize this further, the Kubernetes API would need to support sharded list/watch capabilities. In the optimal case, memory consumption for each shard will be 1/n compared to an unsharded setup. Typically, kube-state-metrics needs to be memory and latency optimized in order for it to return its metrics rather quickly to Prometheus. One way to reduce the latency between kube-state-metrics and the kube-apiserver is to run KSM with the `--use-apiserver-cache` flag. In addition to reducing the latency, this option will also lead to a reduction in the load on etcd.
 
 Sharding should be used carefully and additional monitoring should be set up in order to ensure that sharding is set up and functioning as expected (eg. instances for each shard out of the total shards are configured).
 
@@ -351,7 +513,7 @@ The following healthcheck endpoints are available (`self` refers to the telemetr
 
 * `/healthz` (exposed on `main`): Returns a 200 status code if the application is running. We recommend to use this for the startup probe.
 * `/livez` (exposed on `main`): Returns a 200 status code if the application is not affected by an outage of the Kubernetes API Server. We recommend to using this for the liveness probe.
-* `/readyz` (exposed on `self`): Returns a 200 status code if the application is ready to accept requests and expose metrics. We recommend using this for the readiness probe.
+* `/readyz` (exposed on `self`): Returns a 200 status code if the application is ready to accept requests and expose metrics, i.e. once the stores of all resources populated their initial list. Until then, the exposed metrics would only reflect part of the cluster state. We recommend using this for the readiness probe.
 
 Note that it is discouraged to use the telemetry metrics endpoint for any probe when proxying the exposition data.
 
@@ -400,6 +562,11 @@ spec:
           - '--namespaces=project1'
 ```
 
+Note that `--namespaces` and `--namespaces-denylist` also accept glob patterns
+such as `tenant-*`. As patterns are matched against the namespace of each object,
+they require kube-state-metrics to list and watch the resources across all
+namespaces, so they cannot be used with the privileges described above.
+
 For the full list of arguments available, see the documentation in [docs/developer/cli-arguments.md](./docs/developer/cli-arguments.md)
 
 #### Helm Chart
@@ -412,6 +579,8 @@ When developing, test a metric dump against your local Kubernetes cluster by
 running:
 
 > Users can override the apiserver address in KUBE-CONFIG file with `--apiserver` command line.
+>
+> The certificate of the overriding apiserver is still verified with the CA certificate of the KUBE-CONFIG file or the in-cluster service account. If it is not valid for the new host, use `--apiserver-tls-server-name` or `--apiserver-ca-file`, or `--apiserver-insecure-skip-tls-verify` for testing only.
 
  go install
  kube-state-metrics --port=8080 --telemetry-port=8081 --kubeconfig=<KUBE-CONFIG> --apiserver=<APISERVER>
@@ -422,6 +591,17 @@ Then curl the metrics endpoint
 
 To run the e2e tests locally see the documentation in [tests/README.md](./tests/README.md).
 
+#### Comparing two versions
+
+Before rolling out a new version or configuration, its metrics can be compared
+with the ones of the running instance:
+
+ kube-state-metrics diff --a=http://old:8080/metrics --b=http://new:8080/metrics --threshold=0.1
+
+Added and removed metric families, label names and series are reported, as
+well as values which changed by more than the relative `--threshold`. The
+command exits with 1 if any difference was found.
+
 #### Developer Contributions
 
 When developing, there are certain code patterns to follow to better your contributing experience and likelihood of e2e and other ci tests to pass. To learn more about them, see the documentation in [docs/developer/guide.md](./docs/developer/guide.md).
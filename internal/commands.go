@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/app"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// NewServeCommand returns the command serving the metrics, which is also what
+// the root command does when no subcommand is given.
+func NewServeCommand(root *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the metrics, the default when no command is given.",
+		Args:  cobra.NoArgs,
+		Run:   root.Run,
+	}
+	addRootFlags(cmd, root)
+
+	return cmd
+}
+
+// NewValidateCommand returns the command checking the options, the options
+// config file and the custom resource state configuration, and then the
+// discovery of the enabled resources like --dry-run, unless --skip-discovery is
+// set. It exits with a non-zero code if they are invalid.
+func NewValidateCommand(root *cobra.Command, opts *options.Options) *cobra.Command {
+	var skipDiscovery bool
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the options and configuration files.",
+		Long:  "Validate the flags, the options config file and the custom resource state configuration like the serve command does on startup, then connect to the apiserver and report the collectors and metric families which would be enabled like --dry-run. Exits with a non-zero code if the configuration is invalid or an enabled resource can not be collected.",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := opts.SetupLogging(); err != nil {
+				klog.ErrorS(err, "Failed to set up logging")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+			if err := app.ValidateOptions(opts); err != nil {
+				klog.ErrorS(err, "Invalid configuration")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+			klog.InfoS("Configuration is valid")
+			if !skipDiscovery {
+				opts.DryRun = true
+				if err := app.RunKubeStateMetrics(context.Background(), opts); err != nil {
+					klog.ErrorS(err, "Failed to discover the enabled resources")
+					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+				}
+			}
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		},
+	}
+	addRootFlags(cmd, root)
+	cmd.Flags().BoolVar(&skipDiscovery, "skip-discovery", false, "Only validate the configuration, without connecting to the apiserver.")
+
+	return cmd
+}
+
+// NewVersionCommand returns the command printing the build information.
+func NewVersionCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version information.",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := app.WriteVersion(os.Stdout, output); err != nil {
+				klog.ErrorS(err, "Failed to write version")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Format of the build information, one of text or json.")
+
+	return cmd
+}
+
+// addRootFlags shares the flags of the root command with cmd, so that both
+// set the same options. The help and version flags are left to cobra.
+func addRootFlags(cmd, root *cobra.Command) {
+	root.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" || f.Name == "version" {
+			return
+		}
+		cmd.Flags().AddFlag(f)
+	})
+}
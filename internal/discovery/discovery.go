@@ -220,7 +220,7 @@ func (r *CRDiscoverer) PollForCacheUpdates(
 			enabledCustomResources = append(enabledCustomResources, gvrString)
 		}
 		// Create clients for discovered factories.
-		discoveredCustomResourceClients, err := util.CreateCustomResourceClients(opts.Apiserver, opts.Kubeconfig, customFactories...)
+		discoveredCustomResourceClients, err := util.CreateCustomResourceClients(util.NewClientOptions(opts), customFactories...)
 		if err != nil {
 			klog.ErrorS(err, "failed to update custom resource stores")
 		}
@@ -18,6 +18,8 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -181,3 +183,23 @@ func TestGVKMapsResolveGVK(t *testing.T) {
 		}
 	}
 }
+
+func TestResourcesDiscoveredGauge(t *testing.T) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "kube_state_metrics_resources_discovered"}, []string{"group", "version", "kind"})
+	r := &CRDiscoverer{ResourcesDiscoveredGauge: gauge}
+
+	foo := groupVersionKindPlural{GroupVersionKind: schema.GroupVersionKind{Group: "myteam.io", Version: "v1", Kind: "Foo"}, Plural: "foos"}
+	bar := groupVersionKindPlural{GroupVersionKind: schema.GroupVersionKind{Group: "myteam.io", Version: "v1", Kind: "Bar"}, Plural: "bars"}
+	r.AppendToMap(foo, bar)
+	if got := testutil.CollectAndCount(gauge); got != 2 {
+		t.Fatalf("expected 2 discovered resources, got %d", got)
+	}
+	if got := testutil.ToFloat64(gauge.WithLabelValues("myteam.io", "v1", "Foo")); got != 1 {
+		t.Fatalf("expected Foo to be discovered, got %v", got)
+	}
+
+	r.RemoveFromMap(foo)
+	if got := testutil.CollectAndCount(gauge); got != 1 {
+		t.Fatalf("expected 1 discovered resource after removing Foo, got %d", got)
+	}
+}
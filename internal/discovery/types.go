@@ -43,6 +43,8 @@ type CRDiscoverer struct {
 	CRDsDeleteEventsCounter prometheus.Counter
 	// CRDsCacheCountGauge tracks the net amount of CRDs affecting the cache at this point.
 	CRDsCacheCountGauge prometheus.Gauge
+	// ResourcesDiscoveredGauge tracks the GVKs which are currently in the cache.
+	ResourcesDiscoveredGauge *prometheus.GaugeVec
 	// Map is a cache of the collected GVKs.
 	Map map[string]map[string][]kindPlural
 	// m is a mutex to protect the cache.
@@ -78,6 +80,9 @@ func (r *CRDiscoverer) AppendToMap(gvkps ...groupVersionKindPlural) {
 			r.Map[gvkp.Group][gvkp.Version] = []kindPlural{}
 		}
 		r.Map[gvkp.Group][gvkp.Version] = append(r.Map[gvkp.Group][gvkp.Version], kindPlural{Kind: gvkp.Kind, Plural: gvkp.Plural})
+		if r.ResourcesDiscoveredGauge != nil {
+			r.ResourcesDiscoveredGauge.WithLabelValues(gvkp.Group, gvkp.Version, gvkp.Kind).Set(1)
+		}
 	}
 }
 
@@ -92,6 +97,9 @@ func (r *CRDiscoverer) RemoveFromMap(gvkps ...groupVersionKindPlural) {
 		}
 		for i, el := range r.Map[gvkp.Group][gvkp.Version] {
 			if el.Kind == gvkp.Kind {
+				if r.ResourcesDiscoveredGauge != nil {
+					r.ResourcesDiscoveredGauge.DeleteLabelValues(gvkp.Group, gvkp.Version, gvkp.Kind)
+				}
 				if len(r.Map[gvkp.Group][gvkp.Version]) == 1 {
 					delete(r.Map[gvkp.Group], gvkp.Version)
 					if len(r.Map[gvkp.Group]) == 0 {
@@ -0,0 +1,51 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+)
+
+// NewDocsCommand returns the command writing the catalog of the metric
+// families of the built-in resources, so that documentation can be generated
+// from the code.
+func NewDocsCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Print the catalog of the metric families of the built-in resources.",
+		Long:  "Print the name, type, help text, stability and resource of every metric family of the built-in resources, including the opt-in ones, as Markdown tables or as JSON.",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if err := store.WriteCatalog(os.Stdout, format, store.Catalog()); err != nil {
+				klog.ErrorS(err, "Failed to write the metric catalog")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "markdown", "Format of the catalog, one of markdown or json.")
+
+	return cmd
+}
@@ -19,10 +19,13 @@ package store
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -38,7 +41,10 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
@@ -66,28 +72,63 @@ var _ ksmtypes.BuilderInterface = &Builder{}
 // (https://en.wikipedia.org/wiki/Builder_pattern).
 type Builder struct {
 	kubeClient                    clientset.Interface
+	metadataClient                metadata.Interface
 	ctx                           context.Context
 	familyGeneratorFilter         generator.FamilyGeneratorFilter
 	customResourceClients         map[string]interface{}
 	listWatchMetrics              *watch.ListWatchMetrics
 	shardingMetrics               *sharding.Metrics
+	warmupMetrics                 *warmupMetrics
+	warmupGate                    *warmupGate
+	namespaceReflectorMetrics     *namespaceReflectorMetrics
+	generationErrorMetrics        *generationErrorMetrics
+	metadataSizeMetrics           *metadataSizeMetrics
+	storeSizeMetrics              *storeSizeMetrics
+	inventory                     *metricInventory
+	objectInventory               *objectInventory
+	namespaceReflectors           *namespaceReflectors
 	buildStoresFunc               ksmtypes.BuildStoresFunc
 	buildCustomResourceStoresFunc ksmtypes.BuildCustomResourceStoresFunc
 	allowAnnotationsList          map[string][]string
 	allowLabelsList               map[string][]string
+	metricAliases                 map[string]string
+	metricTimestampInfo           map[string]struct{}
+	metricLabelsDenylist          map[string][]string
+	metadataOnlyResources         map[string]struct{}
 	utilOptions                   *options.Options
 	// namespaceFilter is inside fieldSelectorFilter
-	fieldSelectorFilter string
-	namespaces          options.NamespaceList
-	enabledResources    []string
-	totalShards         int
-	shard               int32
-	useAPIServerCache   bool
+	fieldSelectorFilter      string
+	resourceFieldSelectors   map[string]string
+	objectsLabelSelector     string
+	resourceLabelSelectors   map[string]string
+	namespaces               options.NamespaceList
+	namespacePatternFilter   *options.NamespaceFilter
+	enabledResources         []string
+	initialListOrder         []string
+	totalShards              int
+	initialListConcurrency   int
+	relistPeriod             time.Duration
+	resyncPeriod             time.Duration
+	shard                    int32
+	useAPIServerCache        bool
+	trackNamespaceRecreation bool
+	// resourceFieldSelector and resourceLabelSelector are the selectors of
+	// the resource whose stores are being built.
+	resourceFieldSelector string
+	resourceLabelSelector string
+	// resourceMetadataOnly is set if the resource whose stores are being
+	// built is listed and watched as metadata only.
+	resourceMetadataOnly *metadataOnlyResource
+	// builtWarmupGate is the warmup gate of the last completed build, see
+	// Synced.
+	builtWarmupGate atomic.Pointer[warmupGate]
 }
 
 // NewBuilder returns a new builder.
 func NewBuilder() *Builder {
-	b := &Builder{}
+	b := &Builder{
+		inventory: newMetricInventory(),
+	}
 	return b
 }
 
@@ -103,6 +144,37 @@ func (b *Builder) WithUtilOptions(opts *options.Options) {
 func (b *Builder) WithMetrics(r prometheus.Registerer) {
 	b.listWatchMetrics = watch.NewListWatchMetrics(r)
 	b.shardingMetrics = sharding.NewShardingMetrics(r)
+	b.warmupMetrics = newWarmupMetrics(r)
+	b.namespaceReflectorMetrics = newNamespaceReflectorMetrics(r)
+	b.generationErrorMetrics = newGenerationErrorMetrics(r)
+	b.metadataSizeMetrics = newMetadataSizeMetrics(r)
+	b.storeSizeMetrics = newStoreSizeMetrics(r)
+	registerLabelKeyMetrics(r)
+}
+
+// Inventory returns a handler serving the enabled metric families as JSON.
+func (b *Builder) Inventory() http.Handler {
+	return b.inventory
+}
+
+// WithObjectInventory sets whether the cached objects are recorded for the
+// object inventory served by Objects.
+func (b *Builder) WithObjectInventory(enabled bool) {
+	switch {
+	case !enabled:
+		b.objectInventory = nil
+	case b.objectInventory == nil:
+		b.objectInventory = newObjectInventory()
+	}
+}
+
+// Objects returns a handler serving the cached objects as JSON, or nil if the
+// object inventory is disabled.
+func (b *Builder) Objects() http.Handler {
+	if b.objectInventory == nil {
+		return nil
+	}
+	return b.objectInventory
 }
 
 // WithEnabledResources sets the enabledResources property of a Builder.
@@ -122,14 +194,117 @@ func (b *Builder) WithEnabledResources(r []string) error {
 	return nil
 }
 
+// WithInitialListConcurrency sets the maximum number of resources performing
+// their initial list at the same time. Zero or less means no limit.
+func (b *Builder) WithInitialListConcurrency(c int) {
+	b.initialListConcurrency = c
+}
+
+// WithResyncPeriod sets the resync period of the reflectors, after which they
+// call Resync on their stores. Zero or less disables resyncs.
+func (b *Builder) WithResyncPeriod(d time.Duration) {
+	b.resyncPeriod = d
+}
+
+// WithRelistPeriod sets the period after which the reflectors are restarted,
+// which lists their objects again. Zero or less means the objects are only
+// relisted when the watch of a reflector failed.
+func (b *Builder) WithRelistPeriod(d time.Duration) {
+	b.relistPeriod = d
+}
+
+// WithInitialListOrder sets the resources whose initial list is started first,
+// in the given order. The remaining resources follow in alphabetical order.
+func (b *Builder) WithInitialListOrder(r []string) error {
+	for _, resource := range r {
+		if !resourceExists(resource) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", resource, strings.Join(availableResources(), ","))
+		}
+	}
+	b.initialListOrder = r
+	return nil
+}
+
 // WithFieldSelectorFilter sets the fieldSelector property of a Builder.
 func (b *Builder) WithFieldSelectorFilter(fieldSelectorFilter string) {
 	b.fieldSelectorFilter = fieldSelectorFilter
 }
 
-// WithNamespaces sets the namespaces property of a Builder.
+// WithResourceFieldSelectors configures additional field selectors of the
+// given resources, keyed by resource name. They are merged with the field
+// selector filter of the Builder.
+func (b *Builder) WithResourceFieldSelectors(selectors map[string]string) error {
+	for r, s := range selectors {
+		if !resourceExists(r) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", r, strings.Join(availableResources(), ","))
+		}
+		if _, err := fields.ParseSelector(s); err != nil {
+			return fmt.Errorf("invalid field selector %q of resource %s: %w", s, r, err)
+		}
+	}
+	b.resourceFieldSelectors = selectors
+	return nil
+}
+
+// storeFieldSelector returns the field selector of the stores being built.
+func (b *Builder) storeFieldSelector() string {
+	if b.resourceFieldSelector == "" {
+		return b.fieldSelectorFilter
+	}
+	merged, err := options.MergeTwoFieldSelectors(b.fieldSelectorFilter, b.resourceFieldSelector)
+	if err != nil {
+		panic(fmt.Sprintf("failed to merge field selectors: %v", err))
+	}
+	return merged
+}
+
+// WithObjectsLabelSelector configures the label selector the watched objects
+// have to match.
+func (b *Builder) WithObjectsLabelSelector(selector string) error {
+	if _, err := labels.Parse(selector); err != nil {
+		return fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+	b.objectsLabelSelector = selector
+	return nil
+}
+
+// WithResourceLabelSelectors configures the label selectors of the given
+// resources, keyed by resource name. They take precedence over the objects
+// label selector of the Builder.
+func (b *Builder) WithResourceLabelSelectors(selectors map[string]string) error {
+	for r, s := range selectors {
+		if !resourceExists(r) {
+			return fmt.Errorf("resource %s does not exist. Available resources: %s", r, strings.Join(availableResources(), ","))
+		}
+		if _, err := labels.Parse(s); err != nil {
+			return fmt.Errorf("invalid label selector %q of resource %s: %w", s, r, err)
+		}
+	}
+	b.resourceLabelSelectors = selectors
+	return nil
+}
+
+// storeLabelSelector returns the label selector of the stores being built.
+func (b *Builder) storeLabelSelector() string {
+	if b.resourceLabelSelector != "" {
+		return b.resourceLabelSelector
+	}
+	return b.objectsLabelSelector
+}
+
+// WithNamespaces sets the namespaces property of a Builder. Namespaces which
+// are given more than once are only watched once.
 func (b *Builder) WithNamespaces(n options.NamespaceList) {
-	b.namespaces = n
+	namespaces := make(options.NamespaceList, 0, len(n))
+	seen := make(map[string]struct{}, len(n))
+	for _, ns := range n {
+		if _, ok := seen[ns]; ok {
+			continue
+		}
+		seen[ns] = struct{}{}
+		namespaces = append(namespaces, ns)
+	}
+	b.namespaces = namespaces
 }
 
 // MergeFieldSelectors merges multiple fieldSelectors using AND operator.
@@ -147,6 +322,17 @@ func (b *Builder) WithSharding(shard int32, totalShards int) {
 	b.shardingMetrics.Total.Set(float64(totalShards))
 }
 
+// WithNamespacePatternFilter sets the namespacePatternFilter property of a Builder.
+func (b *Builder) WithNamespacePatternFilter(f *options.NamespaceFilter) {
+	b.namespacePatternFilter = f
+}
+
+// WithNamespaceRecreationTracking configures whether the reflectors of the
+// namespaces of the Builder are restarted when their namespace is recreated.
+func (b *Builder) WithNamespaceRecreationTracking(t bool) {
+	b.trackNamespaceRecreation = t
+}
+
 // WithContext sets the ctx property of a Builder.
 func (b *Builder) WithContext(ctx context.Context) {
 	b.ctx = ctx
@@ -157,9 +343,51 @@ func (b *Builder) WithKubeClient(c clientset.Interface) {
 	b.kubeClient = c
 }
 
-// WithCustomResourceClients sets the customResourceClients property of a Builder.
+// WithMetadataClient sets the metadataClient property of a Builder, which is
+// used to list and watch the resources set by WithMetadataOnlyResources.
+func (b *Builder) WithMetadataClient(c metadata.Interface) {
+	b.metadataClient = c
+}
+
+// WithMetadataOnlyResources configures the given resources to be listed and
+// watched as PartialObjectMetadata instead of full objects, which cuts the
+// memory of decoding large objects such as secrets. Families of the resources
+// which need more than the metadata of the objects are not generated.
+func (b *Builder) WithMetadataOnlyResources(r []string) error {
+	resources := make(map[string]struct{}, len(r))
+	for _, name := range r {
+		if _, ok := availableMetadataOnlyResources[name]; !ok {
+			return fmt.Errorf("resource %s cannot be watched as metadata only. Supported resources: %s", name, strings.Join(availableMetadataOnlyResourceNames(), ","))
+		}
+		resources[name] = struct{}{}
+	}
+	b.metadataOnlyResources = resources
+	return nil
+}
+
+// metadataOnlyResource returns the metadata-only description of the given
+// resource if it is watched as metadata only, or nil otherwise.
+func (b *Builder) metadataOnlyResource(name string) *metadataOnlyResource {
+	if _, ok := b.metadataOnlyResources[name]; !ok {
+		return nil
+	}
+	if b.metadataClient == nil {
+		klog.InfoS("No metadata client set, watching full objects", "resource", name)
+		return nil
+	}
+	r := availableMetadataOnlyResources[name]
+	return &r
+}
+
+// WithCustomResourceClients adds the given clients to the customResourceClients
+// property of a Builder.
 func (b *Builder) WithCustomResourceClients(cs map[string]interface{}) {
-	b.customResourceClients = cs
+	if b.customResourceClients == nil {
+		b.customResourceClients = make(map[string]interface{}, len(cs))
+	}
+	for gvr, c := range cs {
+		b.customResourceClients[gvr] = c
+	}
 }
 
 // WithUsingAPIServerCache configures whether using APIServer cache or not.
@@ -219,6 +447,19 @@ func (b *Builder) WithCustomResourceStoreFactories(fs ...customresource.Registry
 	}
 }
 
+// WithGenericResourceStoreFactories configures the stores of generic resources,
+// see customresource.NewGenericRegistryFactory. Unlike other custom resource
+// stores, they can't replace the store of a built-in resource.
+func (b *Builder) WithGenericResourceStoreFactories(fs ...customresource.RegistryFactory) error {
+	for _, f := range fs {
+		if slices.Contains(builtinResources, f.Name()) {
+			return fmt.Errorf("generic resource %s has a store of its own, remove it from the generic resources", f.Name())
+		}
+	}
+	b.WithCustomResourceStoreFactories(fs...)
+	return nil
+}
+
 // allowList validates the given map and checks if the resources exists.
 // If there is a '*' as key, return new map with all enabled resources.
 func (b *Builder) allowList(list map[string][]string) (map[string][]string, error) {
@@ -258,6 +499,59 @@ func (b *Builder) WithAllowLabels(labels map[string][]string) error {
 	return err
 }
 
+// WithMetricAliases configures the legacy names under which metric families
+// are exposed in addition to their own name, keyed by the family name.
+// Aliases which are the name of a family of a built-in resource are rejected.
+func (b *Builder) WithMetricAliases(aliases map[string]string) error {
+	var families map[string]struct{}
+	for name, alias := range aliases {
+		if !metricNameRE.MatchString(alias) {
+			return fmt.Errorf("invalid alias %q of metric %s", alias, name)
+		}
+		if _, ok := aliases[alias]; ok {
+			return fmt.Errorf("alias %q of metric %s is a metric with an alias itself", alias, name)
+		}
+		if families == nil {
+			families = builtinFamilyNames()
+		}
+		if _, ok := families[alias]; ok {
+			return fmt.Errorf("alias %q of metric %s is the name of an existing metric", alias, name)
+		}
+	}
+	b.metricAliases = aliases
+	return nil
+}
+
+// WithMetricTimestampInfo configures the timestamp families which are
+// additionally exposed as info families with an RFC3339 timestamp label.
+func (b *Builder) WithMetricTimestampInfo(families options.MetricSet) error {
+	for name := range families {
+		if !metricNameRE.MatchString(name + "_info") {
+			return fmt.Errorf("invalid timestamp metric %q", name)
+		}
+	}
+	b.metricTimestampInfo = families
+	return nil
+}
+
+// WithMetricLabelsDenylist configures the labels which are dropped from the
+// metrics of the given families, keyed by family name. The labels of the '*'
+// key are dropped from all families.
+func (b *Builder) WithMetricLabelsDenylist(denylist map[string][]string) error {
+	for name, labels := range denylist {
+		if name != generator.LabelsDenylistWildcard && !metricNameRE.MatchString(name) {
+			return fmt.Errorf("invalid metric %q in labels denylist", name)
+		}
+		for _, l := range labels {
+			if !labelNameRE.MatchString(l) {
+				return fmt.Errorf("invalid label %q of metric %s in labels denylist", l, name)
+			}
+		}
+	}
+	b.metricLabelsDenylist = denylist
+	return nil
+}
+
 // Build initializes and registers all enabled stores.
 // It returns metrics writers which can be used to write out
 // metrics from the stores.
@@ -269,18 +563,24 @@ func (b *Builder) Build() metricsstore.MetricsWriterList {
 	var metricsWriters metricsstore.MetricsWriterList
 	var activeStoreNames []string
 
-	for _, c := range b.enabledResources {
+	b.warmupGate = newWarmupGate(b.ctx, b.initialListConcurrency, b.warmupMetrics)
+	b.startNamespaceReflectors()
+	for _, c := range b.orderedResources() {
 		constructor, ok := availableStores[c]
 		if ok {
+			b.resourceFieldSelector = b.resourceFieldSelectors[c]
+			b.resourceLabelSelector = b.resourceLabelSelectors[c]
+			b.resourceMetadataOnly = b.metadataOnlyResource(c)
 			stores := cacheStoresToMetricStores(constructor(b))
 			activeStoreNames = append(activeStoreNames, c)
-			metricsWriters = append(metricsWriters, metricsstore.NewMetricsWriter(stores...))
+			metricsWriters = append(metricsWriters, metricsstore.NewResourceMetricsWriter(c, stores...))
 		}
 	}
 
 	if len(activeStoreNames) > 0 {
 		klog.InfoS("Active resources", "activeStoreNames", strings.Join(activeStoreNames, ","))
 	}
+	b.builtWarmupGate.Store(b.warmupGate)
 
 	return metricsWriters
 }
@@ -296,9 +596,14 @@ func (b *Builder) BuildStores() [][]cache.Store {
 	var allStores [][]cache.Store
 	var activeStoreNames []string
 
-	for _, c := range b.enabledResources {
+	b.warmupGate = newWarmupGate(b.ctx, b.initialListConcurrency, b.warmupMetrics)
+	b.startNamespaceReflectors()
+	for _, c := range b.orderedResources() {
 		constructor, ok := availableStores[c]
 		if ok {
+			b.resourceFieldSelector = b.resourceFieldSelectors[c]
+			b.resourceLabelSelector = b.resourceLabelSelectors[c]
+			b.resourceMetadataOnly = b.metadataOnlyResource(c)
 			stores := constructor(b)
 			activeStoreNames = append(activeStoreNames, c)
 			allStores = append(allStores, stores)
@@ -306,10 +611,58 @@ func (b *Builder) BuildStores() [][]cache.Store {
 	}
 
 	klog.InfoS("Active resources", "activeStoreNames", strings.Join(activeStoreNames, ","))
+	b.builtWarmupGate.Store(b.warmupGate)
 
 	return allStores
 }
 
+// Synced returns whether the stores of the last build populated the initial
+// list of their resource, i.e. whether they expose the complete state of the
+// cluster. It returns false until stores were built.
+func (b *Builder) Synced() bool {
+	g := b.builtWarmupGate.Load()
+	return g != nil && g.synced()
+}
+
+// startNamespaceReflectors starts watching the namespaces of the Builder, so
+// their reflectors are stopped on deletion and started again on recreation.
+func (b *Builder) startNamespaceReflectors() {
+	b.namespaceReflectors = nil
+	if !b.trackNamespaceRecreation || b.namespaces.IsAllNamespaces() {
+		return
+	}
+	b.namespaceReflectors = newNamespaceReflectors(b.ctx, b.namespaces, b.namespaceReflectorMetrics)
+	b.namespaceReflectors.watch(b.kubeClient)
+}
+
+// orderedResources returns the enabled resources in the order in which their
+// stores should be built, resources of the initial list order coming first.
+func (b *Builder) orderedResources() []string {
+	if len(b.initialListOrder) == 0 {
+		return b.enabledResources
+	}
+
+	enabled := make(map[string]struct{}, len(b.enabledResources))
+	for _, r := range b.enabledResources {
+		enabled[r] = struct{}{}
+	}
+
+	ordered := make([]string, 0, len(b.enabledResources))
+	for _, r := range b.initialListOrder {
+		if _, ok := enabled[r]; ok {
+			ordered = append(ordered, r)
+			delete(enabled, r)
+		}
+	}
+	for _, r := range b.enabledResources {
+		if _, ok := enabled[r]; ok {
+			ordered = append(ordered, r)
+			delete(enabled, r)
+		}
+	}
+	return ordered
+}
+
 var availableStores = map[string]func(f *Builder) []cache.Store{
 	"certificatesigningrequests":      func(b *Builder) []cache.Store { return b.buildCsrStores() },
 	"clusterroles":                    func(b *Builder) []cache.Store { return b.buildClusterRoleStores() },
@@ -348,11 +701,26 @@ var availableStores = map[string]func(f *Builder) []cache.Store{
 	"volumeattachments":               func(b *Builder) []cache.Store { return b.buildVolumeAttachmentStores() },
 }
 
+// builtinFamilyNames returns the names of the families of all built-in
+// resources, without building their stores.
+func builtinFamilyNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, families := range (&Builder{}).familyGenerators(availableResources()) {
+		for _, f := range families {
+			names[f.Name] = struct{}{}
+		}
+	}
+	return names
+}
+
 func resourceExists(name string) bool {
 	_, ok := availableStores[name]
 	return ok
 }
 
+// builtinResources are the resources with a store of their own.
+var builtinResources = availableResources()
+
 func availableResources() []string {
 	c := []string{}
 	for name := range availableStores {
@@ -362,143 +730,143 @@ func availableResources() []string {
 }
 
 func (b *Builder) buildConfigMapStores() []cache.Store {
-	return b.buildStoresFunc(configMapMetricFamilies(b.allowAnnotationsList["configmaps"], b.allowLabelsList["configmaps"]), &v1.ConfigMap{}, createConfigMapListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(configMapMetricFamilies(b.allowAnnotationsList["configmaps"], b.allowLabelsList["configmaps"]), "configmap", true, wrapConfigMapFunc), &v1.ConfigMap{}, createConfigMapListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildCronJobStores() []cache.Store {
-	return b.buildStoresFunc(cronJobMetricFamilies(b.allowAnnotationsList["cronjobs"], b.allowLabelsList["cronjobs"]), &batchv1.CronJob{}, createCronJobListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(cronJobMetricFamilies(b.allowAnnotationsList["cronjobs"], b.allowLabelsList["cronjobs"]), "cronjob", true, wrapCronJobFunc), &batchv1.CronJob{}, createCronJobListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildDaemonSetStores() []cache.Store {
-	return b.buildStoresFunc(daemonSetMetricFamilies(b.allowAnnotationsList["daemonsets"], b.allowLabelsList["daemonsets"]), &appsv1.DaemonSet{}, createDaemonSetListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(daemonSetMetricFamilies(b.allowAnnotationsList["daemonsets"], b.allowLabelsList["daemonsets"]), "daemonset", true, wrapDaemonSetFunc), &appsv1.DaemonSet{}, createDaemonSetListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildDeploymentStores() []cache.Store {
-	return b.buildStoresFunc(deploymentMetricFamilies(b.allowAnnotationsList["deployments"], b.allowLabelsList["deployments"]), &appsv1.Deployment{}, createDeploymentListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(deploymentMetricFamilies(b.allowAnnotationsList["deployments"], b.allowLabelsList["deployments"]), "deployment", true, wrapDeploymentFunc), &appsv1.Deployment{}, createDeploymentListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildEndpointsStores() []cache.Store {
-	return b.buildStoresFunc(endpointMetricFamilies(b.allowAnnotationsList["endpoints"], b.allowLabelsList["endpoints"]), &v1.Endpoints{}, createEndpointsListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(endpointMetricFamilies(b.allowAnnotationsList["endpoints"], b.allowLabelsList["endpoints"]), "endpoint", true, wrapEndpointFunc), &v1.Endpoints{}, createEndpointsListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildEndpointSlicesStores() []cache.Store {
-	return b.buildStoresFunc(endpointSliceMetricFamilies(b.allowAnnotationsList["endpointslices"], b.allowLabelsList["endpointslices"]), &discoveryv1.EndpointSlice{}, createEndpointSliceListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(endpointSliceMetricFamilies(b.allowAnnotationsList["endpointslices"], b.allowLabelsList["endpointslices"]), "endpointslice", true, wrapEndpointSliceFunc), &discoveryv1.EndpointSlice{}, createEndpointSliceListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildHPAStores() []cache.Store {
-	return b.buildStoresFunc(hpaMetricFamilies(b.allowAnnotationsList["horizontalpodautoscalers"], b.allowLabelsList["horizontalpodautoscalers"]), &autoscaling.HorizontalPodAutoscaler{}, createHPAListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(hpaMetricFamilies(b.allowAnnotationsList["horizontalpodautoscalers"], b.allowLabelsList["horizontalpodautoscalers"]), "horizontalpodautoscaler", true, wrapHPAFunc), &autoscaling.HorizontalPodAutoscaler{}, createHPAListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildIngressStores() []cache.Store {
-	return b.buildStoresFunc(ingressMetricFamilies(b.allowAnnotationsList["ingresses"], b.allowLabelsList["ingresses"]), &networkingv1.Ingress{}, createIngressListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(ingressMetricFamilies(b.allowAnnotationsList["ingresses"], b.allowLabelsList["ingresses"]), "ingress", true, wrapIngressFunc), &networkingv1.Ingress{}, createIngressListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildJobStores() []cache.Store {
-	return b.buildStoresFunc(jobMetricFamilies(b.allowAnnotationsList["jobs"], b.allowLabelsList["jobs"]), &batchv1.Job{}, createJobListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(jobMetricFamilies(b.allowAnnotationsList["jobs"], b.allowLabelsList["jobs"]), "job", false, wrapJobFunc), &batchv1.Job{}, createJobListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildLimitRangeStores() []cache.Store {
-	return b.buildStoresFunc(limitRangeMetricFamilies, &v1.LimitRange{}, createLimitRangeListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(limitRangeMetricFamilies, "limitrange", b.allowAnnotationsList["limitranges"], wrapLimitRangeFunc), "limitrange", true, wrapLimitRangeFunc), &v1.LimitRange{}, createLimitRangeListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildMutatingWebhookConfigurationStores() []cache.Store {
-	return b.buildStoresFunc(mutatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.MutatingWebhookConfiguration{}, createMutatingWebhookConfigurationListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(mutatingWebhookConfigurationMetricFamilies, "mutatingwebhookconfiguration", b.allowAnnotationsList["mutatingwebhookconfigurations"], wrapMutatingWebhookConfigurationFunc), "mutatingwebhookconfiguration", false, wrapMutatingWebhookConfigurationFunc), &admissionregistrationv1.MutatingWebhookConfiguration{}, createMutatingWebhookConfigurationListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildNamespaceStores() []cache.Store {
-	return b.buildStoresFunc(namespaceMetricFamilies(b.allowAnnotationsList["namespaces"], b.allowLabelsList["namespaces"]), &v1.Namespace{}, createNamespaceListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(namespaceMetricFamilies(b.allowAnnotationsList["namespaces"], b.allowLabelsList["namespaces"]), "namespace", false, wrapNamespaceFunc), &v1.Namespace{}, createNamespaceListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildNetworkPolicyStores() []cache.Store {
-	return b.buildStoresFunc(networkPolicyMetricFamilies(b.allowAnnotationsList["networkpolicies"], b.allowLabelsList["networkpolicies"]), &networkingv1.NetworkPolicy{}, createNetworkPolicyListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(networkPolicyMetricFamilies(b.allowAnnotationsList["networkpolicies"], b.allowLabelsList["networkpolicies"]), "networkpolicy", true, wrapNetworkPolicyFunc), &networkingv1.NetworkPolicy{}, createNetworkPolicyListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildNodeStores() []cache.Store {
-	return b.buildStoresFunc(nodeMetricFamilies(b.allowAnnotationsList["nodes"], b.allowLabelsList["nodes"]), &v1.Node{}, createNodeListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(nodeMetricFamilies(b.allowAnnotationsList["nodes"], b.allowLabelsList["nodes"]), "node", false, wrapNodeFunc), &v1.Node{}, createNodeListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildPersistentVolumeClaimStores() []cache.Store {
-	return b.buildStoresFunc(persistentVolumeClaimMetricFamilies(b.allowAnnotationsList["persistentvolumeclaims"], b.allowLabelsList["persistentvolumeclaims"]), &v1.PersistentVolumeClaim{}, createPersistentVolumeClaimListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(persistentVolumeClaimMetricFamilies(b.allowAnnotationsList["persistentvolumeclaims"], b.allowLabelsList["persistentvolumeclaims"]), "persistentvolumeclaim", true, wrapPersistentVolumeClaimFunc), &v1.PersistentVolumeClaim{}, createPersistentVolumeClaimListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildPersistentVolumeStores() []cache.Store {
-	return b.buildStoresFunc(persistentVolumeMetricFamilies(b.allowAnnotationsList["persistentvolumes"], b.allowLabelsList["persistentvolumes"]), &v1.PersistentVolume{}, createPersistentVolumeListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(persistentVolumeMetricFamilies(b.allowAnnotationsList["persistentvolumes"], b.allowLabelsList["persistentvolumes"]), "persistentvolume", false, wrapPersistentVolumeFunc), &v1.PersistentVolume{}, createPersistentVolumeListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildPodDisruptionBudgetStores() []cache.Store {
-	return b.buildStoresFunc(podDisruptionBudgetMetricFamilies(b.allowAnnotationsList["poddisruptionbudgets"], b.allowLabelsList["poddisruptionbudgets"]), &policyv1.PodDisruptionBudget{}, createPodDisruptionBudgetListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(podDisruptionBudgetMetricFamilies(b.allowAnnotationsList["poddisruptionbudgets"], b.allowLabelsList["poddisruptionbudgets"]), "poddisruptionbudget", true, wrapPodDisruptionBudgetFunc), &policyv1.PodDisruptionBudget{}, createPodDisruptionBudgetListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildReplicaSetStores() []cache.Store {
-	return b.buildStoresFunc(replicaSetMetricFamilies(b.allowAnnotationsList["replicasets"], b.allowLabelsList["replicasets"]), &appsv1.ReplicaSet{}, createReplicaSetListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(replicaSetMetricFamilies(b.allowAnnotationsList["replicasets"], b.allowLabelsList["replicasets"]), "replicaset", false, wrapReplicaSetFunc), &appsv1.ReplicaSet{}, createReplicaSetListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildReplicationControllerStores() []cache.Store {
-	return b.buildStoresFunc(replicationControllerMetricFamilies, &v1.ReplicationController{}, createReplicationControllerListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(replicationControllerMetricFamilies, "replicationcontroller", b.allowAnnotationsList["replicationcontrollers"], wrapReplicationControllerFunc), "replicationcontroller", false, wrapReplicationControllerFunc), &v1.ReplicationController{}, createReplicationControllerListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildResourceQuotaStores() []cache.Store {
-	return b.buildStoresFunc(resourceQuotaMetricFamilies(b.allowAnnotationsList["resourcequotas"], b.allowLabelsList["resourcequotas"]), &v1.ResourceQuota{}, createResourceQuotaListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(resourceQuotaMetricFamilies(b.allowAnnotationsList["resourcequotas"], b.allowLabelsList["resourcequotas"]), "resourcequota", true, wrapResourceQuotaFunc), &v1.ResourceQuota{}, createResourceQuotaListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildSecretStores() []cache.Store {
-	return b.buildStoresFunc(secretMetricFamilies(b.allowAnnotationsList["secrets"], b.allowLabelsList["secrets"]), &v1.Secret{}, createSecretListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(secretMetricFamilies(b.allowAnnotationsList["secrets"], b.allowLabelsList["secrets"]), "secret", false, wrapSecretFunc), &v1.Secret{}, createSecretListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildServiceAccountStores() []cache.Store {
-	return b.buildStoresFunc(serviceAccountMetricFamilies(b.allowAnnotationsList["serviceaccounts"], b.allowLabelsList["serviceaccounts"]), &v1.ServiceAccount{}, createServiceAccountListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(serviceAccountMetricFamilies(b.allowAnnotationsList["serviceaccounts"], b.allowLabelsList["serviceaccounts"]), "serviceaccount", true, wrapServiceAccountFunc), &v1.ServiceAccount{}, createServiceAccountListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildServiceStores() []cache.Store {
-	return b.buildStoresFunc(serviceMetricFamilies(b.allowAnnotationsList["services"], b.allowLabelsList["services"]), &v1.Service{}, createServiceListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(serviceMetricFamilies(b.allowAnnotationsList["services"], b.allowLabelsList["services"]), "service", true, wrapSvcFunc), &v1.Service{}, createServiceListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildStatefulSetStores() []cache.Store {
-	return b.buildStoresFunc(statefulSetMetricFamilies(b.allowAnnotationsList["statefulsets"], b.allowLabelsList["statefulsets"]), &appsv1.StatefulSet{}, createStatefulSetListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(statefulSetMetricFamilies(b.allowAnnotationsList["statefulsets"], b.allowLabelsList["statefulsets"]), "statefulset", true, wrapStatefulSetFunc), &appsv1.StatefulSet{}, createStatefulSetListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildStorageClassStores() []cache.Store {
-	return b.buildStoresFunc(storageClassMetricFamilies(b.allowAnnotationsList["storageclasses"], b.allowLabelsList["storageclasses"]), &storagev1.StorageClass{}, createStorageClassListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(storageClassMetricFamilies(b.allowAnnotationsList["storageclasses"], b.allowLabelsList["storageclasses"]), "storageclass", false, wrapStorageClassFunc), &storagev1.StorageClass{}, createStorageClassListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildPodStores() []cache.Store {
-	return b.buildStoresFunc(podMetricFamilies(b.allowAnnotationsList["pods"], b.allowLabelsList["pods"]), &v1.Pod{}, createPodListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(podMetricFamilies(b.allowAnnotationsList["pods"], b.allowLabelsList["pods"]), "pod", false, wrapPodFunc), &v1.Pod{}, createPodListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildCsrStores() []cache.Store {
-	return b.buildStoresFunc(csrMetricFamilies(b.allowAnnotationsList["certificatesigningrequests"], b.allowLabelsList["certificatesigningrequests"]), &certv1.CertificateSigningRequest{}, createCSRListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(csrMetricFamilies(b.allowAnnotationsList["certificatesigningrequests"], b.allowLabelsList["certificatesigningrequests"]), "certificatesigningrequest", false, wrapCSRFunc), &certv1.CertificateSigningRequest{}, createCSRListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildValidatingWebhookConfigurationStores() []cache.Store {
-	return b.buildStoresFunc(validatingWebhookConfigurationMetricFamilies, &admissionregistrationv1.ValidatingWebhookConfiguration{}, createValidatingWebhookConfigurationListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(validatingWebhookConfigurationMetricFamilies, "validatingwebhookconfiguration", b.allowAnnotationsList["validatingwebhookconfigurations"], wrapValidatingWebhookConfigurationFunc), "validatingwebhookconfiguration", false, wrapValidatingWebhookConfigurationFunc), &admissionregistrationv1.ValidatingWebhookConfiguration{}, createValidatingWebhookConfigurationListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildVolumeAttachmentStores() []cache.Store {
-	return b.buildStoresFunc(volumeAttachmentMetricFamilies, &storagev1.VolumeAttachment{}, createVolumeAttachmentListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(volumeAttachmentMetricFamilies, "volumeattachment", b.allowAnnotationsList["volumeattachments"], wrapVolumeAttachmentFunc), "volumeattachment", false, wrapVolumeAttachmentFunc), &storagev1.VolumeAttachment{}, createVolumeAttachmentListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildLeasesStores() []cache.Store {
-	return b.buildStoresFunc(leaseMetricFamilies, &coordinationv1.Lease{}, createLeaseListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(withAnnotationsFamily(leaseMetricFamilies, "lease", b.allowAnnotationsList["leases"], wrapLeaseFunc), "lease", false, wrapLeaseFunc), &coordinationv1.Lease{}, createLeaseListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildClusterRoleStores() []cache.Store {
-	return b.buildStoresFunc(clusterRoleMetricFamilies(b.allowAnnotationsList["clusterroles"], b.allowLabelsList["clusterroles"]), &rbacv1.ClusterRole{}, createClusterRoleListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(clusterRoleMetricFamilies(b.allowAnnotationsList["clusterroles"], b.allowLabelsList["clusterroles"]), "clusterrole", false, wrapClusterRoleFunc), &rbacv1.ClusterRole{}, createClusterRoleListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildRoleStores() []cache.Store {
-	return b.buildStoresFunc(roleMetricFamilies(b.allowAnnotationsList["roles"], b.allowLabelsList["roles"]), &rbacv1.Role{}, createRoleListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(roleMetricFamilies(b.allowAnnotationsList["roles"], b.allowLabelsList["roles"]), "role", true, wrapRoleFunc), &rbacv1.Role{}, createRoleListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildClusterRoleBindingStores() []cache.Store {
-	return b.buildStoresFunc(clusterRoleBindingMetricFamilies(b.allowAnnotationsList["clusterrolebindings"], b.allowLabelsList["clusterrolebindings"]), &rbacv1.ClusterRoleBinding{}, createClusterRoleBindingListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(clusterRoleBindingMetricFamilies(b.allowAnnotationsList["clusterrolebindings"], b.allowLabelsList["clusterrolebindings"]), "clusterrolebinding", false, wrapClusterRoleBindingFunc), &rbacv1.ClusterRoleBinding{}, createClusterRoleBindingListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildRoleBindingStores() []cache.Store {
-	return b.buildStoresFunc(roleBindingMetricFamilies(b.allowAnnotationsList["rolebindings"], b.allowLabelsList["rolebindings"]), &rbacv1.RoleBinding{}, createRoleBindingListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(roleBindingMetricFamilies(b.allowAnnotationsList["rolebindings"], b.allowLabelsList["rolebindings"]), "rolebinding", true, wrapRoleBindingFunc), &rbacv1.RoleBinding{}, createRoleBindingListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildIngressClassStores() []cache.Store {
-	return b.buildStoresFunc(ingressClassMetricFamilies(b.allowAnnotationsList["ingressclasses"], b.allowLabelsList["ingressclasses"]), &networkingv1.IngressClass{}, createIngressClassListWatch, b.useAPIServerCache)
+	return b.buildStoresFunc(withObjectMetaFamilies(ingressClassMetricFamilies(b.allowAnnotationsList["ingressclasses"], b.allowLabelsList["ingressclasses"]), "ingressclass", false, wrapIngressClassFunc), &networkingv1.IngressClass{}, createIngressClassListWatch, b.useAPIServerCache)
 }
 
 func (b *Builder) buildStores(
@@ -507,8 +875,22 @@ func (b *Builder) buildStores(
 	listWatchFunc func(kubeClient clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher,
 	useAPIServerCache bool,
 ) []cache.Store {
+	if r := b.resourceMetadataOnly; r != nil {
+		metricFamilies = r.filterFamilies(metricFamilies)
+		listWatchFunc = r.listWatchFunc(b.metadataClient)
+	}
+	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
+	metricFamilies = generator.LabelsDenylistFamilyGenerators(b.metricLabelsDenylist, metricFamilies)
+	metricFamilies = generator.AliasFamilyGenerators(b.metricAliases, metricFamilies)
+	// The aliases have to pass the filter by their own name as well.
 	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
-	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
+	metricFamilies = generator.TimestampInfoFamilyGenerators(b.metricTimestampInfo, metricFamilies)
+	recoverer := newGenerationErrorRecoverer(reflect.TypeOf(expectedType).String(), b.generationErrorMetrics)
+	metadataSize := newMetadataSizeTracker(reflect.TypeOf(expectedType).String(), metricFamilies, b.metadataSizeMetrics)
+	objectCount := newObjectCountTracker(reflect.TypeOf(expectedType).String(), b.storeSizeMetrics)
+	objects := b.objectInventory.register(reflect.TypeOf(expectedType).String())
+	inventory := b.inventory.register(reflect.TypeOf(expectedType).String(), metricFamilies)
+	composedMetricGenFuncs := inventory.wrap(metadataSize.wrap(recoverer.wrap(generator.ComposeMetricGenFuncs(metricFamilies))))
 	familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
 
 	if b.namespaces.IsAllNamespaces() {
@@ -516,11 +898,13 @@ func (b *Builder) buildStores(
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.InfoS("FieldSelector is used", "fieldSelector", b.fieldSelectorFilter)
+		fieldSelector := b.storeFieldSelector()
+		if fieldSelector != "" {
+			klog.InfoS("FieldSelector is used", "fieldSelector", fieldSelector)
 		}
-		listWatcher := listWatchFunc(b.kubeClient, v1.NamespaceAll, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(b.kubeClient, v1.NamespaceAll, fieldSelector)
+		b.startReflector(expectedType, objects.wrapStore(objectCount.wrapStore(metadataSize.wrapStore(store, v1.NamespaceAll), v1.NamespaceAll), v1.NamespaceAll), listWatcher, v1.NamespaceAll, useAPIServerCache)
+		b.registerStoreBytes(reflect.TypeOf(expectedType).String(), []cache.Store{store})
 		return []cache.Store{store}
 	}
 
@@ -530,14 +914,16 @@ func (b *Builder) buildStores(
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.InfoS("FieldSelector is used", "fieldSelector", b.fieldSelectorFilter)
+		fieldSelector := b.storeFieldSelector()
+		if fieldSelector != "" {
+			klog.InfoS("FieldSelector is used", "fieldSelector", fieldSelector)
 		}
-		listWatcher := listWatchFunc(b.kubeClient, ns, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(b.kubeClient, ns, fieldSelector)
+		b.startReflector(expectedType, objects.wrapStore(objectCount.wrapStore(metadataSize.wrapStore(store, ns), ns), ns), listWatcher, ns, useAPIServerCache)
 		stores = append(stores, store)
 	}
 
+	b.registerStoreBytes(reflect.TypeOf(expectedType).String(), stores)
 	return stores
 }
 
@@ -549,7 +935,16 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 	useAPIServerCache bool,
 ) []cache.Store {
 	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
-	composedMetricGenFuncs := generator.ComposeMetricGenFuncs(metricFamilies)
+	metricFamilies = generator.LabelsDenylistFamilyGenerators(b.metricLabelsDenylist, metricFamilies)
+	metricFamilies = generator.AliasFamilyGenerators(b.metricAliases, metricFamilies)
+	// The aliases have to pass the filter by their own name as well.
+	metricFamilies = generator.FilterFamilyGenerators(b.familyGeneratorFilter, metricFamilies)
+	metricFamilies = generator.TimestampInfoFamilyGenerators(b.metricTimestampInfo, metricFamilies)
+	recoverer := newGenerationErrorRecoverer(resourceName, b.generationErrorMetrics)
+	objectCount := newObjectCountTracker(resourceName, b.storeSizeMetrics)
+	objects := b.objectInventory.register(resourceName)
+	inventory := b.inventory.register(resourceName, metricFamilies)
+	composedMetricGenFuncs := inventory.wrap(recoverer.wrap(generator.ComposeMetricGenFuncs(metricFamilies)))
 
 	familyHeaders := generator.ExtractMetricFamilyHeaders(metricFamilies)
 
@@ -571,11 +966,13 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		if b.fieldSelectorFilter != "" {
-			klog.InfoS("FieldSelector is used", "fieldSelector", b.fieldSelectorFilter)
+		fieldSelector := b.storeFieldSelector()
+		if fieldSelector != "" {
+			klog.InfoS("FieldSelector is used", "fieldSelector", fieldSelector)
 		}
-		listWatcher := listWatchFunc(customResourceClient, v1.NamespaceAll, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		listWatcher := listWatchFunc(customResourceClient, v1.NamespaceAll, fieldSelector)
+		b.startReflector(expectedType, objects.wrapStore(objectCount.wrapStore(store, v1.NamespaceAll), v1.NamespaceAll), listWatcher, v1.NamespaceAll, useAPIServerCache)
+		b.registerStoreBytes(resourceName, []cache.Store{store})
 		return []cache.Store{store}
 	}
 
@@ -585,26 +982,92 @@ func (b *Builder) buildCustomResourceStores(resourceName string,
 			familyHeaders,
 			composedMetricGenFuncs,
 		)
-		klog.InfoS("FieldSelector is used", "fieldSelector", b.fieldSelectorFilter)
-		listWatcher := listWatchFunc(customResourceClient, ns, b.fieldSelectorFilter)
-		b.startReflector(expectedType, store, listWatcher, useAPIServerCache)
+		fieldSelector := b.storeFieldSelector()
+		klog.InfoS("FieldSelector is used", "fieldSelector", fieldSelector)
+		listWatcher := listWatchFunc(customResourceClient, ns, fieldSelector)
+		b.startReflector(expectedType, objects.wrapStore(objectCount.wrapStore(store, ns), ns), listWatcher, ns, useAPIServerCache)
 		stores = append(stores, store)
 	}
 
+	b.registerStoreBytes(resourceName, stores)
 	return stores
 }
 
+// registerStoreBytes registers the stores of the given resource for the
+// kube_state_metrics_store_bytes metric.
+func (b *Builder) registerStoreBytes(resource string, stores []cache.Store) {
+	if b.storeSizeMetrics == nil {
+		return
+	}
+	b.storeSizeMetrics.Bytes.register(resource, stores)
+}
+
+// runReflector runs a reflector of the given ListerWatcher until ctx is done.
+// With a relist period, the reflector is restarted after every period, so that
+// it lists all objects again.
+func (b *Builder) runReflector(ctx context.Context, lw cache.ListerWatcher, expectedType interface{}, s cache.Store) {
+	for {
+		reflector := cache.NewReflectorWithOptions(lw, expectedType, s, cache.ReflectorOptions{ResyncPeriod: b.resyncPeriod})
+		if b.relistPeriod <= 0 {
+			reflector.Run(ctx.Done())
+			return
+		}
+		relistCtx, cancel := context.WithTimeout(ctx, b.relistPeriod)
+		reflector.Run(relistCtx.Done())
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
 // startReflector starts a Kubernetes client-go reflector with the given
-// listWatcher and registers it with the given store.
+// listWatcher and registers it with the given store. Reflectors of a single
+// namespace are handed over to the namespace reflectors if namespace
+// recreation is tracked.
 func (b *Builder) startReflector(
 	expectedType interface{},
 	store cache.Store,
 	listWatcher cache.ListerWatcher,
+	ns string,
 	useAPIServerCache bool,
 ) {
-	instrumentedListWatch := watch.NewInstrumentedListerWatcher(listWatcher, b.listWatchMetrics, reflect.TypeOf(expectedType).String(), useAPIServerCache)
-	reflector := cache.NewReflectorWithOptions(sharding.NewShardedListWatch(b.shard, b.totalShards, instrumentedListWatch), expectedType, store, cache.ReflectorOptions{ResyncPeriod: 0})
-	go reflector.Run(b.ctx.Done())
+	resource := reflect.TypeOf(expectedType).String()
+	relistBackoffListWatch := newRelistBackoffListWatch(b.ctx, listWatcher)
+	labelSelectedListWatch := newLabelSelectedListWatch(b.storeLabelSelector(), relistBackoffListWatch)
+	instrumentedListWatch := watch.NewInstrumentedListerWatcher(labelSelectedListWatch, b.listWatchMetrics, resource, ns, useAPIServerCache)
+	filteredListWatch := newNamespaceFilteredListWatch(b.namespacePatternFilter, instrumentedListWatch)
+	shardedListWatch := sharding.NewShardedListWatch(b.shard, b.totalShards, filteredListWatch)
+	// The reflectors of a namespace are stopped with the context of the
+	// namespace once it is deleted, which ends their wait for a warmup slot
+	// and their relist backoff.
+	ctx := b.ctx
+	if b.namespaceReflectors != nil && ns != v1.NamespaceAll {
+		ctx = b.namespaceReflectors.context(ns)
+	}
+	b.warmupGate.run(ctx, resource, store, shardedListWatch, func(s cache.Store, lw cache.ListerWatcher, skip func()) {
+		run := func(ctx context.Context) <-chan struct{} {
+			relistBackoffListWatch.setContext(ctx)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				b.runReflector(ctx, lw, expectedType, s)
+				// A reflector stopped before its initial list was populated
+				// must not hold its warmup slot or readiness back.
+				skip()
+			}()
+			return done
+		}
+		if b.namespaceReflectors != nil && ns != v1.NamespaceAll {
+			if !b.namespaceReflectors.add(ns, s, run) {
+				// The namespace is deleted, there is nothing to list until
+				// it is recreated.
+				skip()
+			}
+			return
+		}
+		run(b.ctx)
+	})
 }
 
 // cacheStoresToMetricStores converts []cache.Store into []*metricsstore.MetricsStore
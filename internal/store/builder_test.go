@@ -19,7 +19,14 @@ package store
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -196,3 +203,295 @@ func TestWithAllowAnnotations(t *testing.T) {
 		}
 	}
 }
+
+func TestWithMetricAliases(t *testing.T) {
+	tests := []struct {
+		Desc    string
+		Aliases map[string]string
+		Wanted  []string
+		WantErr bool
+	}{
+		{
+			Desc:    "no aliases",
+			Aliases: nil,
+			Wanted:  []string{"kube_pod_info", "kube_pod_restart_policy"},
+		},
+		{
+			Desc:    "aliased family",
+			Aliases: map[string]string{"kube_pod_restart_policy": "kube_pod_restartpolicy", "kube_pod_foo": "kube_pod_bar"},
+			Wanted:  []string{"kube_pod_info", "kube_pod_restart_policy", "kube_pod_restartpolicy"},
+		},
+		{
+			Desc:    "invalid alias",
+			Aliases: map[string]string{"kube_pod_restart_policy": "kube-pod-restartpolicy"},
+			WantErr: true,
+		},
+		{
+			Desc:    "chained alias",
+			Aliases: map[string]string{"kube_pod_restart_policy": "kube_pod_info", "kube_pod_info": "kube_pod_information"},
+			WantErr: true,
+		},
+		{
+			Desc:    "alias of an existing family",
+			Aliases: map[string]string{"kube_pod_restart_policy": "kube_deployment_created"},
+			WantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		b := NewBuilder()
+		err := b.WithMetricAliases(test.Aliases)
+		if (err != nil) != test.WantErr {
+			t.Errorf("Test error for Desc: %s. Want error: %t. Got Error: %v", test.Desc, test.WantErr, err)
+			continue
+		}
+		if test.WantErr {
+			continue
+		}
+
+		families := generator.AliasFamilyGenerators(b.metricAliases, []generator.FamilyGenerator{
+			createPodInfoFamilyGenerator(),
+			createPodRestartPolicyFamilyGenerator(),
+		})
+		var got []string
+		for _, f := range families {
+			got = append(got, f.Name)
+		}
+		if !reflect.DeepEqual(got, test.Wanted) {
+			t.Errorf("Test error for Desc: %s\n Want: \n%+v\n Got: \n%+v", test.Desc, test.Wanted, got)
+		}
+	}
+}
+
+func TestAliasFamilyGeneratorsSkipsExistingFamilies(t *testing.T) {
+	families := generator.AliasFamilyGenerators(map[string]string{"kube_pod_restart_policy": "kube_pod_info"}, []generator.FamilyGenerator{
+		createPodInfoFamilyGenerator(),
+		createPodRestartPolicyFamilyGenerator(),
+	})
+	var got []string
+	for _, f := range families {
+		got = append(got, f.Name)
+	}
+	if want := []string{"kube_pod_info", "kube_pod_restart_policy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Want: \n%+v\n Got: \n%+v", want, got)
+	}
+}
+
+func TestWithMetricTimestampInfo(t *testing.T) {
+	b := NewBuilder()
+	if err := b.WithMetricTimestampInfo(options.MetricSet{"kube_pod_created": {}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewBuilder().WithMetricTimestampInfo(options.MetricSet{"kube-pod-created": {}}); err == nil {
+		t.Fatal("expected error for invalid metric name")
+	}
+
+	families := generator.TimestampInfoFamilyGenerators(b.metricTimestampInfo, []generator.FamilyGenerator{
+		createPodInfoFamilyGenerator(),
+		createPodCreatedFamilyGenerator(),
+	})
+
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:              "pod1",
+		Namespace:         "ns1",
+		UID:               "uid1",
+		CreationTimestamp: metav1.Time{Time: time.Unix(1500000000, 0)},
+	}}
+	test := generateMetricsTestCase{
+		Obj: pod,
+		Want: `
+			# HELP kube_pod_created [STABLE] Unix creation timestamp
+			# HELP kube_pod_created_info Timestamp of kube_pod_created in RFC3339 format.
+			# TYPE kube_pod_created gauge
+			# TYPE kube_pod_created_info gauge
+			kube_pod_created{namespace="ns1",pod="pod1",uid="uid1"} 1.5e+09
+			kube_pod_created_info{namespace="ns1",pod="pod1",timestamp="2017-07-14T02:40:00Z",uid="uid1"} 1
+`,
+		MetricNames: []string{"kube_pod_created"},
+		Func:        generator.ComposeMetricGenFuncs(families),
+		Headers:     generator.ExtractMetricFamilyHeaders(families),
+	}
+	if err := test.run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithGenericResourceStoreFactories(t *testing.T) {
+	pods := customresource.NewGenericRegistryFactory(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, nil, nil)
+	if err := NewBuilder().WithGenericResourceStoreFactories(pods); err == nil {
+		t.Fatal("expected error for a generic resource with a store of its own")
+	}
+	if _, ok := availableStores["pods"]; !ok {
+		t.Fatal("expected the store of pods to be kept")
+	}
+}
+
+func TestWithResourceFieldSelectors(t *testing.T) {
+	tests := []struct {
+		Desc      string
+		Selectors map[string]string
+		Resource  string
+		Wanted    string
+		WantErr   bool
+	}{
+		{
+			Desc:     "no resource field selectors",
+			Resource: "pods",
+			Wanted:   "metadata.namespace!=kube-system",
+		},
+		{
+			Desc:      "field selector of another resource",
+			Selectors: map[string]string{"jobs": "status.successful=0"},
+			Resource:  "pods",
+			Wanted:    "metadata.namespace!=kube-system",
+		},
+		{
+			Desc:      "field selector of the resource",
+			Selectors: map[string]string{"pods": "status.phase!=Succeeded"},
+			Resource:  "pods",
+			Wanted:    "metadata.namespace!=kube-system,status.phase!=Succeeded",
+		},
+		{
+			Desc:      "unknown resource",
+			Selectors: map[string]string{"foo": "status.phase!=Succeeded"},
+			WantErr:   true,
+		},
+		{
+			Desc:      "invalid field selector",
+			Selectors: map[string]string{"pods": "status.phase"},
+			WantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		b := NewBuilder()
+		b.WithFieldSelectorFilter("metadata.namespace!=kube-system")
+		err := b.WithResourceFieldSelectors(test.Selectors)
+		if (err != nil) != test.WantErr {
+			t.Errorf("Test error for Desc: %s. Want error: %t. Got Error: %v", test.Desc, test.WantErr, err)
+			continue
+		}
+		if test.WantErr {
+			continue
+		}
+
+		b.resourceFieldSelector = b.resourceFieldSelectors[test.Resource]
+		if got := b.storeFieldSelector(); got != test.Wanted {
+			t.Errorf("Test error for Desc: %s. Want: %q. Got: %q", test.Desc, test.Wanted, got)
+		}
+	}
+}
+
+func TestWithResourceLabelSelectors(t *testing.T) {
+	tests := []struct {
+		Desc      string
+		Selectors map[string]string
+		Resource  string
+		Wanted    string
+		WantErr   bool
+	}{
+		{
+			Desc:     "no resource label selectors",
+			Resource: "pods",
+			Wanted:   "tenant=team-a",
+		},
+		{
+			Desc:      "label selector of the resource",
+			Selectors: map[string]string{"pods": "tenant in (team-a,team-b)"},
+			Resource:  "pods",
+			Wanted:    "tenant in (team-a,team-b)",
+		},
+		{
+			Desc:      "unknown resource",
+			Selectors: map[string]string{"foo": "tenant=team-a"},
+			WantErr:   true,
+		},
+		{
+			Desc:      "invalid label selector",
+			Selectors: map[string]string{"pods": "tenant in team-a"},
+			WantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		b := NewBuilder()
+		if err := b.WithObjectsLabelSelector("tenant=team-a"); err != nil {
+			t.Fatal(err)
+		}
+		err := b.WithResourceLabelSelectors(test.Selectors)
+		if (err != nil) != test.WantErr {
+			t.Errorf("Test error for Desc: %s. Want error: %t. Got Error: %v", test.Desc, test.WantErr, err)
+			continue
+		}
+		if test.WantErr {
+			continue
+		}
+
+		b.resourceLabelSelector = b.resourceLabelSelectors[test.Resource]
+		if got := b.storeLabelSelector(); got != test.Wanted {
+			t.Errorf("Test error for Desc: %s. Want: %q. Got: %q", test.Desc, test.Wanted, got)
+		}
+	}
+
+	if err := NewBuilder().WithObjectsLabelSelector("tenant in team-a"); err == nil {
+		t.Fatal("expected error for invalid objects label selector")
+	}
+}
+
+func TestWithMetricLabelsDenylist(t *testing.T) {
+	b := NewBuilder()
+	if err := b.WithMetricLabelsDenylist(map[string][]string{
+		"kube_pod_created": {"uid"},
+		"*":                {"namespace"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewBuilder().WithMetricLabelsDenylist(map[string][]string{"kube-pod-created": {"uid"}}); err == nil {
+		t.Fatal("expected error for invalid metric name")
+	}
+	if err := NewBuilder().WithMetricLabelsDenylist(map[string][]string{"kube_pod_created": {"pod-uid"}}); err == nil {
+		t.Fatal("expected error for invalid label name")
+	}
+
+	families := generator.LabelsDenylistFamilyGenerators(b.metricLabelsDenylist, []generator.FamilyGenerator{
+		createPodCreatedFamilyGenerator(),
+		createPodRestartPolicyFamilyGenerator(),
+	})
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "pod1",
+			Namespace:         "ns1",
+			UID:               "uid1",
+			CreationTimestamp: metav1.Time{Time: time.Unix(1500000000, 0)},
+		},
+		Spec: v1.PodSpec{
+			RestartPolicy: v1.RestartPolicyAlways,
+		},
+	}
+	test := generateMetricsTestCase{
+		Obj: pod,
+		Want: `
+			# HELP kube_pod_created [STABLE] Unix creation timestamp
+			# HELP kube_pod_restart_policy [STABLE] Describes the restart policy in use by this pod.
+			# TYPE kube_pod_created gauge
+			# TYPE kube_pod_restart_policy gauge
+			kube_pod_created{pod="pod1"} 1.5e+09
+			kube_pod_restart_policy{pod="pod1",type="Always",uid="uid1"} 1
+`,
+		Func:    generator.ComposeMetricGenFuncs(families),
+		Headers: generator.ExtractMetricFamilyHeaders(families),
+	}
+	if err := test.run(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithNamespacesDeduplicates(t *testing.T) {
+	b := NewBuilder()
+	b.WithNamespaces(options.NamespaceList{"ns1", "ns2", "ns1"})
+
+	if !reflect.DeepEqual(b.namespaces, options.NamespaceList{"ns1", "ns2"}) {
+		t.Errorf("expected each namespace once, got %v", b.namespaces)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// CatalogFamily describes a metric family of a built-in resource in the
+// metric catalog.
+type CatalogFamily struct {
+	Name              string `json:"name"`
+	Help              string `json:"help"`
+	Type              string `json:"type"`
+	Stability         string `json:"stability"`
+	DeprecatedVersion string `json:"deprecatedVersion,omitempty"`
+	OptIn             bool   `json:"optIn"`
+	Resource          string `json:"resource"`
+}
+
+// Catalog returns the metric families of all built-in resources, including
+// the opt-in ones, sorted by resource, without building their stores.
+func Catalog() []CatalogFamily {
+	resources := availableResources()
+	sort.Strings(resources)
+	generators := (&Builder{}).familyGenerators(resources)
+
+	var families []CatalogFamily
+	for _, resource := range resources {
+		for _, f := range generators[resource] {
+			families = append(families, catalogFamily(resource, f))
+		}
+	}
+	return families
+}
+
+// FamilyGenerators returns the metric families of the enabled built-in
+// resources by resource, before they are filtered, without building their
+// stores.
+func (b *Builder) FamilyGenerators() map[string][]generator.FamilyGenerator {
+	return b.familyGenerators(b.enabledResources)
+}
+
+// familyGenerators returns the unfiltered metric families of the given
+// built-in resources by resource. It captures the families the store builder
+// of each resource passes to buildStoresFunc instead of building the stores.
+// Resources which are not built-in are skipped.
+func (b *Builder) familyGenerators(resources []string) map[string][]generator.FamilyGenerator {
+	families := map[string][]generator.FamilyGenerator{}
+	var resource string
+	fb := &Builder{
+		allowAnnotationsList: b.allowAnnotationsList,
+		allowLabelsList:      b.allowLabelsList,
+		buildStoresFunc: func(metricFamilies []generator.FamilyGenerator, _ interface{}, _ func(clientset.Interface, string, string) cache.ListerWatcher, _ bool) []cache.Store {
+			families[resource] = metricFamilies
+			return nil
+		},
+	}
+
+	for _, resource = range resources {
+		if build, ok := availableStores[resource]; ok {
+			build(fb)
+		}
+	}
+	return families
+}
+
+func catalogFamily(resource string, f generator.FamilyGenerator) CatalogFamily {
+	stability := "EXPERIMENTAL"
+	if f.StabilityLevel == basemetrics.STABLE {
+		stability = "STABLE"
+	}
+	return CatalogFamily{
+		Name:              f.Name,
+		Help:              f.Help,
+		Type:              string(f.Type),
+		Stability:         stability,
+		DeprecatedVersion: f.DeprecatedVersion,
+		OptIn:             f.OptIn,
+		Resource:          resource,
+	}
+}
+
+// WriteCatalog writes the given metric families to w as a JSON array if
+// format is json, or as a Markdown table per resource if it is markdown.
+func WriteCatalog(w io.Writer, format string, families []CatalogFamily) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(families)
+	case "markdown":
+		return writeCatalogMarkdown(w, families)
+	default:
+		return fmt.Errorf("unknown catalog format %q, must be one of json or markdown", format)
+	}
+}
+
+func writeCatalogMarkdown(w io.Writer, families []CatalogFamily) error {
+	var resource string
+	for _, f := range families {
+		if f.Resource != resource {
+			if resource != "" {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			resource = f.Resource
+			if _, err := fmt.Fprintf(w, "## %s\n\n| Metric name | Metric type | Description | Status | Opt-in |\n| ----------- | ----------- | ----------- | ------ | ------ |\n", resource); err != nil {
+				return err
+			}
+		}
+
+		optIn := "-"
+		if f.OptIn {
+			optIn = "Opt-in"
+		}
+		status := f.Stability
+		if f.DeprecatedVersion != "" {
+			status = "DEPRECATED"
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n", f.Name, typeName(f.Type), strings.ReplaceAll(f.Help, "|", `\|`), status, optIn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeName returns the given metric type as it is written in the docs, e.g.
+// Gauge.
+func typeName(t string) string {
+	if t == "" {
+		return t
+	}
+	return strings.ToUpper(t[:1]) + t[1:]
+}
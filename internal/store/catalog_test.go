@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	families := Catalog()
+
+	byName := map[string]CatalogFamily{}
+	for _, f := range families {
+		byName[f.Name] = f
+	}
+	if f, ok := byName["kube_pod_info"]; !ok || f.Resource != "pods" || f.Stability != "STABLE" || f.Type != "gauge" || f.OptIn {
+		t.Errorf("unexpected catalog entry for kube_pod_info: %+v", f)
+	}
+	if f, ok := byName["kube_configmap_owner"]; !ok || !f.OptIn || f.Stability != "EXPERIMENTAL" {
+		t.Errorf("unexpected catalog entry for kube_configmap_owner: %+v", f)
+	}
+	for i := 1; i < len(families); i++ {
+		if families[i-1].Resource > families[i].Resource {
+			t.Fatalf("expected the families to be sorted by resource, got %s before %s", families[i-1].Resource, families[i].Resource)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := WriteCatalog(buf, "markdown", families); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "## pods\n") || !strings.Contains(buf.String(), "| kube_pod_info | Gauge | Information about pod. | STABLE | - |\n") {
+		t.Errorf("unexpected markdown catalog:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteCatalog(buf, "json", families); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []CatalogFamily
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(families) {
+		t.Errorf("expected %d families in the json catalog, got %d", len(families), len(decoded))
+	}
+
+	if err := WriteCatalog(buf, "yaml", families); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
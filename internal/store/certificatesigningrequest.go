@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strconv"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -137,6 +138,29 @@ func csrMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_certificatesigningrequest_info",
+			"Information about the requestor and the requested duration of the certificatesigningrequest.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCSRFunc(func(csr *certv1.CertificateSigningRequest) *metric.Family {
+				expirationSeconds := ""
+				if csr.Spec.ExpirationSeconds != nil {
+					expirationSeconds = strconv.FormatInt(int64(*csr.Spec.ExpirationSeconds), 10)
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"username", "expiration_seconds"},
+							LabelValues: []string{csr.Spec.Username, expirationSeconds},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 
 	certv1 "k8s.io/api/certificates/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -219,6 +220,41 @@ func TestCsrStore(t *testing.T) {
 `,
 			MetricNames: []string{"kube_certificatesigningrequest_created", "kube_certificatesigningrequest_condition", "kube_certificatesigningrequest_labels", "kube_certificatesigningrequest_cert_length"},
 		},
+		{
+			Obj: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "certificate-test",
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					SignerName:        "kubernetes.io/kube-apiserver-client-kubelet",
+					Username:          "system:node:node1",
+					ExpirationSeconds: ptr.To[int32](86400),
+				},
+			},
+			Want: `
+				# HELP kube_certificatesigningrequest_info Information about the requestor and the requested duration of the certificatesigningrequest.
+				# TYPE kube_certificatesigningrequest_info gauge
+				kube_certificatesigningrequest_info{certificatesigningrequest="certificate-test",signer_name="kubernetes.io/kube-apiserver-client-kubelet",username="system:node:node1",expiration_seconds="86400"} 1
+`,
+			MetricNames: []string{"kube_certificatesigningrequest_info"},
+		},
+		{
+			Obj: &certv1.CertificateSigningRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "certificate-test",
+				},
+				Spec: certv1.CertificateSigningRequestSpec{
+					SignerName: "signer",
+					Username:   "admin",
+				},
+			},
+			Want: `
+				# HELP kube_certificatesigningrequest_info Information about the requestor and the requested duration of the certificatesigningrequest.
+				# TYPE kube_certificatesigningrequest_info gauge
+				kube_certificatesigningrequest_info{certificatesigningrequest="certificate-test",signer_name="signer",username="admin",expiration_seconds=""} 1
+`,
+			MetricNames: []string{"kube_certificatesigningrequest_info"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(csrMetricFamilies(nil, nil))
@@ -131,6 +131,58 @@ func configMapMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 				}
 			}),
 		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_configmap_object_size_bytes",
+			"The size in bytes of the configmap as serialized by the API server.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+				return &metric.Family{
+					Metrics: objectSizeMetrics(c),
+				}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_configmap_data_bytes",
+			"The total size in bytes of the values in the data and binaryData of the configmap.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+				size := 0
+				for _, v := range c.Data {
+					size += len(v)
+				}
+				for _, v := range c.BinaryData {
+					size += len(v)
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(size),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_configmap_data_keys",
+			"The number of keys in the data and binaryData of the configmap.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapConfigMapFunc(func(c *v1.ConfigMap) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(len(c.Data) + len(c.BinaryData)),
+						},
+					},
+				}
+			}),
+		),
 	}
 }
 
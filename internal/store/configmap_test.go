@@ -94,6 +94,47 @@ func TestConfigMapStore(t *testing.T) {
 				`,
 			MetricNames: []string{"kube_configmap_info", "kube_configmap_created", "kube_configmap_metadata_resource_version"},
 		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap3",
+					Namespace: "ns3",
+				},
+				Data: map[string]string{
+					"config.yaml": "replicas: 3",
+				},
+			},
+			Want: `
+				# HELP kube_configmap_object_size_bytes The size in bytes of the configmap as serialized by the API server.
+				# TYPE kube_configmap_object_size_bytes gauge
+				kube_configmap_object_size_bytes{configmap="configmap3",namespace="ns3"} 59
+`,
+			MetricNames: []string{"kube_configmap_object_size_bytes"},
+		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap4",
+					Namespace: "ns4",
+				},
+				Data: map[string]string{
+					"config.yaml": "replicas: 3",
+					"empty":       "",
+				},
+				BinaryData: map[string][]byte{
+					"cert.der": {0x30, 0x82, 0x01, 0x0a},
+				},
+			},
+			Want: `
+				# HELP kube_configmap_data_bytes The total size in bytes of the values in the data and binaryData of the configmap.
+				# HELP kube_configmap_data_keys The number of keys in the data and binaryData of the configmap.
+				# TYPE kube_configmap_data_bytes gauge
+				# TYPE kube_configmap_data_keys gauge
+				kube_configmap_data_bytes{configmap="configmap4",namespace="ns4"} 15
+				kube_configmap_data_keys{configmap="configmap4",namespace="ns4"} 3
+`,
+			MetricNames: []string{"kube_configmap_data_bytes", "kube_configmap_data_keys"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(configMapMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
@@ -30,6 +30,7 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	basemetrics "k8s.io/component-base/metrics"
+	"k8s.io/klog/v2"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
@@ -235,6 +236,28 @@ func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_cronjob_spec_timezone",
+			"The time zone in which the schedule of the cronjob is evaluated.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapCronJobFunc(func(j *batchv1.CronJob) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Spec.TimeZone != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"timezone"},
+						LabelValues: []string{*j.Spec.TimeZone},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_cronjob_next_schedule_time",
 			"Next time the cronjob should be scheduled. The time after lastScheduleTime, or after the cron job's creation time if it's never been scheduled. Use this to determine if the job is delayed.",
@@ -245,9 +268,11 @@ func cronJobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				ms := []*metric.Metric{}
 
 				// If the cron job is suspended, don't track the next scheduled time
-				nextScheduledTime, err := getNextScheduledTime(j.Spec.Schedule, j.Status.LastScheduleTime, j.CreationTimestamp)
+				nextScheduledTime, err := getNextScheduledTime(j.Spec.Schedule, j.Spec.TimeZone, j.Status.LastScheduleTime, j.CreationTimestamp)
 				if err != nil {
-					panic(err)
+					// An invalid schedule or an unknown time zone must not
+					// drop the other metrics of the cron job.
+					klog.ErrorS(err, "Failed to get the next schedule time of cron job", "cronjob", klog.KObj(j))
 				} else if !*j.Spec.Suspend {
 					ms = append(ms, &metric.Metric{
 						LabelKeys:   []string{},
@@ -347,7 +372,12 @@ func createCronJobListWatch(kubeClient clientset.Interface, ns string, fieldSele
 	}
 }
 
-func getNextScheduledTime(schedule string, lastScheduleTime *metav1.Time, createdTime metav1.Time) (time.Time, error) {
+func getNextScheduledTime(schedule string, timeZone *string, lastScheduleTime *metav1.Time, createdTime metav1.Time) (time.Time, error) {
+	// The schedule of a cron job with a time zone is evaluated in that time
+	// zone, the same way the cronjob controller does.
+	if timeZone != nil {
+		schedule = fmt.Sprintf("CRON_TZ=%s %s", *timeZone, schedule)
+	}
 	sched, err := cron.ParseStandard(schedule)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("Failed to parse cron job schedule '%s': %w", schedule, err)
@@ -35,6 +35,8 @@ var (
 	StartingDeadlineSeconds300 int64 = 300
 	SuccessfulJobHistoryLimit3 int32 = 3
 	FailedJobHistoryLimit1     int32 = 1
+	TimeZoneUTC                      = "UTC"
+	TimeZoneUnknown                  = "Mars/Olympus_Mons"
 
 	// "1520742896" is "2018/3/11 12:34:56" in "Asia/Shanghai".
 	ActiveRunningCronJob1LastScheduleTime          = time.Unix(1520742896, 0)
@@ -366,6 +368,56 @@ func TestCronJobStore(t *testing.T) {
 					float64(ActiveCronJob1NoLastScheduledNextScheduleTime.Unix())/math.Pow10(9)),
 			MetricNames: []string{"kube_cronjob_status_last_successful_time", "kube_cronjob_next_schedule_time", "kube_cronjob_spec_starting_deadline_seconds", "kube_cronjob_status_active", "kube_cronjob_metadata_resource_version", "kube_cronjob_spec_suspend", "kube_cronjob_info", "kube_cronjob_created", "kube_cronjob_labels", "kube_cronjob_spec_successful_job_history_limit", "kube_cronjob_spec_failed_job_history_limit"},
 		},
+		{
+			Obj: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ActiveCronJob1WithTimeZone",
+					Namespace: "ns1",
+				},
+				Status: batchv1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: ActiveRunningCronJob1LastScheduleTime},
+				},
+				Spec: batchv1.CronJobSpec{
+					Suspend:  &SuspendFalse,
+					Schedule: "0 */6 * * *",
+					TimeZone: &TimeZoneUTC,
+				},
+			},
+			// "1520748000" is "2018/3/11 06:00:00" in "UTC", regardless of the local time zone.
+			Want: `
+				# HELP kube_cronjob_next_schedule_time [STABLE] Next time the cronjob should be scheduled. The time after lastScheduleTime, or after the cron job's creation time if it's never been scheduled. Use this to determine if the job is delayed.
+				# HELP kube_cronjob_spec_timezone The time zone in which the schedule of the cronjob is evaluated.
+				# TYPE kube_cronjob_next_schedule_time gauge
+				# TYPE kube_cronjob_spec_timezone gauge
+				kube_cronjob_next_schedule_time{cronjob="ActiveCronJob1WithTimeZone",namespace="ns1"} 1.520748e+09
+				kube_cronjob_spec_timezone{cronjob="ActiveCronJob1WithTimeZone",namespace="ns1",timezone="UTC"} 1
+`,
+			MetricNames: []string{"kube_cronjob_next_schedule_time", "kube_cronjob_spec_timezone"},
+		},
+		{
+			Obj: &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ActiveCronJob1WithUnknownTimeZone",
+					Namespace: "ns1",
+				},
+				Status: batchv1.CronJobStatus{
+					LastScheduleTime: &metav1.Time{Time: ActiveRunningCronJob1LastScheduleTime},
+				},
+				Spec: batchv1.CronJobSpec{
+					Suspend:  &SuspendFalse,
+					Schedule: "0 */6 * * *",
+					TimeZone: &TimeZoneUnknown,
+				},
+			},
+			Want: `
+				# HELP kube_cronjob_next_schedule_time [STABLE] Next time the cronjob should be scheduled. The time after lastScheduleTime, or after the cron job's creation time if it's never been scheduled. Use this to determine if the job is delayed.
+				# HELP kube_cronjob_spec_timezone The time zone in which the schedule of the cronjob is evaluated.
+				# TYPE kube_cronjob_next_schedule_time gauge
+				# TYPE kube_cronjob_spec_timezone gauge
+				kube_cronjob_spec_timezone{cronjob="ActiveCronJob1WithUnknownTimeZone",namespace="ns1",timezone="Mars/Olympus_Mons"} 1
+`,
+			MetricNames: []string{"kube_cronjob_next_schedule_time", "kube_cronjob_spec_timezone"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(cronJobMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
@@ -22,6 +22,7 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -223,6 +224,80 @@ func daemonSetMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_daemonset_spec_strategy",
+			"The strategy used to replace old pods by new ones of a daemonset.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapDaemonSetFunc(func(d *v1.DaemonSet) *metric.Family {
+				if d.Spec.UpdateStrategy.Type == "" {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"type"},
+							LabelValues: []string{string(d.Spec.UpdateStrategy.Type)},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_daemonset_spec_strategy_rollingupdate_max_unavailable",
+			"Maximum number of nodes with an unavailable daemon pod during a rolling update of a daemonset.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapDaemonSetFunc(func(d *v1.DaemonSet) *metric.Family {
+				if d.Spec.UpdateStrategy.RollingUpdate == nil || d.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable == nil {
+					return &metric.Family{}
+				}
+
+				// Like the daemonset controller, percentages are scaled by the
+				// desired number of scheduled nodes and rounded up.
+				maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(d.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable, int(d.Status.DesiredNumberScheduled), true)
+				if err != nil {
+					panic(err)
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(maxUnavailable),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_daemonset_spec_strategy_rollingupdate_max_surge",
+			"Maximum number of nodes running an updated daemon pod alongside the old one during a rolling update of a daemonset.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapDaemonSetFunc(func(d *v1.DaemonSet) *metric.Family {
+				if d.Spec.UpdateStrategy.RollingUpdate == nil || d.Spec.UpdateStrategy.RollingUpdate.MaxSurge == nil {
+					return &metric.Family{}
+				}
+
+				maxSurge, err := intstr.GetScaledValueFromIntOrPercent(d.Spec.UpdateStrategy.RollingUpdate.MaxSurge, int(d.Status.DesiredNumberScheduled), true)
+				if err != nil {
+					panic(err)
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(maxSurge),
+						},
+					},
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			descDaemonSetAnnotationsName,
 			descDaemonSetAnnotationsHelp,
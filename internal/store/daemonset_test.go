@@ -22,6 +22,7 @@ import (
 
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -228,6 +229,69 @@ func TestDaemonSetStore(t *testing.T) {
 				"kube_daemonset_status_updated_number_scheduled",
 			},
 		},
+		{
+			Obj: &v1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ds4",
+					Namespace: "ns4",
+				},
+				Spec: v1.DaemonSetSpec{
+					UpdateStrategy: v1.DaemonSetUpdateStrategy{
+						Type: v1.RollingUpdateDaemonSetStrategyType,
+						RollingUpdate: &v1.RollingUpdateDaemonSet{
+							MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+							MaxSurge:       &intstr.IntOrString{Type: intstr.Int, IntVal: 2},
+						},
+					},
+				},
+				Status: v1.DaemonSetStatus{
+					DesiredNumberScheduled: 10,
+				},
+			},
+			Want: `
+				# HELP kube_daemonset_spec_strategy The strategy used to replace old pods by new ones of a daemonset.
+				# HELP kube_daemonset_spec_strategy_rollingupdate_max_surge Maximum number of nodes running an updated daemon pod alongside the old one during a rolling update of a daemonset.
+				# HELP kube_daemonset_spec_strategy_rollingupdate_max_unavailable Maximum number of nodes with an unavailable daemon pod during a rolling update of a daemonset.
+				# TYPE kube_daemonset_spec_strategy gauge
+				# TYPE kube_daemonset_spec_strategy_rollingupdate_max_surge gauge
+				# TYPE kube_daemonset_spec_strategy_rollingupdate_max_unavailable gauge
+				kube_daemonset_spec_strategy{daemonset="ds4",namespace="ns4",type="RollingUpdate"} 1
+				kube_daemonset_spec_strategy_rollingupdate_max_surge{daemonset="ds4",namespace="ns4"} 2
+				kube_daemonset_spec_strategy_rollingupdate_max_unavailable{daemonset="ds4",namespace="ns4"} 3
+`,
+			MetricNames: []string{
+				"kube_daemonset_spec_strategy",
+				"kube_daemonset_spec_strategy_rollingupdate_max_surge",
+				"kube_daemonset_spec_strategy_rollingupdate_max_unavailable",
+			},
+		},
+		{
+			Obj: &v1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ds5",
+					Namespace: "ns5",
+				},
+				Spec: v1.DaemonSetSpec{
+					UpdateStrategy: v1.DaemonSetUpdateStrategy{
+						Type: v1.OnDeleteDaemonSetStrategyType,
+					},
+				},
+			},
+			Want: `
+				# HELP kube_daemonset_spec_strategy The strategy used to replace old pods by new ones of a daemonset.
+				# HELP kube_daemonset_spec_strategy_rollingupdate_max_surge Maximum number of nodes running an updated daemon pod alongside the old one during a rolling update of a daemonset.
+				# HELP kube_daemonset_spec_strategy_rollingupdate_max_unavailable Maximum number of nodes with an unavailable daemon pod during a rolling update of a daemonset.
+				# TYPE kube_daemonset_spec_strategy gauge
+				# TYPE kube_daemonset_spec_strategy_rollingupdate_max_surge gauge
+				# TYPE kube_daemonset_spec_strategy_rollingupdate_max_unavailable gauge
+				kube_daemonset_spec_strategy{daemonset="ds5",namespace="ns5",type="OnDelete"} 1
+`,
+			MetricNames: []string{
+				"kube_daemonset_spec_strategy",
+				"kube_daemonset_spec_strategy_rollingupdate_max_surge",
+				"kube_daemonset_spec_strategy_rollingupdate_max_unavailable",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(daemonSetMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
@@ -267,6 +267,48 @@ func deploymentMetricFamilies(allowAnnotationsList, allowLabelsList []string) []
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_deployment_spec_strategy",
+			"The strategy used to replace old pods by new ones of a deployment.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapDeploymentFunc(func(d *v1.Deployment) *metric.Family {
+				if d.Spec.Strategy.Type == "" {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"type"},
+							LabelValues: []string{string(d.Spec.Strategy.Type)},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_deployment_spec_progress_deadline_seconds",
+			"Maximum time in seconds for a deployment to make progress before it is considered to be failed.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapDeploymentFunc(func(d *v1.Deployment) *metric.Family {
+				if d.Spec.ProgressDeadlineSeconds == nil {
+					return &metric.Family{}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(*d.Spec.ProgressDeadlineSeconds),
+						},
+					},
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_deployment_metadata_generation",
 			"Sequence number representing a specific generation of the desired state.",
@@ -37,6 +37,8 @@ var (
 
 	depl1MaxSurge = intstr.FromInt(10)
 	depl2MaxSurge = intstr.FromString("20%")
+
+	depl1ProgressDeadlineSeconds int32 = 600
 )
 
 func TestDeploymentStore(t *testing.T) {
@@ -71,6 +73,10 @@ func TestDeploymentStore(t *testing.T) {
 		# TYPE kube_deployment_spec_strategy_rollingupdate_max_unavailable gauge
 		# HELP kube_deployment_spec_strategy_rollingupdate_max_surge [STABLE] Maximum number of replicas that can be scheduled above the desired number of replicas during a rolling update of a deployment.
 		# TYPE kube_deployment_spec_strategy_rollingupdate_max_surge gauge
+		# HELP kube_deployment_spec_strategy The strategy used to replace old pods by new ones of a deployment.
+		# TYPE kube_deployment_spec_strategy gauge
+		# HELP kube_deployment_spec_progress_deadline_seconds Maximum time in seconds for a deployment to make progress before it is considered to be failed.
+		# TYPE kube_deployment_spec_progress_deadline_seconds gauge
 		# HELP kube_deployment_labels [STABLE] Kubernetes labels converted to Prometheus labels.
 		# TYPE kube_deployment_labels gauge
 	`
@@ -103,8 +109,10 @@ func TestDeploymentStore(t *testing.T) {
 					},
 				},
 				Spec: v1.DeploymentSpec{
-					Replicas: &depl1Replicas,
+					Replicas:                &depl1Replicas,
+					ProgressDeadlineSeconds: &depl1ProgressDeadlineSeconds,
 					Strategy: v1.DeploymentStrategy{
+						Type: v1.RollingUpdateDeploymentStrategyType,
 						RollingUpdate: &v1.RollingUpdateDeployment{
 							MaxUnavailable: &depl1MaxUnavailable,
 							MaxSurge:       &depl1MaxSurge,
@@ -117,7 +125,9 @@ func TestDeploymentStore(t *testing.T) {
         kube_deployment_created{deployment="depl1",namespace="ns1"} 1.5e+09
         kube_deployment_metadata_generation{deployment="depl1",namespace="ns1"} 21
         kube_deployment_spec_paused{deployment="depl1",namespace="ns1"} 0
+        kube_deployment_spec_progress_deadline_seconds{deployment="depl1",namespace="ns1"} 600
         kube_deployment_spec_replicas{deployment="depl1",namespace="ns1"} 200
+        kube_deployment_spec_strategy{deployment="depl1",namespace="ns1",type="RollingUpdate"} 1
         kube_deployment_spec_strategy_rollingupdate_max_surge{deployment="depl1",namespace="ns1"} 10
         kube_deployment_spec_strategy_rollingupdate_max_unavailable{deployment="depl1",namespace="ns1"} 10
         kube_deployment_status_observed_generation{deployment="depl1",namespace="ns1"} 111
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// generationErrorLogInterval is the number of generation errors of a
+// collector after which the key of the failing object is logged again.
+const generationErrorLogInterval = 100
+
+// generationErrorMetrics stores the pointer of the
+// kube_state_metrics_generation_errors_total metric.
+type generationErrorMetrics struct {
+	Errors *prometheus.CounterVec
+}
+
+// newGenerationErrorMetrics takes in a prometheus registry and initializes
+// and registers the generation error metrics. It returns those registered metrics.
+func newGenerationErrorMetrics(r prometheus.Registerer) *generationErrorMetrics {
+	return &generationErrorMetrics{
+		Errors: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_generation_errors_total",
+				Help: "Number of objects which were skipped because generating their metrics failed.",
+			},
+			[]string{"collector"},
+		),
+	}
+}
+
+// generationErrorRecoverer recovers from panics of the metric generators of
+// a collector, so that a single malformed object neither crashes
+// kube-state-metrics nor corrupts the exposition.
+type generationErrorRecoverer struct {
+	collector string
+	metrics   *generationErrorMetrics
+
+	// mtx protects errors
+	mtx    sync.Mutex
+	errors int
+}
+
+func newGenerationErrorRecoverer(collector string, metrics *generationErrorMetrics) *generationErrorRecoverer {
+	return &generationErrorRecoverer{collector: collector, metrics: metrics}
+}
+
+// wrap returns a function generating the metrics of an object with the given
+// function. It returns no families if the generation panicked, which causes
+// the object to be skipped by the MetricsStore.
+func (g *generationErrorRecoverer) wrap(f func(interface{}) []metric.FamilyInterface) func(interface{}) []metric.FamilyInterface {
+	return func(obj interface{}) (families []metric.FamilyInterface) {
+		defer func() {
+			if r := recover(); r != nil {
+				g.record(obj, fmt.Errorf("%v", r))
+				families = nil
+			}
+		}()
+
+		return f(obj)
+	}
+}
+
+func (g *generationErrorRecoverer) record(obj interface{}, err error) {
+	if g.metrics != nil {
+		g.metrics.Errors.WithLabelValues(g.collector).Inc()
+	}
+
+	g.mtx.Lock()
+	g.errors++
+	sampled := g.errors%generationErrorLogInterval == 1
+	g.mtx.Unlock()
+
+	if sampled {
+		key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		klog.ErrorS(err, "Failed to generate metrics, skipping object", "collector", g.collector, "key", key)
+	}
+}
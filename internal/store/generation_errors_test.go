@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestGenerationErrorRecoverer(t *testing.T) {
+	metrics := newGenerationErrorMetrics(prometheus.NewRegistry())
+	recoverer := newGenerationErrorRecoverer("*v1.ConfigMap", metrics)
+
+	genFunc := recoverer.wrap(func(obj interface{}) []metric.FamilyInterface {
+		cm := obj.(*v1.ConfigMap)
+		if cm.Data["panic"] != "" {
+			panic("malformed object")
+		}
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_configmap_info",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"configmap"},
+					LabelValues: []string{cm.Name},
+					Value:       1,
+				},
+			},
+		}}
+	})
+	store := metricsstore.NewMetricsStore([]string{"# HELP kube_configmap_info Information about configmap."}, genFunc)
+
+	good := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "good", UID: "uid1"}}
+	other := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "uid2"}}
+	for _, cm := range []*v1.ConfigMap{good, other} {
+		if err := store.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// An object which could be generated before must be skipped as well once it fails.
+	broken := good.DeepCopy()
+	broken.Data = map[string]string{"panic": "true"}
+	if err := store.Update(broken); err != nil {
+		t.Fatal(err)
+	}
+
+	w := strings.Builder{}
+	if err := metricsstore.NewMetricsWriter(store).WriteAll(&w); err != nil {
+		t.Fatal(err)
+	}
+	if want := "# HELP kube_configmap_info Information about configmap.\nkube_configmap_info{configmap=\"other\"} 1\n"; w.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, w.String())
+	}
+
+	if got := testutil.ToFloat64(metrics.Errors.WithLabelValues("*v1.ConfigMap")); got != 1 {
+		t.Fatalf("expected 1 generation error, got %v", got)
+	}
+}
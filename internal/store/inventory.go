@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// InventoryFamily describes an enabled metric family in the metric inventory.
+type InventoryFamily struct {
+	Name      string `json:"name"`
+	Help      string `json:"help"`
+	Type      string `json:"type"`
+	Stability string `json:"stability"`
+	Collector string `json:"collector"`
+	// LabelKeys are the label keys of the series generated so far, as some
+	// depend on the objects, e.g. the ones of the labels families.
+	LabelKeys []string `json:"labelKeys"`
+}
+
+// metricInventory holds the metric families of the enabled collectors.
+type metricInventory struct {
+	// mtx protects collectors
+	mtx        sync.Mutex
+	collectors map[string]*inventoryCollector
+}
+
+func newMetricInventory() *metricInventory {
+	return &metricInventory{collectors: map[string]*inventoryCollector{}}
+}
+
+// inventoryCollector records the label keys of the families of a collector.
+type inventoryCollector struct {
+	name     string
+	families []generator.FamilyGenerator
+
+	// mtx protects labelKeys
+	mtx       sync.Mutex
+	labelKeys []map[string]struct{}
+}
+
+// register adds the given families of a collector to the inventory,
+// replacing the previous ones of the collector, e.g. when the stores are
+// rebuilt after a change of the sharding.
+func (i *metricInventory) register(collector string, families []generator.FamilyGenerator) *inventoryCollector {
+	c := &inventoryCollector{
+		name:      collector,
+		families:  families,
+		labelKeys: make([]map[string]struct{}, len(families)),
+	}
+	for j := range c.labelKeys {
+		c.labelKeys[j] = map[string]struct{}{}
+	}
+
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	i.collectors[collector] = c
+	return c
+}
+
+// wrap returns a function generating the metrics of an object with the given
+// function, which records the label keys of the generated families.
+func (c *inventoryCollector) wrap(f func(interface{}) []metric.FamilyInterface) func(interface{}) []metric.FamilyInterface {
+	return func(obj interface{}) []metric.FamilyInterface {
+		families := f(obj)
+
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		for i, family := range families {
+			if family == nil || i >= len(c.labelKeys) {
+				continue
+			}
+			family.Inspect(func(f metric.Family) {
+				for _, m := range f.Metrics {
+					for _, k := range m.LabelKeys {
+						c.labelKeys[i][k] = struct{}{}
+					}
+				}
+			})
+		}
+
+		return families
+	}
+}
+
+// families returns the families of all collectors, sorted by their name.
+func (i *metricInventory) families() []InventoryFamily {
+	i.mtx.Lock()
+	collectors := make([]*inventoryCollector, 0, len(i.collectors))
+	for _, c := range i.collectors {
+		collectors = append(collectors, c)
+	}
+	i.mtx.Unlock()
+
+	families := []InventoryFamily{}
+	for _, c := range collectors {
+		c.mtx.Lock()
+		for j, f := range c.families {
+			labelKeys := make([]string, 0, len(c.labelKeys[j]))
+			for k := range c.labelKeys[j] {
+				labelKeys = append(labelKeys, k)
+			}
+			sort.Strings(labelKeys)
+			families = append(families, InventoryFamily{
+				Name:      f.Name,
+				Help:      f.Help,
+				Type:      string(f.Type),
+				Stability: string(f.StabilityLevel),
+				Collector: c.name,
+				LabelKeys: labelKeys,
+			})
+		}
+		c.mtx.Unlock()
+	}
+	sort.Slice(families, func(a, b int) bool { return families[a].Name < families[b].Name })
+	return families
+}
+
+// ServeHTTP writes the families of the inventory as JSON.
+func (i *metricInventory) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(i.families()); err != nil {
+		klog.ErrorS(err, "Failed to write metric inventory")
+	}
+}
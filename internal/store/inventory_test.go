@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+func TestMetricInventory(t *testing.T) {
+	inventory := newMetricInventory()
+	var families []generator.FamilyGenerator
+	for _, f := range configMapMetricFamilies(nil, []string{"*"}) {
+		if f.Name == "kube_configmap_info" || f.Name == "kube_configmap_labels" {
+			families = append(families, f)
+		}
+	}
+	generate := inventory.register("*v1.ConfigMap", families).wrap(generator.ComposeMetricGenFuncs(families))
+
+	generate(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "cm1",
+		Namespace: "ns1",
+		Labels:    map[string]string{"app": "foo"},
+	}})
+	generate(&v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "cm2",
+		Namespace: "ns1",
+		Labels:    map[string]string{"team": "bar"},
+	}})
+
+	w := httptest.NewRecorder()
+	inventory.ServeHTTP(w, httptest.NewRequest("GET", "/metrics-inventory", nil))
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected a JSON response, got content type %q", got)
+	}
+	var got []InventoryFamily
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []InventoryFamily{
+		{
+			Name:      "kube_configmap_info",
+			Help:      "Information about configmap.",
+			Type:      "gauge",
+			Stability: "STABLE",
+			Collector: "*v1.ConfigMap",
+			LabelKeys: []string{"configmap", "namespace"},
+		},
+		{
+			Name:      "kube_configmap_labels",
+			Help:      "Kubernetes labels converted to Prometheus labels.",
+			Type:      "gauge",
+			Stability: "STABLE",
+			Collector: "*v1.ConfigMap",
+			LabelKeys: []string{"configmap", "label_app", "label_team", "namespace"},
+		},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("unexpected inventory (-want, +got):\n%s", diff)
+	}
+
+	// Registering the collector again, e.g. when the stores are rebuilt, replaces its families.
+	inventory.register("*v1.ConfigMap", families[:1])
+	if got := inventory.families(); len(got) != 1 || len(got[0].LabelKeys) != 0 {
+		t.Fatalf("expected the families of the collector to be replaced, got %+v", got)
+	}
+}
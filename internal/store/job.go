@@ -18,7 +18,9 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -184,6 +186,68 @@ func jobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_spec_completion_mode",
+			"The completion mode of the job.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Spec.CompletionMode != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"completion_mode"},
+						LabelValues: []string{string(*j.Spec.CompletionMode)},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_spec_suspend",
+			"Whether the job is suspended.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Spec.Suspend != nil {
+					ms = append(ms, &metric.Metric{
+						Value: boolFloat64(*j.Spec.Suspend),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_spec_backoff_limit_per_index",
+			"The number of retries of an index of an Indexed job before the index is marked as failed.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Spec.BackoffLimitPerIndex != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*j.Spec.BackoffLimitPerIndex),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_job_status_succeeded",
 			"The number of pods which reached Phase Succeeded.",
@@ -265,6 +329,68 @@ func jobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_status_ready",
+			"The number of active pods which have a Ready condition.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Status.Ready != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*j.Status.Ready),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_status_terminating",
+			"The number of pods which are terminating.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Status.Terminating != nil {
+					ms = append(ms, &metric.Metric{
+						Value: float64(*j.Status.Terminating),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_job_status_failed_indexes",
+			"The number of failed indexes of an Indexed job with a backoff limit per index.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if j.Status.FailedIndexes != nil {
+					if n, err := countIndexes(*j.Status.FailedIndexes); err == nil {
+						ms = append(ms, &metric.Metric{
+							Value: float64(n),
+						})
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_job_complete",
 			"The job has completed its execution.",
@@ -362,42 +488,8 @@ func jobMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 			basemetrics.STABLE,
 			"",
 			wrapJobFunc(func(j *v1batch.Job) *metric.Family {
-				labelKeys := []string{"owner_kind", "owner_name", "owner_is_controller"}
-
-				owners := j.GetOwnerReferences()
-
-				if len(owners) == 0 {
-					return &metric.Family{
-						Metrics: []*metric.Metric{
-							{
-								LabelKeys:   labelKeys,
-								LabelValues: []string{"", "", ""},
-								Value:       1,
-							},
-						},
-					}
-				}
-
-				ms := make([]*metric.Metric, len(owners))
-
-				for i, owner := range owners {
-					if owner.Controller != nil {
-						ms[i] = &metric.Metric{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{owner.Kind, owner.Name, strconv.FormatBool(*owner.Controller)},
-							Value:       1,
-						}
-					} else {
-						ms[i] = &metric.Metric{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{owner.Kind, owner.Name, "false"},
-							Value:       1,
-						}
-					}
-				}
-
 				return &metric.Family{
-					Metrics: ms,
+					Metrics: ownerMetrics(j.GetOwnerReferences()),
 				}
 			}),
 		),
@@ -437,3 +529,30 @@ func failureReason(jc *v1batch.JobCondition, reason string) bool {
 	}
 	return jc.Reason == reason
 }
+
+// countIndexes returns the number of indexes in the compressed index list of
+// an Indexed job, e.g. "1,3-5,7" holds 5 indexes.
+func countIndexes(indexes string) (int, error) {
+	n := 0
+	if indexes == "" {
+		return n, nil
+	}
+	for _, interval := range strings.Split(indexes, ",") {
+		first, last, isRange := strings.Cut(interval, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return 0, err
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil {
+				return 0, err
+			}
+		}
+		if end < start {
+			return 0, fmt.Errorf("invalid index interval %q", interval)
+		}
+		n += end - start + 1
+	}
+	return n, nil
+}
@@ -23,6 +23,7 @@ import (
 	v1batch "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -62,6 +63,18 @@ func TestJobStore(t *testing.T) {
 		# TYPE kube_job_info gauge
 		# HELP kube_job_labels [STABLE] Kubernetes labels converted to Prometheus labels.
 		# TYPE kube_job_labels gauge
+		# HELP kube_job_spec_backoff_limit_per_index The number of retries of an index of an Indexed job before the index is marked as failed.
+		# TYPE kube_job_spec_backoff_limit_per_index gauge
+		# HELP kube_job_spec_completion_mode The completion mode of the job.
+		# TYPE kube_job_spec_completion_mode gauge
+		# HELP kube_job_spec_suspend Whether the job is suspended.
+		# TYPE kube_job_spec_suspend gauge
+		# HELP kube_job_status_failed_indexes The number of failed indexes of an Indexed job with a backoff limit per index.
+		# TYPE kube_job_status_failed_indexes gauge
+		# HELP kube_job_status_ready The number of active pods which have a Ready condition.
+		# TYPE kube_job_status_ready gauge
+		# HELP kube_job_status_terminating The number of pods which are terminating.
+		# TYPE kube_job_status_terminating gauge
 		# HELP kube_job_spec_active_deadline_seconds [STABLE] The duration in seconds relative to the startTime that the job may be active before the system tries to terminate it.
 		# TYPE kube_job_spec_active_deadline_seconds gauge
 		# HELP kube_job_spec_completions [STABLE] The desired number of successfully finished pods the job should be run with.
@@ -250,6 +263,42 @@ func TestJobStore(t *testing.T) {
 				kube_job_status_failed{job_name="SuccessfulJob2NoActiveDeadlineSeconds",namespace="ns1"} 0
 				kube_job_status_start_time{job_name="SuccessfulJob2NoActiveDeadlineSeconds",namespace="ns1"} 1.495800607e+09
 				kube_job_status_succeeded{job_name="SuccessfulJob2NoActiveDeadlineSeconds",namespace="ns1"} 1
+`,
+		},
+		{
+			Obj: &v1batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "IndexedJob1",
+					Namespace: "ns1",
+				},
+				Status: v1batch.JobStatus{
+					Active:        2,
+					Failed:        4,
+					Ready:         ptr.To[int32](1),
+					Terminating:   ptr.To[int32](1),
+					FailedIndexes: ptr.To("1,3-5"),
+				},
+				Spec: v1batch.JobSpec{
+					Completions:          ptr.To[int32](10),
+					Parallelism:          ptr.To[int32](2),
+					CompletionMode:       ptr.To(v1batch.IndexedCompletion),
+					Suspend:              ptr.To(false),
+					BackoffLimitPerIndex: ptr.To[int32](2),
+				},
+			},
+			Want: metadata + `
+				kube_job_info{job_name="IndexedJob1",namespace="ns1"} 1
+				kube_job_owner{job_name="IndexedJob1",namespace="ns1",owner_is_controller="",owner_kind="",owner_name=""} 1
+				kube_job_spec_backoff_limit_per_index{job_name="IndexedJob1",namespace="ns1"} 2
+				kube_job_spec_completion_mode{completion_mode="Indexed",job_name="IndexedJob1",namespace="ns1"} 1
+				kube_job_spec_completions{job_name="IndexedJob1",namespace="ns1"} 10
+				kube_job_spec_parallelism{job_name="IndexedJob1",namespace="ns1"} 2
+				kube_job_spec_suspend{job_name="IndexedJob1",namespace="ns1"} 0
+				kube_job_status_active{job_name="IndexedJob1",namespace="ns1"} 2
+				kube_job_status_failed_indexes{job_name="IndexedJob1",namespace="ns1"} 4
+				kube_job_status_ready{job_name="IndexedJob1",namespace="ns1"} 1
+				kube_job_status_succeeded{job_name="IndexedJob1",namespace="ns1"} 0
+				kube_job_status_terminating{job_name="IndexedJob1",namespace="ns1"} 1
 `,
 		},
 	}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// labelKeysSanitized and labelKeysConflicted hold the distinct Kubernetes
+	// label and annotation keys which were converted to Prometheus label
+	// names. They are global, as the keys are converted by the metric
+	// generators, which have no access to the registry.
+	labelKeysSanitized  = &labelKeySet{}
+	labelKeysConflicted = &labelKeySet{}
+)
+
+// labelKeySet holds the keys which were converted, so that the keys of an
+// object are counted once, however often its metrics are generated.
+type labelKeySet struct {
+	mtx  sync.Mutex
+	keys map[string]struct{}
+}
+
+// add adds the given key, converted with the given prefix, to the set.
+func (s *labelKeySet) add(prefix, key string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.keys == nil {
+		s.keys = map[string]struct{}{}
+	}
+	s.keys[prefix+"\x00"+key] = struct{}{}
+}
+
+// len returns the number of distinct keys in the set.
+func (s *labelKeySet) len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.keys)
+}
+
+// registerLabelKeyMetrics registers the
+// kube_state_metrics_label_keys_sanitized_total metric in the given registry.
+func registerLabelKeyMetrics(r prometheus.Registerer) {
+	for reason, keys := range map[string]*labelKeySet{
+		"sanitized": labelKeysSanitized,
+		"conflict":  labelKeysConflicted,
+	} {
+		r.MustRegister(prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "kube_state_metrics_label_keys_sanitized_total",
+				Help:        "Number of distinct Kubernetes label and annotation keys which were not valid Prometheus label names and were sanitized, or which collided with another key and were suffixed.",
+				ConstLabels: prometheus.Labels{"reason": reason},
+			},
+			func() float64 { return float64(keys.len()) },
+		))
+	}
+}
+
+// PrometheusLabelName converts the given key, e.g. a Kubernetes label or
+// annotation key, to a valid Prometheus label name. In addition to
+// SanitizeLabelName, names which are empty or start with a digit are prefixed
+// with an underscore.
+func PrometheusLabelName(key string) string {
+	name := SanitizeLabelName(key)
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	if name != key {
+		labelKeysSanitized.add("", key)
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// labelSelectedListWatch only lists and watches the objects matching a label
+// selector, which is evaluated by the apiserver.
+type labelSelectedListWatch struct {
+	selector string
+	lw       cache.ListerWatcher
+}
+
+// newLabelSelectedListWatch returns a new labelSelectedListWatch via the
+// cache.ListerWatcher interface. In the case of an empty selector, it returns
+// the provided cache.ListerWatcher.
+func newLabelSelectedListWatch(selector string, lw cache.ListerWatcher) cache.ListerWatcher {
+	if selector == "" {
+		return lw
+	}
+
+	return &labelSelectedListWatch{selector: selector, lw: lw}
+}
+
+func (l *labelSelectedListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	return l.lw.List(l.withSelector(options))
+}
+
+func (l *labelSelectedListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return l.lw.Watch(l.withSelector(options))
+}
+
+func (l *labelSelectedListWatch) withSelector(options metav1.ListOptions) metav1.ListOptions {
+	if options.LabelSelector == "" {
+		options.LabelSelector = l.selector
+	} else {
+		options.LabelSelector += "," + l.selector
+	}
+	return options
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"slices"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// metadataOnlyResource describes a resource which can be listed and watched
+// as PartialObjectMetadata, i.e. without its spec, status or data.
+type metadataOnlyResource struct {
+	gvr schema.GroupVersionResource
+	// newObject returns an object of the type of the resource holding the
+	// given metadata, so the metric families of the resource can be reused.
+	newObject func(metav1.ObjectMeta) runtime.Object
+	// fullObjectFamilies are the families of the resource which need more
+	// than the metadata of the objects. They are not generated.
+	fullObjectFamilies []string
+}
+
+// availableMetadataOnlyResources are the resources whose metrics are mostly
+// derived from the metadata of their objects.
+var availableMetadataOnlyResources = map[string]metadataOnlyResource{
+	"clusterroles": {
+		gvr:       rbacv1.SchemeGroupVersion.WithResource("clusterroles"),
+		newObject: func(m metav1.ObjectMeta) runtime.Object { return &rbacv1.ClusterRole{ObjectMeta: m} },
+	},
+	"configmaps": {
+		gvr:                v1.SchemeGroupVersion.WithResource("configmaps"),
+		newObject:          func(m metav1.ObjectMeta) runtime.Object { return &v1.ConfigMap{ObjectMeta: m} },
+		fullObjectFamilies: []string{"kube_configmap_object_size_bytes", "kube_configmap_data_bytes", "kube_configmap_data_keys"},
+	},
+	"leases": {
+		gvr:                coordinationv1.SchemeGroupVersion.WithResource("leases"),
+		newObject:          func(m metav1.ObjectMeta) runtime.Object { return &coordinationv1.Lease{ObjectMeta: m} },
+		fullObjectFamilies: []string{"kube_lease_owner", "kube_lease_renew_time"},
+	},
+	"roles": {
+		gvr:       rbacv1.SchemeGroupVersion.WithResource("roles"),
+		newObject: func(m metav1.ObjectMeta) runtime.Object { return &rbacv1.Role{ObjectMeta: m} },
+	},
+	"secrets": {
+		gvr:                v1.SchemeGroupVersion.WithResource("secrets"),
+		newObject:          func(m metav1.ObjectMeta) runtime.Object { return &v1.Secret{ObjectMeta: m} },
+		fullObjectFamilies: []string{"kube_secret_type", "kube_secret_object_size_bytes", "kube_secret_data_bytes", "kube_secret_data_keys"},
+	},
+}
+
+// filterFamilies returns the given families without the ones which need more
+// than the metadata of the objects.
+func (r *metadataOnlyResource) filterFamilies(families []generator.FamilyGenerator) []generator.FamilyGenerator {
+	filtered := make([]generator.FamilyGenerator, 0, len(families))
+	for _, f := range families {
+		if !slices.Contains(r.fullObjectFamilies, f.Name) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// listWatchFunc returns a function creating ListerWatchers of the resource
+// with the given metadata client, in place of the typed ListerWatchers.
+func (r *metadataOnlyResource) listWatchFunc(client metadata.Interface) func(clientset.Interface, string, string) cache.ListerWatcher {
+	return func(_ clientset.Interface, ns string, fieldSelector string) cache.ListerWatcher {
+		return &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				opts.FieldSelector = fieldSelector
+				list, err := client.Resource(r.gvr).Namespace(ns).List(context.TODO(), opts)
+				if err != nil {
+					return nil, err
+				}
+				items := make([]runtime.RawExtension, len(list.Items))
+				for i := range list.Items {
+					items[i].Object = r.newObject(list.Items[i].ObjectMeta)
+				}
+				return &metav1.List{ListMeta: list.ListMeta, Items: items}, nil
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				opts.FieldSelector = fieldSelector
+				w, err := client.Resource(r.gvr).Namespace(ns).Watch(context.TODO(), opts)
+				if err != nil {
+					return nil, err
+				}
+				return watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+					if m, ok := e.Object.(*metav1.PartialObjectMetadata); ok {
+						e.Object = r.newObject(m.ObjectMeta)
+					}
+					return e, true
+				}), nil
+			},
+		}
+	}
+}
+
+func availableMetadataOnlyResourceNames() []string {
+	names := make([]string, 0, len(availableMetadataOnlyResources))
+	for name := range availableMetadataOnlyResources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func TestWithMetadataOnlyResources(t *testing.T) {
+	b := NewBuilder()
+	if err := b.WithMetadataOnlyResources([]string{"pods"}); err == nil {
+		t.Error("expected an error for a resource which cannot be watched as metadata only")
+	}
+	if err := b.WithMetadataOnlyResources([]string{"secrets"}); err != nil {
+		t.Fatal(err)
+	}
+	if b.metadataOnlyResource("secrets") != nil {
+		t.Error("expected secrets to be watched as full objects without a metadata client")
+	}
+
+	b.WithMetadataClient(metadatafake.NewSimpleMetadataClient(metadatafake.NewTestScheme()))
+	r := b.metadataOnlyResource("secrets")
+	if r == nil {
+		t.Fatal("expected secrets to be watched as metadata only")
+	}
+	if b.metadataOnlyResource("configmaps") != nil {
+		t.Error("expected configmaps to be watched as full objects")
+	}
+
+	for _, f := range r.filterFamilies(secretMetricFamilies(nil, nil)) {
+		if f.Name == "kube_secret_type" {
+			t.Error("expected kube_secret_type to be filtered out for metadata-only secrets")
+		}
+	}
+}
+
+func TestMetadataOnlyListWatch(t *testing.T) {
+	scheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme, &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: "s1", Namespace: "ns1", Labels: map[string]string{"app": "foo"}},
+	})
+	r := availableMetadataOnlyResources["secrets"]
+	lw := r.listWatchFunc(client)(nil, "ns1", "")
+
+	list, err := lw.List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 secret, got %d", len(items))
+	}
+	s, ok := items[0].(*v1.Secret)
+	if !ok {
+		t.Fatalf("expected a *v1.Secret, got %T", items[0])
+	}
+	if s.Name != "s1" || s.Labels["app"] != "foo" {
+		t.Errorf("unexpected metadata of the secret: %+v", s.ObjectMeta)
+	}
+}
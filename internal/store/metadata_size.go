@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+)
+
+// metadataSizeMetrics stores the pointer of the
+// kube_state_metrics_metadata_bytes metric.
+type metadataSizeMetrics struct {
+	Bytes *prometheus.GaugeVec
+}
+
+// newMetadataSizeMetrics takes in a prometheus registry and initializes and
+// registers the metadata size metrics. It returns those registered metrics.
+func newMetadataSizeMetrics(r prometheus.Registerer) *metadataSizeMetrics {
+	return &metadataSizeMetrics{
+		Bytes: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_metadata_bytes",
+				Help: "Number of bytes of the keys and values of the labels and annotations of the objects of a resource, per namespace.",
+			},
+			[]string{"resource", "namespace"},
+		),
+	}
+}
+
+type metadataSize struct {
+	namespace string
+	bytes     int
+}
+
+// metadataSizeTracker accounts the size of the labels and annotations of the
+// objects of a resource, in order to find the namespaces whose metadata
+// inflates the exposition before restricting the allowlists.
+type metadataSizeTracker struct {
+	resource string
+	metrics  *metadataSizeMetrics
+
+	// mtx protects objects and totals
+	mtx     sync.Mutex
+	objects map[types.UID]metadataSize
+	totals  map[string]int
+}
+
+// newMetadataSizeTracker returns a metadataSizeTracker for a resource with the
+// given families. It returns nil if none of them is a labels or annotations
+// family or if there are no metrics to report to.
+func newMetadataSizeTracker(resource string, families []generator.FamilyGenerator, metrics *metadataSizeMetrics) *metadataSizeTracker {
+	if metrics == nil {
+		return nil
+	}
+
+	for _, f := range families {
+		if strings.HasSuffix(f.Name, "_labels") || strings.HasSuffix(f.Name, "_annotations") {
+			return &metadataSizeTracker{
+				resource: resource,
+				metrics:  metrics,
+				objects:  map[types.UID]metadataSize{},
+				totals:   map[string]int{},
+			}
+		}
+	}
+	return nil
+}
+
+// wrap returns a function generating the metrics of an object with the given
+// function, which accounts the size of the labels and annotations of the
+// object. Objects without labels and annotations are not tracked.
+func (t *metadataSizeTracker) wrap(f func(interface{}) []metric.FamilyInterface) func(interface{}) []metric.FamilyInterface {
+	if t == nil {
+		return f
+	}
+
+	return func(obj interface{}) []metric.FamilyInterface {
+		families := f(obj)
+
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			return families
+		}
+		size := 0
+		for k, v := range o.GetLabels() {
+			size += len(k) + len(v)
+		}
+		for k, v := range o.GetAnnotations() {
+			size += len(k) + len(v)
+		}
+		t.set(o.GetUID(), metadataSize{namespace: o.GetNamespace(), bytes: size})
+
+		return families
+	}
+}
+
+// wrapStore returns a store which forgets the size of the objects removed
+// from the given store. The store holds the objects of the given namespace,
+// or of all namespaces if it is empty.
+func (t *metadataSizeTracker) wrapStore(store cache.Store, namespace string) cache.Store {
+	if t == nil {
+		return store
+	}
+
+	return &metadataSizeStore{Store: store, tracker: t, namespace: namespace}
+}
+
+func (t *metadataSizeTracker) set(uid types.UID, size metadataSize) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.remove(uid)
+	if size.bytes == 0 {
+		return
+	}
+	t.objects[uid] = size
+	t.add(size.namespace, size.bytes)
+}
+
+func (t *metadataSizeTracker) forget(uid types.UID) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.remove(uid)
+}
+
+// forgetNamespace forgets the size of all objects of the given namespace, or
+// of all objects if it is empty.
+func (t *metadataSizeTracker) forgetNamespace(namespace string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for uid, size := range t.objects {
+		if namespace == "" || size.namespace == namespace {
+			t.remove(uid)
+		}
+	}
+}
+
+func (t *metadataSizeTracker) remove(uid types.UID) {
+	size, ok := t.objects[uid]
+	if !ok {
+		return
+	}
+	delete(t.objects, uid)
+	t.add(size.namespace, -size.bytes)
+}
+
+func (t *metadataSizeTracker) add(namespace string, bytes int) {
+	t.totals[namespace] += bytes
+	if t.totals[namespace] == 0 {
+		delete(t.totals, namespace)
+		t.metrics.Bytes.DeleteLabelValues(t.resource, namespace)
+		return
+	}
+	t.metrics.Bytes.WithLabelValues(t.resource, namespace).Set(float64(t.totals[namespace]))
+}
+
+// metadataSizeStore forgets the metadata size of the objects which are
+// deleted from or replaced in the wrapped store.
+type metadataSizeStore struct {
+	cache.Store
+	tracker   *metadataSizeTracker
+	namespace string
+}
+
+// Delete forgets the size of the deleted object.
+func (s *metadataSizeStore) Delete(obj interface{}) error {
+	if o, err := meta.Accessor(deletedObject(obj)); err == nil {
+		s.tracker.forget(o.GetUID())
+	}
+	return s.Store.Delete(obj)
+}
+
+// Replace forgets the size of all objects of the store, the ones of the new
+// list are accounted again while they are added.
+func (s *metadataSizeStore) Replace(list []interface{}, resourceVersion string) error {
+	s.tracker.forgetNamespace(s.namespace)
+	return s.Store.Replace(list, resourceVersion)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestMetadataSizeTracker(t *testing.T) {
+	metrics := newMetadataSizeMetrics(prometheus.NewRegistry())
+	families := configMapMetricFamilies([]string{"*"}, []string{"*"})
+	tracker := newMetadataSizeTracker("*v1.ConfigMap", families, metrics)
+	if tracker == nil {
+		t.Fatal("expected a tracker for the configmap families")
+	}
+
+	store := metricsstore.NewMetricsStore(
+		generator.ExtractMetricFamilyHeaders(families),
+		tracker.wrap(generator.ComposeMetricGenFuncs(families)),
+	)
+	wrapped := tracker.wrapStore(store, v1.NamespaceAll)
+
+	bytes := func(ns string) float64 {
+		return testutil.ToFloat64(metrics.Bytes.WithLabelValues("*v1.ConfigMap", ns))
+	}
+
+	cm1 := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:        "cm1",
+		Namespace:   "ns1",
+		UID:         "uid1",
+		Labels:      map[string]string{"app": "foo"},
+		Annotations: map[string]string{"owner": "team"},
+	}}
+	cm2 := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "cm2",
+		Namespace: "ns2",
+		UID:       "uid2",
+		Labels:    map[string]string{"app": "bar"},
+	}}
+	cm3 := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "cm3",
+		Namespace: "ns3",
+		UID:       "uid3",
+	}}
+	for _, cm := range []*v1.ConfigMap{cm1, cm2, cm3} {
+		if err := wrapped.Add(cm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// app=foo and owner=team in ns1, app=bar in ns2.
+	if got := bytes("ns1"); got != 15 {
+		t.Fatalf("expected 15 bytes for ns1, got %v", got)
+	}
+	if got := bytes("ns2"); got != 6 {
+		t.Fatalf("expected 6 bytes for ns2, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.Bytes); got != 2 {
+		t.Fatalf("expected objects without labels and annotations not to be tracked, got %d series", got)
+	}
+
+	// Updates replace the previous size of an object instead of adding to it.
+	updated := cm1.DeepCopy()
+	updated.Labels["team"] = "platform"
+	if err := wrapped.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	if got := bytes("ns1"); got != 27 {
+		t.Fatalf("expected the size of ns1 to grow by the new label only, got %v", got)
+	}
+
+	if err := wrapped.Delete(cm2); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(metrics.Bytes); got != 1 {
+		t.Fatalf("expected the series of ns2 to be removed, got %d series", got)
+	}
+
+	if err := wrapped.Replace(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(metrics.Bytes); got != 0 {
+		t.Fatalf("expected no series after the store was reset, got %d series", got)
+	}
+}
+
+func TestMetadataSizeTrackerWithoutMetadataFamilies(t *testing.T) {
+	metrics := newMetadataSizeMetrics(prometheus.NewRegistry())
+	var families []generator.FamilyGenerator
+	for _, f := range configMapMetricFamilies(nil, nil) {
+		if f.Name == "kube_configmap_info" {
+			families = append(families, f)
+		}
+	}
+	if tracker := newMetadataSizeTracker("*v1.ConfigMap", families, metrics); tracker != nil {
+		t.Fatal("expected no tracker without labels and annotations families")
+	}
+}
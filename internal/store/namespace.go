@@ -38,6 +38,11 @@ var (
 	descNamespaceLabelsName          = "kube_namespace_labels"
 	descNamespaceLabelsHelp          = "Kubernetes labels converted to Prometheus labels."
 	descNamespaceLabelsDefaultLabels = []string{"namespace"}
+
+	// podSecurityLabelPrefix is the prefix of the namespace labels configuring
+	// the pod security admission controller.
+	podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+	podSecurityModes       = []string{"enforce", "warn", "audit"}
 )
 
 func namespaceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
@@ -159,6 +164,41 @@ func namespaceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []g
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_namespace_pod_security",
+			"The pod security admission level and version of each mode of a namespace.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapNamespaceFunc(func(n *v1.Namespace) *metric.Family {
+				labelKeys := make([]string, 0, 2*len(podSecurityModes))
+				labelValues := make([]string, 0, 2*len(podSecurityModes))
+				found := false
+				for _, mode := range podSecurityModes {
+					level, ok := n.Labels[podSecurityLabelPrefix+mode]
+					version, versionOk := n.Labels[podSecurityLabelPrefix+mode+"-version"]
+					found = found || ok || versionOk
+					labelKeys = append(labelKeys, mode, mode+"_version")
+					labelValues = append(labelValues, level, version)
+				}
+
+				if !found {
+					return &metric.Family{
+						Metrics: []*metric.Metric{},
+					}
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
 	}
 }
 
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// namespaceFilteredListWatch drops the objects of the namespaces which do not
+// match the namespace glob patterns of a NamespaceFilter.
+type namespaceFilteredListWatch struct {
+	filter *options.NamespaceFilter
+	lw     cache.ListerWatcher
+}
+
+// newNamespaceFilteredListWatch returns a new namespaceFilteredListWatch via the
+// cache.ListerWatcher interface. In the case of no filter, it returns the
+// provided cache.ListerWatcher.
+func newNamespaceFilteredListWatch(filter *options.NamespaceFilter, lw cache.ListerWatcher) cache.ListerWatcher {
+	if filter == nil {
+		return lw
+	}
+
+	return &namespaceFilteredListWatch{filter: filter, lw: lw}
+}
+
+func (n *namespaceFilteredListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	list, err := n.lw.List(options)
+	if err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	metaObj, err := meta.ListAccessor(list)
+	if err != nil {
+		return nil, err
+	}
+	res := &metav1.List{
+		Items: []runtime.RawExtension{},
+	}
+	for _, item := range items {
+		a, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		if n.filter.Matches(a.GetNamespace()) {
+			res.Items = append(res.Items, runtime.RawExtension{Object: item})
+		}
+	}
+	res.ListMeta.ResourceVersion = metaObj.GetResourceVersion()
+
+	return res, nil
+}
+
+func (n *namespaceFilteredListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	w, err := n.lw.Watch(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return watch.Filter(w, func(in watch.Event) (out watch.Event, keep bool) {
+		a, err := meta.Accessor(in.Object)
+		if err != nil {
+			return in, true
+		}
+
+		return in, n.filter.Matches(a.GetNamespace())
+	}), nil
+}
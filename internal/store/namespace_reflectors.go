@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	reflectorEventStarted = "started"
+	reflectorEventStopped = "stopped"
+)
+
+// namespaceReflectorMetrics stores the pointer of the
+// kube_state_metrics_namespace_reflector_events_total metric.
+type namespaceReflectorMetrics struct {
+	Events *prometheus.CounterVec
+}
+
+// newNamespaceReflectorMetrics takes in a prometheus registry and initializes
+// and registers the namespace reflector metrics. It returns those registered metrics.
+func newNamespaceReflectorMetrics(r prometheus.Registerer) *namespaceReflectorMetrics {
+	return &namespaceReflectorMetrics{
+		Events: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_namespace_reflector_events_total",
+				Help: "Number of times the reflectors of a namespace were stopped or started because the namespace was deleted or recreated.",
+			},
+			[]string{"namespace", "event"},
+		),
+	}
+}
+
+// namespacedReflector is a reflector which can be started again with a new context.
+type namespacedReflector struct {
+	store cache.Store
+	// run starts the reflector, the returned channel is closed once it exited
+	// after its context was cancelled.
+	run func(ctx context.Context) <-chan struct{}
+	// done is the channel returned by the last run, it is nil if the
+	// reflector was never run.
+	done <-chan struct{}
+}
+
+// namespaceState holds the reflectors of a single namespace.
+type namespaceState struct {
+	reflectors []namespacedReflector
+	ctx        context.Context
+	// cancel is nil while the reflectors are stopped.
+	cancel context.CancelFunc
+	// uid is the UID of the namespace the reflectors were started for, it is
+	// empty as long as the namespace was not observed.
+	uid types.UID
+	// reset is closed once the stores of the last stopped reflectors were
+	// reset, it is nil if the reflectors were never stopped.
+	reset <-chan struct{}
+}
+
+// namespaceReflectors tracks the reflectors started for each namespace of
+// --namespaces. It stops them when their namespace is deleted and starts them
+// again once the namespace is recreated.
+type namespaceReflectors struct {
+	ctx     context.Context
+	metrics *namespaceReflectorMetrics
+
+	// mtx protects namespaces
+	mtx        sync.Mutex
+	namespaces map[string]*namespaceState
+}
+
+func newNamespaceReflectors(ctx context.Context, namespaces []string, metrics *namespaceReflectorMetrics) *namespaceReflectors {
+	n := &namespaceReflectors{
+		ctx:        ctx,
+		metrics:    metrics,
+		namespaces: make(map[string]*namespaceState, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		nsCtx, cancel := context.WithCancel(ctx)
+		n.namespaces[ns] = &namespaceState{ctx: nsCtx, cancel: cancel}
+	}
+	return n
+}
+
+// add registers the reflector of the given namespace and runs it, unless the
+// namespace is currently deleted. It returns whether the reflector was run.
+func (n *namespaceReflectors) add(ns string, store cache.Store, run func(ctx context.Context) <-chan struct{}) bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	s, ok := n.namespaces[ns]
+	if !ok {
+		run(n.ctx)
+		return true
+	}
+	r := namespacedReflector{store: store, run: run}
+	if s.cancel != nil {
+		r.done = runAfter(s.ctx, s.reset, run)
+	}
+	s.reflectors = append(s.reflectors, r)
+	return r.done != nil
+}
+
+// context returns the context the reflectors of the given namespace run with,
+// which is done while the namespace is deleted.
+func (n *namespaceReflectors) context(ns string) context.Context {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if s, ok := n.namespaces[ns]; ok {
+		return s.ctx
+	}
+	return n.ctx
+}
+
+// watch starts an informer on namespaces and restarts the reflectors of a
+// namespace whenever it is recreated.
+func (n *namespaceReflectors) watch(kubeClient clientset.Interface) {
+	lw := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "namespaces", v1.NamespaceAll, fields.Everything())
+	i := cache.NewSharedIndexInformer(lw, &v1.Namespace{}, 0, cache.Indexers{})
+	i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(o interface{}) {
+			if ns, ok := o.(*v1.Namespace); ok {
+				n.observed(ns)
+			}
+		},
+		UpdateFunc: func(_, cur interface{}) {
+			if ns, ok := cur.(*v1.Namespace); ok {
+				n.observed(ns)
+			}
+		},
+		DeleteFunc: func(o interface{}) {
+			if tombstone, ok := o.(cache.DeletedFinalStateUnknown); ok {
+				o = tombstone.Obj
+			}
+			if ns, ok := o.(*v1.Namespace); ok {
+				n.deleted(ns.Name)
+			}
+		},
+	})
+	go i.Run(n.ctx.Done())
+}
+
+// observed (re-)starts the reflectors of the given namespace if it was
+// recreated since the reflectors were started.
+func (n *namespaceReflectors) observed(ns *v1.Namespace) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	s, ok := n.namespaces[ns.Name]
+	if !ok {
+		return
+	}
+	if s.cancel != nil && (s.uid == "" || s.uid == ns.UID) {
+		s.uid = ns.UID
+		return
+	}
+	if s.cancel != nil {
+		// The deletion of the previous namespace was missed.
+		n.stop(ns.Name, s)
+	}
+	s.uid = ns.UID
+	n.start(ns.Name, s)
+}
+
+// deleted stops the reflectors of the given namespace.
+func (n *namespaceReflectors) deleted(name string) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	s, ok := n.namespaces[name]
+	if !ok {
+		return
+	}
+	if s.cancel != nil {
+		n.stop(name, s)
+	}
+	s.uid = ""
+}
+
+func (n *namespaceReflectors) start(name string, s *namespaceState) {
+	klog.InfoS("Starting reflectors of recreated namespace", "namespace", name)
+	s.ctx, s.cancel = context.WithCancel(n.ctx)
+	for i := range s.reflectors {
+		s.reflectors[i].done = runAfter(s.ctx, s.reset, s.reflectors[i].run)
+	}
+	if n.metrics != nil {
+		n.metrics.Events.WithLabelValues(name, reflectorEventStarted).Inc()
+	}
+}
+
+// stop cancels the reflectors of the given namespace and resets their stores
+// once they exited. It doesn't wait for them, as it is called by the event
+// handler of the namespace informer, whose events would be held back.
+func (n *namespaceReflectors) stop(name string, s *namespaceState) {
+	klog.InfoS("Stopping reflectors of deleted namespace", "namespace", name)
+	s.cancel()
+	s.cancel = nil
+
+	previous := s.reset
+	reset := make(chan struct{})
+	s.reset = reset
+	reflectors := slices.Clone(s.reflectors)
+	go func() {
+		defer close(reset)
+		if previous != nil {
+			<-previous
+		}
+		for _, r := range reflectors {
+			// A reflector which did not exit yet could still write stale
+			// objects into the store after it was reset.
+			if r.done != nil {
+				<-r.done
+			}
+			// Drop the metrics of the objects of the deleted namespace.
+			if err := r.store.Replace(nil, ""); err != nil {
+				klog.ErrorS(err, "Failed to reset store of deleted namespace", "namespace", name)
+			}
+		}
+	}()
+	if n.metrics != nil {
+		n.metrics.Events.WithLabelValues(name, reflectorEventStopped).Inc()
+	}
+}
+
+// runAfter runs the given reflector with ctx once reset is closed, so that the
+// reset of its store by a previous stop doesn't drop the objects it lists. A
+// nil reset runs it right away. The returned channel is closed once the
+// reflector exited, or ctx is done before it ran.
+func runAfter(ctx context.Context, reset <-chan struct{}, run func(ctx context.Context) <-chan struct{}) <-chan struct{} {
+	if reset == nil {
+		return run(ctx)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-reset:
+		case <-ctx.Done():
+			return
+		}
+		<-run(ctx)
+	}()
+	return done
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+// reflectorRuns records the contexts a namespaced reflector was run with.
+type reflectorRuns struct {
+	mtx  sync.Mutex
+	runs []context.Context
+}
+
+func (r *reflectorRuns) run(ctx context.Context) <-chan struct{} {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.runs = append(r.runs, ctx)
+	return ctx.Done()
+}
+
+func (r *reflectorRuns) get() []context.Context {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]context.Context(nil), r.runs...)
+}
+
+// waitFor fails the test if cond doesn't become true within a few seconds.
+func waitFor(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNamespaceReflectorsRecreation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metrics := newNamespaceReflectorMetrics(prometheus.NewRegistry())
+	n := newNamespaceReflectors(ctx, []string{"default"}, metrics)
+
+	runs := &reflectorRuns{}
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	if err := store.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}}); err != nil {
+		t.Fatal(err)
+	}
+	n.add("default", store, runs.run)
+	if got := len(runs.get()); got != 1 {
+		t.Fatalf("expected reflector to be started once, got %d", got)
+	}
+
+	ns := func(uid types.UID) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: uid}}
+	}
+
+	// The initial list of namespaces must not restart the reflector.
+	n.observed(ns("a"))
+	if got := len(runs.get()); got != 1 {
+		t.Fatalf("expected reflector not to be restarted for an unchanged namespace, got %d runs", got)
+	}
+
+	n.deleted("default")
+	if runs.get()[0].Err() == nil {
+		t.Fatal("expected reflector to be stopped after namespace deletion")
+	}
+	waitFor(t, "expected store to be emptied after namespace deletion", func() bool {
+		return len(store.List()) == 0
+	})
+
+	n.observed(ns("b"))
+	waitFor(t, "expected reflector to be running again after namespace recreation", func() bool {
+		r := runs.get()
+		return len(r) == 2 && r[1].Err() == nil
+	})
+
+	// A recreation whose deletion was missed restarts the reflector as well.
+	n.observed(ns("c"))
+	waitFor(t, "expected reflector to be restarted after a missed namespace deletion", func() bool {
+		r := runs.get()
+		return len(r) == 3 && r[1].Err() != nil && r[2].Err() == nil
+	})
+
+	if got := testutil.ToFloat64(metrics.Events.WithLabelValues("default", reflectorEventStopped)); got != 2 {
+		t.Fatalf("expected 2 stopped events, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.Events.WithLabelValues("default", reflectorEventStarted)); got != 2 {
+		t.Fatalf("expected 2 started events, got %v", got)
+	}
+}
+
+func TestNamespaceReflectorsUntrackedNamespace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := newNamespaceReflectors(ctx, []string{"default"}, nil)
+
+	runs := &reflectorRuns{}
+	n.add("kube-system", cache.NewStore(cache.MetaNamespaceKeyFunc), runs.run)
+	n.deleted("kube-system")
+	n.observed(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "a"}})
+
+	if got := len(runs.get()); got != 1 {
+		t.Fatalf("expected reflector of an untracked namespace to be started once, got %d", got)
+	}
+}
+
+func TestNamespaceReflectorsStopWaitsForReflector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := newNamespaceReflectors(ctx, []string{"default"}, nil)
+
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	exit := make(chan struct{})
+	n.add("default", store, func(ctx context.Context) <-chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			<-ctx.Done()
+			<-exit
+			// A reflector which is still populating the store when it is
+			// stopped.
+			_ = store.Add(&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "default"}})
+		}()
+		return done
+	})
+
+	// The event handler must not wait for the reflector to exit.
+	n.deleted("default")
+	restarted := &reflectorRuns{}
+	n.add("default", cache.NewStore(cache.MetaNamespaceKeyFunc), restarted.run)
+	n.observed(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "a"}})
+	if got := len(restarted.get()); got != 0 {
+		t.Fatalf("expected reflectors not to be restarted before the stores were reset, got %d runs", got)
+	}
+
+	close(exit)
+	waitFor(t, "expected reflectors to be restarted once the stores were reset", func() bool {
+		return len(restarted.get()) == 1
+	})
+	waitFor(t, "expected store to be emptied after the reflector exited", func() bool {
+		return len(store.List()) == 0
+	})
+}
+
+func TestNamespaceReflectorsAddToDeletedNamespace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := newNamespaceReflectors(ctx, []string{"default"}, nil)
+	n.deleted("default")
+	if n.context("default").Err() == nil {
+		t.Fatal("expected the context of a deleted namespace to be done")
+	}
+
+	runs := &reflectorRuns{}
+	if n.add("default", cache.NewStore(cache.MetaNamespaceKeyFunc), runs.run) {
+		t.Fatal("expected reflector of a deleted namespace not to be run")
+	}
+
+	n.observed(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "a"}})
+	waitFor(t, "expected reflector to be run once the namespace is recreated", func() bool {
+		return len(runs.get()) == 1
+	})
+}
@@ -40,6 +40,8 @@ func TestNamespaceStore(t *testing.T) {
 		# TYPE kube_namespace_status_phase gauge
 		# HELP kube_namespace_status_condition The condition of a namespace.
 		# TYPE kube_namespace_status_condition gauge
+		# HELP kube_namespace_pod_security The pod security admission level and version of each mode of a namespace.
+		# TYPE kube_namespace_pod_security gauge
 	`
 
 	cases := []generateMetricsTestCase{
@@ -150,6 +152,26 @@ func TestNamespaceStore(t *testing.T) {
 			Want: metadata + `
 				kube_namespace_status_phase{namespace="ns2",phase="Active"} 1
 				kube_namespace_status_phase{namespace="ns2",phase="Terminating"} 0
+`,
+		},
+		{
+			Obj: &v1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "nsRestricted",
+					Labels: map[string]string{
+						"pod-security.kubernetes.io/enforce":         "baseline",
+						"pod-security.kubernetes.io/enforce-version": "v1.30",
+						"pod-security.kubernetes.io/warn":            "restricted",
+					},
+				},
+				Status: v1.NamespaceStatus{
+					Phase: v1.NamespaceActive,
+				},
+			},
+			Want: metadata + `
+				kube_namespace_pod_security{namespace="nsRestricted",enforce="baseline",enforce_version="v1.30",warn="restricted",warn_version="",audit="",audit_version=""} 1
+				kube_namespace_status_phase{namespace="nsRestricted",phase="Active"} 1
+				kube_namespace_status_phase{namespace="nsRestricted",phase="Terminating"} 0
 `,
 		},
 	}
@@ -45,6 +45,7 @@ var (
 func nodeMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generator.FamilyGenerator {
 	return []generator.FamilyGenerator{
 		createNodeAnnotationsGenerator(allowAnnotationsList),
+		createNodeContainerRuntimeInfoFamilyGenerator(),
 		createNodeCreatedFamilyGenerator(),
 		createNodeDeletionTimestampFamilyGenerator(),
 		createNodeInfoFamilyGenerator(),
@@ -181,6 +182,34 @@ func createNodeInfoFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createNodeContainerRuntimeInfoFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGeneratorWithStability(
+		"kube_node_container_runtime_info",
+		"The container runtime of a cluster node and its version.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrapNodeFunc(func(n *v1.Node) *metric.Family {
+			if n.Status.NodeInfo.ContainerRuntimeVersion == "" {
+				return &metric.Family{}
+			}
+
+			// The version is reported as <runtime>://<version>, e.g. containerd://1.7.2.
+			runtimeName, version, _ := strings.Cut(n.Status.NodeInfo.ContainerRuntimeVersion, "://")
+
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"runtime", "version"},
+						LabelValues: []string{runtimeName, version},
+						Value:       1,
+					},
+				},
+			}
+		}),
+	)
+}
+
 func createNodeAnnotationsGenerator(allowAnnotationsList []string) generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		descNodeAnnotationsName,
@@ -312,77 +341,8 @@ func createNodeStatusAllocatableFamilyGenerator() generator.FamilyGenerator {
 		basemetrics.STABLE,
 		"",
 		wrapNodeFunc(func(n *v1.Node) *metric.Family {
-			ms := []*metric.Metric{}
-
-			allocatable := n.Status.Allocatable
-
-			for resourceName, val := range allocatable {
-				switch resourceName {
-				case v1.ResourceCPU:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitCore),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				case v1.ResourceStorage:
-					fallthrough
-				case v1.ResourceEphemeralStorage:
-					fallthrough
-				case v1.ResourceMemory:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitByte),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				case v1.ResourcePods:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitInteger),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				default:
-					if isHugePageResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitByte),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-					if isAttachableVolumeResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitByte),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-					if isExtendedResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitInteger),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-				}
-			}
-
-			for _, m := range ms {
-				m.LabelKeys = []string{"resource", "unit"}
-			}
-
 			return &metric.Family{
-				Metrics: ms,
+				Metrics: nodeResourceListMetrics(n.Status.Allocatable),
 			}
 		}),
 	)
@@ -396,81 +356,53 @@ func createNodeStatusCapacityFamilyGenerator() generator.FamilyGenerator {
 		basemetrics.STABLE,
 		"",
 		wrapNodeFunc(func(n *v1.Node) *metric.Family {
-			ms := []*metric.Metric{}
-
-			capacity := n.Status.Capacity
-			for resourceName, val := range capacity {
-				switch resourceName {
-				case v1.ResourceCPU:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitCore),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				case v1.ResourceStorage:
-					fallthrough
-				case v1.ResourceEphemeralStorage:
-					fallthrough
-				case v1.ResourceMemory:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitByte),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				case v1.ResourcePods:
-					ms = append(ms, &metric.Metric{
-						LabelValues: []string{
-							SanitizeLabelName(string(resourceName)),
-							string(constant.UnitInteger),
-						},
-						Value: float64(val.MilliValue()) / 1000,
-					})
-				default:
-					if isHugePageResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitByte),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-					if isAttachableVolumeResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitByte),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-					if isExtendedResourceName(resourceName) {
-						ms = append(ms, &metric.Metric{
-							LabelValues: []string{
-								SanitizeLabelName(string(resourceName)),
-								string(constant.UnitInteger),
-							},
-							Value: float64(val.MilliValue()) / 1000,
-						})
-					}
-				}
-			}
-
-			for _, metric := range ms {
-				metric.LabelKeys = []string{"resource", "unit"}
-			}
-
 			return &metric.Family{
-				Metrics: ms,
+				Metrics: nodeResourceListMetrics(n.Status.Capacity),
 			}
 		}),
 	)
 }
 
+// nodeResourceListMetrics returns a metric with the resource and unit labels
+// for each resource of the given list. Besides the native resources, this
+// covers hugepages, attachable volumes and extended resources such as GPUs.
+func nodeResourceListMetrics(resources v1.ResourceList) []*metric.Metric {
+	ms := []*metric.Metric{}
+
+	for resourceName, val := range resources {
+		var unit constant.ResourceUnit
+
+		switch resourceName {
+		case v1.ResourceCPU:
+			unit = constant.UnitCore
+		case v1.ResourceStorage, v1.ResourceEphemeralStorage, v1.ResourceMemory:
+			unit = constant.UnitByte
+		case v1.ResourcePods:
+			unit = constant.UnitInteger
+		default:
+			switch {
+			case isHugePageResourceName(resourceName), isAttachableVolumeResourceName(resourceName):
+				unit = constant.UnitByte
+			case isExtendedResourceName(resourceName):
+				unit = constant.UnitInteger
+			default:
+				continue
+			}
+		}
+
+		ms = append(ms, &metric.Metric{
+			LabelKeys: []string{"resource", "unit"},
+			LabelValues: []string{
+				SanitizeLabelName(string(resourceName)),
+				string(unit),
+			},
+			Value: float64(val.MilliValue()) / 1000,
+		})
+	}
+
+	return ms
+}
+
 // createNodeStatusConditionFamilyGenerator returns an all-in-one metric family
 // containing all conditions for extensibility. Third party plugin may report
 // customized condition for cluster node (e.g. node-problem-detector), and
@@ -107,20 +107,24 @@ func TestNodeStore(t *testing.T) {
 						{Type: "InternalIP", Address: "1.2.3.4"},
 					},
 					Capacity: v1.ResourceList{
-						v1.ResourceCPU:                    resource.MustParse("4.3"),
-						v1.ResourceMemory:                 resource.MustParse("2G"),
-						v1.ResourcePods:                   resource.MustParse("1000"),
-						v1.ResourceStorage:                resource.MustParse("3G"),
-						v1.ResourceEphemeralStorage:       resource.MustParse("4G"),
-						v1.ResourceName("nvidia.com/gpu"): resource.MustParse("4"),
+						v1.ResourceCPU:                                resource.MustParse("4.3"),
+						v1.ResourceMemory:                             resource.MustParse("2G"),
+						v1.ResourcePods:                               resource.MustParse("1000"),
+						v1.ResourceStorage:                            resource.MustParse("3G"),
+						v1.ResourceEphemeralStorage:                   resource.MustParse("4G"),
+						v1.ResourceName("nvidia.com/gpu"):             resource.MustParse("4"),
+						v1.ResourceName("hugepages-2Mi"):              resource.MustParse("4Mi"),
+						v1.ResourceName("attachable-volumes-aws-ebs"): resource.MustParse("39"),
 					},
 					Allocatable: v1.ResourceList{
-						v1.ResourceCPU:                    resource.MustParse("3"),
-						v1.ResourceMemory:                 resource.MustParse("1G"),
-						v1.ResourcePods:                   resource.MustParse("555"),
-						v1.ResourceStorage:                resource.MustParse("2G"),
-						v1.ResourceEphemeralStorage:       resource.MustParse("3G"),
-						v1.ResourceName("nvidia.com/gpu"): resource.MustParse("1"),
+						v1.ResourceCPU:                                resource.MustParse("3"),
+						v1.ResourceMemory:                             resource.MustParse("1G"),
+						v1.ResourcePods:                               resource.MustParse("555"),
+						v1.ResourceStorage:                            resource.MustParse("2G"),
+						v1.ResourceEphemeralStorage:                   resource.MustParse("3G"),
+						v1.ResourceName("nvidia.com/gpu"):             resource.MustParse("1"),
+						v1.ResourceName("hugepages-2Mi"):              resource.MustParse("2Mi"),
+						v1.ResourceName("attachable-volumes-aws-ebs"): resource.MustParse("39"),
 					},
 				},
 			},
@@ -143,14 +147,18 @@ func TestNodeStore(t *testing.T) {
         kube_node_info{container_runtime_version="rkt",kernel_version="kernel",kubelet_version="kubelet",kubeproxy_version="kubeproxy",node="127.0.0.1",os_image="osimage",pod_cidr="172.24.10.0/24",provider_id="provider://i-randomidentifier",internal_ip="1.2.3.4",system_uuid="6a934e21-5207-4a84-baea-3a952d926c80"} 1
 		kube_node_role{node="127.0.0.1",role="master"} 1
         kube_node_spec_unschedulable{node="127.0.0.1"} 1
+        kube_node_status_allocatable{node="127.0.0.1",resource="attachable_volumes_aws_ebs",unit="byte"} 39
         kube_node_status_allocatable{node="127.0.0.1",resource="cpu",unit="core"} 3
         kube_node_status_allocatable{node="127.0.0.1",resource="ephemeral_storage",unit="byte"} 3e+09
+        kube_node_status_allocatable{node="127.0.0.1",resource="hugepages_2Mi",unit="byte"} 2.097152e+06
         kube_node_status_allocatable{node="127.0.0.1",resource="memory",unit="byte"} 1e+09
         kube_node_status_allocatable{node="127.0.0.1",resource="nvidia_com_gpu",unit="integer"} 1
         kube_node_status_allocatable{node="127.0.0.1",resource="pods",unit="integer"} 555
         kube_node_status_allocatable{node="127.0.0.1",resource="storage",unit="byte"} 2e+09
+        kube_node_status_capacity{node="127.0.0.1",resource="attachable_volumes_aws_ebs",unit="byte"} 39
         kube_node_status_capacity{node="127.0.0.1",resource="cpu",unit="core"} 4.3
         kube_node_status_capacity{node="127.0.0.1",resource="ephemeral_storage",unit="byte"} 4e+09
+        kube_node_status_capacity{node="127.0.0.1",resource="hugepages_2Mi",unit="byte"} 4.194304e+06
         kube_node_status_capacity{node="127.0.0.1",resource="memory",unit="byte"} 2e+09
         kube_node_status_capacity{node="127.0.0.1",resource="nvidia_com_gpu",unit="integer"} 4
         kube_node_status_capacity{node="127.0.0.1",resource="pods",unit="integer"} 1000
@@ -274,6 +282,26 @@ func TestNodeStore(t *testing.T) {
 			`,
 			MetricNames: []string{"kube_node_spec_taint"},
 		},
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "127.0.0.2",
+				},
+				Spec: v1.NodeSpec{
+					Taints: []v1.Taint{
+						{Key: "node.kubernetes.io/unschedulable", Effect: v1.TaintEffectNoSchedule},
+						{Key: "node.kubernetes.io/not-ready", Effect: v1.TaintEffectNoExecute},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_node_spec_taint [STABLE] The taint of a cluster node.
+				# TYPE kube_node_spec_taint gauge
+				kube_node_spec_taint{effect="NoSchedule",key="node.kubernetes.io/unschedulable",node="127.0.0.2",value=""} 1
+				kube_node_spec_taint{effect="NoExecute",key="node.kubernetes.io/not-ready",node="127.0.0.2",value=""} 1
+			`,
+			MetricNames: []string{"kube_node_spec_taint"},
+		},
 		{
 			Obj: &v1.Node{
 				ObjectMeta: metav1.ObjectMeta{
@@ -298,6 +326,24 @@ func TestNodeStore(t *testing.T) {
 					`,
 			MetricNames: []string{"kube_node_status_addresses"},
 		},
+		{
+			Obj: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "127.0.0.1",
+				},
+				Status: v1.NodeStatus{
+					NodeInfo: v1.NodeSystemInfo{
+						ContainerRuntimeVersion: "containerd://1.7.2",
+					},
+				},
+			},
+			Want: `
+				# HELP kube_node_container_runtime_info The container runtime of a cluster node and its version.
+				# TYPE kube_node_container_runtime_info gauge
+				kube_node_container_runtime_info{node="127.0.0.1",runtime="containerd",version="1.7.2"} 1
+			`,
+			MetricNames: []string{"kube_node_container_runtime_info"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(nodeMetricFamilies(nil, nil))
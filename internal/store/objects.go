@@ -0,0 +1,277 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// InventoryObject describes a cached object in the object inventory.
+type InventoryObject struct {
+	Resource  string            `json:"resource"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	// Status holds the key status fields of the object, for the resources
+	// which have any, see objectStatus.
+	Status map[string]string `json:"status,omitempty"`
+}
+
+// objectInventory holds the cached objects of all resources.
+type objectInventory struct {
+	// mtx protects trackers
+	mtx      sync.Mutex
+	trackers map[string]*objectInventoryTracker
+}
+
+func newObjectInventory() *objectInventory {
+	return &objectInventory{trackers: map[string]*objectInventoryTracker{}}
+}
+
+// register returns the tracker of the objects of a resource, replacing the
+// previous one of the resource, e.g. when the stores are rebuilt after a
+// change of the sharding. It returns nil if the inventory is disabled.
+func (i *objectInventory) register(resource string) *objectInventoryTracker {
+	if i == nil {
+		return nil
+	}
+
+	t := &objectInventoryTracker{
+		resource: resource,
+		objects:  map[types.UID]InventoryObject{},
+	}
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	i.trackers[resource] = t
+	return t
+}
+
+// objects returns the objects of the given resource and namespace, sorted by
+// resource, namespace and name. Empty filters match all objects.
+func (i *objectInventory) objects(resource, namespace string) []InventoryObject {
+	i.mtx.Lock()
+	trackers := make([]*objectInventoryTracker, 0, len(i.trackers))
+	for r, t := range i.trackers {
+		if resource == "" || r == resource {
+			trackers = append(trackers, t)
+		}
+	}
+	i.mtx.Unlock()
+
+	objects := []InventoryObject{}
+	for _, t := range trackers {
+		t.mtx.Lock()
+		for _, o := range t.objects {
+			if namespace == "" || o.Namespace == namespace {
+				objects = append(objects, o)
+			}
+		}
+		t.mtx.Unlock()
+	}
+	sort.Slice(objects, func(a, b int) bool {
+		if objects[a].Resource != objects[b].Resource {
+			return objects[a].Resource < objects[b].Resource
+		}
+		if objects[a].Namespace != objects[b].Namespace {
+			return objects[a].Namespace < objects[b].Namespace
+		}
+		return objects[a].Name < objects[b].Name
+	})
+	return objects
+}
+
+// ServeHTTP writes the objects of the inventory as JSON. The objects can be
+// restricted with the resource and namespace query parameters.
+func (i *objectInventory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(i.objects(query.Get("resource"), query.Get("namespace"))); err != nil {
+		klog.ErrorS(err, "Failed to write object inventory")
+	}
+}
+
+// objectInventoryTracker records the objects of a resource.
+type objectInventoryTracker struct {
+	resource string
+
+	// mtx protects objects
+	mtx     sync.Mutex
+	objects map[types.UID]InventoryObject
+}
+
+// wrapStore returns a store which records the objects added to and removed
+// from the given store. The store holds the objects of the given namespace,
+// or of all namespaces if it is empty.
+func (t *objectInventoryTracker) wrapStore(store cache.Store, namespace string) cache.Store {
+	if t == nil {
+		return store
+	}
+
+	return &objectInventoryStore{Store: store, tracker: t, namespace: namespace}
+}
+
+func (t *objectInventoryTracker) set(obj interface{}) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.objects[o.GetUID()] = InventoryObject{
+		Resource:  t.resource,
+		Namespace: o.GetNamespace(),
+		Name:      o.GetName(),
+		Labels:    o.GetLabels(),
+		Status:    objectStatus(obj),
+	}
+}
+
+func (t *objectInventoryTracker) forget(obj interface{}) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	delete(t.objects, o.GetUID())
+}
+
+// forgetNamespace forgets the objects of the given namespace, or all objects
+// if it is empty.
+func (t *objectInventoryTracker) forgetNamespace(namespace string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for uid, o := range t.objects {
+		if namespace == "" || o.Namespace == namespace {
+			delete(t.objects, uid)
+		}
+	}
+}
+
+// objectInventoryStore records the objects which are added to, deleted from
+// or replaced in the wrapped store.
+type objectInventoryStore struct {
+	cache.Store
+	tracker   *objectInventoryTracker
+	namespace string
+}
+
+// Add records the added object.
+func (s *objectInventoryStore) Add(obj interface{}) error {
+	s.tracker.set(obj)
+	return s.Store.Add(obj)
+}
+
+// Update records the updated object.
+func (s *objectInventoryStore) Update(obj interface{}) error {
+	s.tracker.set(obj)
+	return s.Store.Update(obj)
+}
+
+// Delete forgets the deleted object.
+func (s *objectInventoryStore) Delete(obj interface{}) error {
+	s.tracker.forget(deletedObject(obj))
+	return s.Store.Delete(obj)
+}
+
+// Replace forgets all objects of the store and records the ones of the new
+// list.
+func (s *objectInventoryStore) Replace(list []interface{}, resourceVersion string) error {
+	s.tracker.forgetNamespace(s.namespace)
+	for _, obj := range list {
+		s.tracker.set(obj)
+	}
+	return s.Store.Replace(list, resourceVersion)
+}
+
+// objectStatus returns the key status fields of an object, or nil if there
+// are none for its resource.
+func objectStatus(obj interface{}) map[string]string {
+	switch o := obj.(type) {
+	case *v1.Pod:
+		status := map[string]string{"phase": string(o.Status.Phase)}
+		if o.Spec.NodeName != "" {
+			status["node"] = o.Spec.NodeName
+		}
+		for _, c := range o.Status.Conditions {
+			if c.Type == v1.PodReady {
+				status["ready"] = string(c.Status)
+			}
+		}
+		return status
+	case *v1.Node:
+		status := map[string]string{"unschedulable": strconv.FormatBool(o.Spec.Unschedulable)}
+		for _, c := range o.Status.Conditions {
+			if c.Type == v1.NodeReady {
+				status["ready"] = string(c.Status)
+			}
+		}
+		return status
+	case *v1.Namespace:
+		return map[string]string{"phase": string(o.Status.Phase)}
+	case *v1.PersistentVolume:
+		return map[string]string{"phase": string(o.Status.Phase)}
+	case *v1.PersistentVolumeClaim:
+		return map[string]string{"phase": string(o.Status.Phase)}
+	case *appsv1.Deployment:
+		return map[string]string{
+			"replicas":          strconv.Itoa(int(o.Status.Replicas)),
+			"readyReplicas":     strconv.Itoa(int(o.Status.ReadyReplicas)),
+			"availableReplicas": strconv.Itoa(int(o.Status.AvailableReplicas)),
+		}
+	case *appsv1.StatefulSet:
+		return map[string]string{
+			"replicas":      strconv.Itoa(int(o.Status.Replicas)),
+			"readyReplicas": strconv.Itoa(int(o.Status.ReadyReplicas)),
+		}
+	case *appsv1.ReplicaSet:
+		return map[string]string{
+			"replicas":      strconv.Itoa(int(o.Status.Replicas)),
+			"readyReplicas": strconv.Itoa(int(o.Status.ReadyReplicas)),
+		}
+	case *appsv1.DaemonSet:
+		return map[string]string{
+			"desiredNumberScheduled": strconv.Itoa(int(o.Status.DesiredNumberScheduled)),
+			"numberReady":            strconv.Itoa(int(o.Status.NumberReady)),
+		}
+	case *batchv1.Job:
+		return map[string]string{
+			"active":    strconv.Itoa(int(o.Status.Active)),
+			"succeeded": strconv.Itoa(int(o.Status.Succeeded)),
+			"failed":    strconv.Itoa(int(o.Status.Failed)),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestObjectInventory(t *testing.T) {
+	var disabled *objectInventory
+	if tracker := disabled.register("*v1.Pod"); tracker != nil {
+		t.Fatal("expected no tracker if the object inventory is disabled")
+	}
+
+	inventory := newObjectInventory()
+	tracker := inventory.register("*v1.Pod")
+	families := podMetricFamilies(nil, nil)
+	store := tracker.wrapStore(metricsstore.NewMetricsStore(
+		generator.ExtractMetricFamilyHeaders(families),
+		generator.ComposeMetricGenFuncs(families),
+	), v1.NamespaceAll)
+
+	pod1 := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1", UID: "uid1", Labels: map[string]string{"app": "foo"}},
+		Spec:       v1.PodSpec{NodeName: "node1"},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}},
+		},
+	}
+	pod2 := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns2", UID: "uid2"},
+		Status:     v1.PodStatus{Phase: v1.PodPending},
+	}
+	for _, p := range []*v1.Pod{pod2, pod1} {
+		if err := store.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []InventoryObject{
+		{
+			Resource:  "*v1.Pod",
+			Namespace: "ns1",
+			Name:      "pod1",
+			Labels:    map[string]string{"app": "foo"},
+			Status:    map[string]string{"phase": "Running", "node": "node1", "ready": "True"},
+		},
+		{
+			Resource:  "*v1.Pod",
+			Namespace: "ns2",
+			Name:      "pod2",
+			Status:    map[string]string{"phase": "Pending"},
+		},
+	}
+	if got := inventory.objects("", ""); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected objects %v, got %v", want, got)
+	}
+
+	rec := httptest.NewRecorder()
+	inventory.ServeHTTP(rec, httptest.NewRequest("GET", "/objects?namespace=ns2", nil))
+	var got []InventoryObject
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want[1:]) {
+		t.Fatalf("expected objects %v of ns2, got %v", want[1:], got)
+	}
+	if got := inventory.objects("*v1.Node", ""); len(got) != 0 {
+		t.Fatalf("expected no nodes, got %v", got)
+	}
+
+	if err := store.Delete(pod2); err != nil {
+		t.Fatal(err)
+	}
+	if got := inventory.objects("", ""); !reflect.DeepEqual(got, want[:1]) {
+		t.Fatalf("expected objects %v after deleting pod2, got %v", want[:1], got)
+	}
+
+	if err := store.Replace([]interface{}{pod2}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := inventory.objects("", ""); !reflect.DeepEqual(got, want[1:]) {
+		t.Fatalf("expected objects %v after the store was replaced, got %v", want[1:], got)
+	}
+}
@@ -58,7 +58,9 @@ func persistentVolumeMetricFamilies(allowAnnotationsList, allowLabelsList []stri
 		createPersistentVolumeCreated(),
 		createPersistentVolumeDeletionTimestamp(),
 		createPersistentVolumeCSIAttributes(),
+		createPersistentVolumeCSIInfo(),
 		createPersistentVolumeMode(),
+		createPersistentVolumeReclaimPolicy(),
 	}
 }
 
@@ -456,6 +458,60 @@ func createPersistentVolumeCSIAttributes() generator.FamilyGenerator {
 	)
 }
 
+func createPersistentVolumeCSIInfo() generator.FamilyGenerator {
+	return *generator.NewFamilyGeneratorWithStability(
+		"kube_persistentvolume_csi_info",
+		"CSI driver and volume handle of the Persistent Volume.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrapPersistentVolumeFunc(func(p *v1.PersistentVolume) *metric.Family {
+			if p.Spec.CSI == nil {
+				return &metric.Family{
+					Metrics: []*metric.Metric{},
+				}
+			}
+
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"driver", "volume_handle"},
+						LabelValues: []string{p.Spec.CSI.Driver, p.Spec.CSI.VolumeHandle},
+						Value:       1,
+					},
+				},
+			}
+		}),
+	)
+}
+
+func createPersistentVolumeReclaimPolicy() generator.FamilyGenerator {
+	return *generator.NewFamilyGeneratorWithStability(
+		"kube_persistentvolume_reclaim_policy",
+		"The reclaim policy of the Persistent Volume.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrapPersistentVolumeFunc(func(p *v1.PersistentVolume) *metric.Family {
+			if p.Spec.PersistentVolumeReclaimPolicy == "" {
+				return &metric.Family{
+					Metrics: []*metric.Metric{},
+				}
+			}
+
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   []string{"reclaim_policy"},
+						LabelValues: []string{string(p.Spec.PersistentVolumeReclaimPolicy)},
+						Value:       1,
+					},
+				},
+			}
+		}),
+	)
+}
+
 func createPersistentVolumeMode() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_persistentvolume_volume_mode",
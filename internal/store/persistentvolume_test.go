@@ -776,6 +776,66 @@ func TestPersistentVolumeStore(t *testing.T) {
 				`,
 			MetricNames: []string{"kube_persistentvolume_volume_mode"},
 		},
+		{
+			Obj: &v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pv-csi",
+				},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						CSI: &v1.CSIPersistentVolumeSource{
+							Driver:       "test-driver",
+							VolumeHandle: "test-volume-handle",
+						},
+					},
+				},
+			},
+			Want: `
+					# HELP kube_persistentvolume_csi_info CSI driver and volume handle of the Persistent Volume.
+					# TYPE kube_persistentvolume_csi_info gauge
+					kube_persistentvolume_csi_info{persistentvolume="test-pv-csi",driver="test-driver",volume_handle="test-volume-handle"} 1
+				`,
+			MetricNames: []string{"kube_persistentvolume_csi_info"},
+		},
+		{
+			Obj: &v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pv-nfs",
+				},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						NFS: &v1.NFSVolumeSource{
+							Server: "nfs-server",
+							Path:   "/exports",
+						},
+					},
+				},
+			},
+			Want: `
+					# HELP kube_persistentvolume_csi_info CSI driver and volume handle of the Persistent Volume.
+					# TYPE kube_persistentvolume_csi_info gauge
+				`,
+			MetricNames: []string{"kube_persistentvolume_csi_info"},
+		},
+		{
+			Obj: &v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-pv-released",
+				},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+				},
+				Status: v1.PersistentVolumeStatus{
+					Phase: v1.VolumeReleased,
+				},
+			},
+			Want: `
+					# HELP kube_persistentvolume_reclaim_policy The reclaim policy of the Persistent Volume.
+					# TYPE kube_persistentvolume_reclaim_policy gauge
+					kube_persistentvolume_reclaim_policy{persistentvolume="test-pv-released",reclaim_policy="Retain"} 1
+				`,
+			MetricNames: []string{"kube_persistentvolume_reclaim_policy"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(persistentVolumeMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strings"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -112,6 +113,69 @@ func persistentVolumeClaimMetricFamilies(allowAnnotationsList, allowLabelsList [
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_persistentvolumeclaim_spec_volume_mode",
+			"The volume mode of the persistent volume claim.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := []*metric.Metric{}
+
+				if p.Spec.VolumeMode != nil {
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"volume_mode"},
+						LabelValues: []string{string(*p.Spec.VolumeMode)},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_persistentvolumeclaim_datasource_info",
+			"Information about the data source the persistent volume claim is populated from, e.g. a volume snapshot or another claim to clone.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := []*metric.Metric{}
+
+				// DataSourceRef supersedes DataSource and may refer to a source in
+				// another namespace.
+				if ref := p.Spec.DataSourceRef; ref != nil {
+					apiGroup, namespace := "", p.Namespace
+					if ref.APIGroup != nil {
+						apiGroup = *ref.APIGroup
+					}
+					if ref.Namespace != nil {
+						namespace = *ref.Namespace
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"datasource_api_group", "datasource_kind", "datasource_name", "datasource_namespace"},
+						LabelValues: []string{apiGroup, ref.Kind, ref.Name, namespace},
+						Value:       1,
+					})
+				} else if ref := p.Spec.DataSource; ref != nil {
+					apiGroup := ""
+					if ref.APIGroup != nil {
+						apiGroup = *ref.APIGroup
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"datasource_api_group", "datasource_kind", "datasource_name", "datasource_namespace"},
+						LabelValues: []string{apiGroup, ref.Kind, ref.Name, p.Namespace},
+						Value:       1,
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_persistentvolumeclaim_status_phase",
 			"The phase the persistent volume claim is currently in.",
@@ -221,6 +285,31 @@ func persistentVolumeClaimMetricFamilies(allowAnnotationsList, allowLabelsList [
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_persistentvolumeclaim_condition_last_transition_time",
+			"Unix timestamp of the last transition of each condition of the persistent volume claim.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPersistentVolumeClaimFunc(func(p *v1.PersistentVolumeClaim) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, c := range p.Status.Conditions {
+					if c.LastTransitionTime.IsZero() {
+						continue
+					}
+					ms = append(ms, &metric.Metric{
+						LabelKeys:   []string{"condition", "status"},
+						LabelValues: []string{string(c.Type), strings.ToLower(string(c.Status))},
+						Value:       float64(c.LastTransitionTime.Unix()),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_persistentvolumeclaim_created",
 			"Unix creation timestamp",
@@ -23,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -311,6 +312,47 @@ func TestPersistentVolumeClaimStore(t *testing.T) {
 `,
 			MetricNames: []string{"kube_persistentvolumeclaim_deletion_timestamp", "kube_persistentvolumeclaim_status_phase"},
 		},
+		{
+			Obj: &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "restored-data",
+					Namespace: "default",
+				},
+				Spec: v1.PersistentVolumeClaimSpec{
+					VolumeMode: &volumeMode,
+					DataSource: &v1.TypedLocalObjectReference{
+						APIGroup: ptr.To("snapshot.storage.k8s.io"),
+						Kind:     "VolumeSnapshot",
+						Name:     "data-snapshot",
+					},
+					DataSourceRef: &v1.TypedObjectReference{
+						APIGroup:  ptr.To("snapshot.storage.k8s.io"),
+						Kind:      "VolumeSnapshot",
+						Name:      "data-snapshot",
+						Namespace: ptr.To("backups"),
+					},
+				},
+				Status: v1.PersistentVolumeClaimStatus{
+					Phase: v1.ClaimBound,
+					Conditions: []v1.PersistentVolumeClaimCondition{
+						{Type: v1.PersistentVolumeClaimResizing, Status: v1.ConditionTrue, LastTransitionTime: metav1.Time{Time: time.Unix(1600000000, 0)}},
+						{Type: v1.PersistentVolumeClaimFileSystemResizePending, Status: v1.ConditionFalse},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_persistentvolumeclaim_datasource_info Information about the data source the persistent volume claim is populated from, e.g. a volume snapshot or another claim to clone.
+				# HELP kube_persistentvolumeclaim_spec_volume_mode The volume mode of the persistent volume claim.
+				# HELP kube_persistentvolumeclaim_condition_last_transition_time Unix timestamp of the last transition of each condition of the persistent volume claim.
+				# TYPE kube_persistentvolumeclaim_datasource_info gauge
+				# TYPE kube_persistentvolumeclaim_spec_volume_mode gauge
+				# TYPE kube_persistentvolumeclaim_condition_last_transition_time gauge
+				kube_persistentvolumeclaim_datasource_info{datasource_api_group="snapshot.storage.k8s.io",datasource_kind="VolumeSnapshot",datasource_name="data-snapshot",datasource_namespace="backups",namespace="default",persistentvolumeclaim="restored-data"} 1
+				kube_persistentvolumeclaim_spec_volume_mode{namespace="default",persistentvolumeclaim="restored-data",volume_mode="Block"} 1
+				kube_persistentvolumeclaim_condition_last_transition_time{condition="Resizing",namespace="default",persistentvolumeclaim="restored-data",status="true"} 1.6e+09
+`,
+			MetricNames: []string{"kube_persistentvolumeclaim_datasource_info", "kube_persistentvolumeclaim_spec_volume_mode", "kube_persistentvolumeclaim_condition_last_transition_time"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(persistentVolumeClaimMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
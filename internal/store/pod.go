@@ -46,6 +46,7 @@ func podMetricFamilies(allowAnnotationsList, allowLabelsList []string) []generat
 		createPodContainerInfoFamilyGenerator(),
 		createPodContainerResourceLimitsFamilyGenerator(),
 		createPodContainerResourceRequestsFamilyGenerator(),
+		createPodContainerRestartPolicyFamilyGenerator(),
 		createPodContainerStateStartedFamilyGenerator(),
 		createPodContainerStatusLastTerminatedReasonFamilyGenerator(),
 		createPodContainerStatusLastTerminatedExitCodeFamilyGenerator(),
@@ -1169,41 +1170,8 @@ func createPodOwnerFamilyGenerator() generator.FamilyGenerator {
 		basemetrics.STABLE,
 		"",
 		wrapPodFunc(func(p *v1.Pod) *metric.Family {
-			labelKeys := []string{"owner_kind", "owner_name", "owner_is_controller"}
-
-			owners := p.GetOwnerReferences()
-			if len(owners) == 0 {
-				return &metric.Family{
-					Metrics: []*metric.Metric{
-						{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{"", "", ""},
-							Value:       1,
-						},
-					},
-				}
-			}
-
-			ms := make([]*metric.Metric, len(owners))
-
-			for i, owner := range owners {
-				if owner.Controller != nil {
-					ms[i] = &metric.Metric{
-						LabelKeys:   labelKeys,
-						LabelValues: []string{owner.Kind, owner.Name, strconv.FormatBool(*owner.Controller)},
-						Value:       1,
-					}
-				} else {
-					ms[i] = &metric.Metric{
-						LabelKeys:   labelKeys,
-						LabelValues: []string{owner.Kind, owner.Name, "false"},
-						Value:       1,
-					}
-				}
-			}
-
 			return &metric.Family{
-				Metrics: ms,
+				Metrics: ownerMetrics(p.GetOwnerReferences()),
 			}
 		}),
 	)
@@ -1230,6 +1198,34 @@ func createPodRestartPolicyFamilyGenerator() generator.FamilyGenerator {
 	)
 }
 
+func createPodContainerRestartPolicyFamilyGenerator() generator.FamilyGenerator {
+	return *generator.NewFamilyGeneratorWithStability(
+		"kube_pod_container_restart_policy",
+		"Describes the restart policy in use by a container, which is the restart policy of the pod unless set on the container.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrapPodFunc(func(p *v1.Pod) *metric.Family {
+			ms := make([]*metric.Metric, 0, len(p.Spec.Containers))
+			for _, c := range p.Spec.Containers {
+				restartPolicy := string(p.Spec.RestartPolicy)
+				if c.RestartPolicy != nil {
+					restartPolicy = string(*c.RestartPolicy)
+				}
+				ms = append(ms, &metric.Metric{
+					LabelKeys:   []string{"container", "type"},
+					LabelValues: []string{c.Name, restartPolicy},
+					Value:       1,
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	)
+}
+
 func createPodRuntimeClassNameInfoFamilyGenerator() generator.FamilyGenerator {
 	return *generator.NewFamilyGeneratorWithStability(
 		"kube_pod_runtimeclass_name_info",
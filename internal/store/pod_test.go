@@ -1117,6 +1117,34 @@ func TestPodStore(t *testing.T) {
 				`,
 			MetricNames: []string{"kube_pod_restart_policy"},
 		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod2",
+					Namespace: "ns2",
+					UID:       "uid2",
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{
+						{
+							Name: "container1",
+						},
+						{
+							Name:          "container2",
+							RestartPolicy: &restartPolicyAlways,
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_container_restart_policy Describes the restart policy in use by a container, which is the restart policy of the pod unless set on the container.
+				# TYPE kube_pod_container_restart_policy gauge
+				kube_pod_container_restart_policy{container="container1",namespace="ns2",pod="pod2",type="Never",uid="uid2"} 1
+				kube_pod_container_restart_policy{container="container2",namespace="ns2",pod="pod2",type="Always",uid="uid2"} 1
+				`,
+			MetricNames: []string{"kube_pod_container_restart_policy"},
+		},
 		{
 			Obj: &v1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1326,6 +1354,40 @@ func TestPodStore(t *testing.T) {
 				kube_pod_status_qos_class{namespace="ns1",qos_class="BestEffort",pod="pod1",uid="uid1"} 1
 				kube_pod_status_qos_class{namespace="ns1",qos_class="Burstable",pod="pod1",uid="uid1"} 0
 				kube_pod_status_qos_class{namespace="ns1",qos_class="Guaranteed",pod="pod1",uid="uid1"} 0
+`,
+			MetricNames: []string{"kube_pod_status_qos_class"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod2",
+					Namespace: "ns2",
+					UID:       "uid2",
+				},
+				Status: v1.PodStatus{
+					QOSClass: v1.PodQOSGuaranteed,
+				},
+			},
+			Want: `
+				# HELP kube_pod_status_qos_class The pods current qosClass.
+				# TYPE kube_pod_status_qos_class gauge
+				kube_pod_status_qos_class{namespace="ns2",qos_class="BestEffort",pod="pod2",uid="uid2"} 0
+				kube_pod_status_qos_class{namespace="ns2",qos_class="Burstable",pod="pod2",uid="uid2"} 0
+				kube_pod_status_qos_class{namespace="ns2",qos_class="Guaranteed",pod="pod2",uid="uid2"} 1
+`,
+			MetricNames: []string{"kube_pod_status_qos_class"},
+		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod3",
+					Namespace: "ns3",
+					UID:       "uid3",
+				},
+			},
+			Want: `
+				# HELP kube_pod_status_qos_class The pods current qosClass.
+				# TYPE kube_pod_status_qos_class gauge
 `,
 			MetricNames: []string{"kube_pod_status_qos_class"},
 		},
@@ -1977,6 +2039,34 @@ func TestPodStore(t *testing.T) {
 				"kube_pod_runtimeclass_name_info",
 			},
 		},
+		{
+			Obj: &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod1",
+					Namespace: "ns1",
+					UID:       "uid1",
+				},
+				Spec: v1.PodSpec{
+					RuntimeClassName: &runtimeclass,
+					Overhead: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("250m"),
+						v1.ResourceMemory: resource.MustParse("120Mi"),
+					},
+				},
+			},
+			Want: `
+				# HELP kube_pod_overhead_cpu_cores The pod overhead in regards to cpu cores associated with running a pod.
+				# HELP kube_pod_overhead_memory_bytes The pod overhead in regards to memory associated with running a pod.
+				# TYPE kube_pod_overhead_cpu_cores gauge
+				# TYPE kube_pod_overhead_memory_bytes gauge
+				kube_pod_overhead_cpu_cores{namespace="ns1",pod="pod1",uid="uid1"} 0.25
+				kube_pod_overhead_memory_bytes{namespace="ns1",pod="pod1",uid="uid1"} 1.2582912e+08
+			`,
+			MetricNames: []string{
+				"kube_pod_overhead_cpu_cores",
+				"kube_pod_overhead_memory_bytes",
+			},
+		},
 		{
 			Obj: &v1.Pod{
 				ObjectMeta: metav1.ObjectMeta{
@@ -2275,7 +2365,7 @@ func BenchmarkPodStore(b *testing.B) {
 		},
 	}
 
-	expectedFamilies := 54
+	expectedFamilies := 56
 	for n := 0; n < b.N; n++ {
 		families := f(pod)
 		if len(families) != expectedFamilies {
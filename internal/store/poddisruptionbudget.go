@@ -18,10 +18,13 @@ package store
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -185,7 +188,104 @@ func podDisruptionBudgetMetricFamilies(allowAnnotationsList, allowLabelsList []s
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_poddisruptionbudget_info",
+			"Information about the poddisruptionbudget.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPodDisruptionBudgetFunc(func(p *policyv1.PodDisruptionBudget) *metric.Family {
+				unhealthyPodEvictionPolicy := ""
+				if p.Spec.UnhealthyPodEvictionPolicy != nil {
+					unhealthyPodEvictionPolicy = string(*p.Spec.UnhealthyPodEvictionPolicy)
+				}
+
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"unhealthy_pod_eviction_policy"},
+							LabelValues: []string{unhealthyPodEvictionPolicy},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_poddisruptionbudget_spec_min_available",
+			"Minimum number of pods which must be available after an eviction, if given as a number.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPodDisruptionBudgetFunc(func(p *policyv1.PodDisruptionBudget) *metric.Family {
+				return &metric.Family{
+					Metrics: intOrPercentMetrics(p.Spec.MinAvailable, false),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_poddisruptionbudget_spec_min_available_percent",
+			"Minimum percentage of pods which must be available after an eviction, if given as a percentage.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPodDisruptionBudgetFunc(func(p *policyv1.PodDisruptionBudget) *metric.Family {
+				return &metric.Family{
+					Metrics: intOrPercentMetrics(p.Spec.MinAvailable, true),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_poddisruptionbudget_spec_max_unavailable",
+			"Maximum number of pods which can be unavailable after an eviction, if given as a number.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPodDisruptionBudgetFunc(func(p *policyv1.PodDisruptionBudget) *metric.Family {
+				return &metric.Family{
+					Metrics: intOrPercentMetrics(p.Spec.MaxUnavailable, false),
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_poddisruptionbudget_spec_max_unavailable_percent",
+			"Maximum percentage of pods which can be unavailable after an eviction, if given as a percentage.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapPodDisruptionBudgetFunc(func(p *policyv1.PodDisruptionBudget) *metric.Family {
+				return &metric.Family{
+					Metrics: intOrPercentMetrics(p.Spec.MaxUnavailable, true),
+				}
+			}),
+		),
+	}
+}
+
+// intOrPercentMetrics returns the value of the given number or percentage.
+// Percentages are only returned if percent is true and numbers only if it is
+// false, so that each is exposed by its own family.
+func intOrPercentMetrics(v *intstr.IntOrString, percent bool) []*metric.Metric {
+	if v == nil {
+		return []*metric.Metric{}
+	}
+
+	if v.Type == intstr.Int {
+		if percent {
+			return []*metric.Metric{}
+		}
+		return []*metric.Metric{{Value: float64(v.IntVal)}}
+	}
+
+	p, ok := strings.CutSuffix(v.StrVal, "%")
+	if !percent || !ok {
+		return []*metric.Metric{}
+	}
+	value, err := strconv.ParseFloat(p, 64)
+	if err != nil {
+		return []*metric.Metric{}
 	}
+	return []*metric.Metric{{Value: value}}
 }
 
 func wrapPodDisruptionBudgetFunc(f func(*policyv1.PodDisruptionBudget) *metric.Family) func(interface{}) *metric.Family {
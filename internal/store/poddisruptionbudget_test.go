@@ -22,6 +22,8 @@ import (
 
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -48,6 +50,16 @@ func TestPodDisruptionBudgetStore(t *testing.T) {
 	# TYPE kube_poddisruptionbudget_status_expected_pods gauge
 	# HELP kube_poddisruptionbudget_status_observed_generation [STABLE] Most recent generation observed when updating this PDB status
 	# TYPE kube_poddisruptionbudget_status_observed_generation gauge
+	# HELP kube_poddisruptionbudget_info Information about the poddisruptionbudget.
+	# TYPE kube_poddisruptionbudget_info gauge
+	# HELP kube_poddisruptionbudget_spec_min_available Minimum number of pods which must be available after an eviction, if given as a number.
+	# TYPE kube_poddisruptionbudget_spec_min_available gauge
+	# HELP kube_poddisruptionbudget_spec_min_available_percent Minimum percentage of pods which must be available after an eviction, if given as a percentage.
+	# TYPE kube_poddisruptionbudget_spec_min_available_percent gauge
+	# HELP kube_poddisruptionbudget_spec_max_unavailable Maximum number of pods which can be unavailable after an eviction, if given as a number.
+	# TYPE kube_poddisruptionbudget_spec_max_unavailable gauge
+	# HELP kube_poddisruptionbudget_spec_max_unavailable_percent Maximum percentage of pods which can be unavailable after an eviction, if given as a percentage.
+	# TYPE kube_poddisruptionbudget_spec_max_unavailable_percent gauge
 	`
 	cases := []generateMetricsTestCase{
 		{
@@ -68,6 +80,7 @@ func TestPodDisruptionBudgetStore(t *testing.T) {
 			},
 			Want: metadata + `
 			kube_poddisruptionbudget_created{namespace="ns1",poddisruptionbudget="pdb1"} 1.5e+09
+			kube_poddisruptionbudget_info{namespace="ns1",poddisruptionbudget="pdb1",unhealthy_pod_eviction_policy=""} 1
 			kube_poddisruptionbudget_status_current_healthy{namespace="ns1",poddisruptionbudget="pdb1"} 12
 			kube_poddisruptionbudget_status_desired_healthy{namespace="ns1",poddisruptionbudget="pdb1"} 10
 			kube_poddisruptionbudget_status_pod_disruptions_allowed{namespace="ns1",poddisruptionbudget="pdb1"} 2
@@ -91,6 +104,7 @@ func TestPodDisruptionBudgetStore(t *testing.T) {
 				},
 			},
 			Want: metadata + `
+				kube_poddisruptionbudget_info{namespace="ns2",poddisruptionbudget="pdb2",unhealthy_pod_eviction_policy=""} 1
 				kube_poddisruptionbudget_status_current_healthy{namespace="ns2",poddisruptionbudget="pdb2"} 8
 				kube_poddisruptionbudget_status_desired_healthy{namespace="ns2",poddisruptionbudget="pdb2"} 9
 				kube_poddisruptionbudget_status_pod_disruptions_allowed{namespace="ns2",poddisruptionbudget="pdb2"} 0
@@ -128,6 +142,39 @@ func TestPodDisruptionBudgetStore(t *testing.T) {
 				"kube_poddisruptionbudget_labels",
 			},
 		},
+		{
+			Obj: &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pdb3",
+					Namespace: "ns3",
+				},
+				Spec: policyv1.PodDisruptionBudgetSpec{
+					MinAvailable:               ptr.To(intstr.FromInt32(2)),
+					MaxUnavailable:             ptr.To(intstr.FromString("25%")),
+					UnhealthyPodEvictionPolicy: ptr.To(policyv1.AlwaysAllow),
+				},
+			},
+			Want: `
+				# HELP kube_poddisruptionbudget_info Information about the poddisruptionbudget.
+				# TYPE kube_poddisruptionbudget_info gauge
+				# HELP kube_poddisruptionbudget_spec_min_available Minimum number of pods which must be available after an eviction, if given as a number.
+				# TYPE kube_poddisruptionbudget_spec_min_available gauge
+				# HELP kube_poddisruptionbudget_spec_min_available_percent Minimum percentage of pods which must be available after an eviction, if given as a percentage.
+				# TYPE kube_poddisruptionbudget_spec_min_available_percent gauge
+				# HELP kube_poddisruptionbudget_spec_max_unavailable Maximum number of pods which can be unavailable after an eviction, if given as a number.
+				# TYPE kube_poddisruptionbudget_spec_max_unavailable gauge
+				# HELP kube_poddisruptionbudget_spec_max_unavailable_percent Maximum percentage of pods which can be unavailable after an eviction, if given as a percentage.
+				# TYPE kube_poddisruptionbudget_spec_max_unavailable_percent gauge
+				kube_poddisruptionbudget_info{namespace="ns3",poddisruptionbudget="pdb3",unhealthy_pod_eviction_policy="AlwaysAllow"} 1
+				kube_poddisruptionbudget_spec_min_available{namespace="ns3",poddisruptionbudget="pdb3"} 2
+				kube_poddisruptionbudget_spec_max_unavailable_percent{namespace="ns3",poddisruptionbudget="pdb3"} 25
+			`,
+			MetricNames: []string{
+				"kube_poddisruptionbudget_info",
+				"kube_poddisruptionbudget_spec_min_available",
+				"kube_poddisruptionbudget_spec_max_unavailable",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(podDisruptionBudgetMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
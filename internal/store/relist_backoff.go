@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	relistBackoffInitial = time.Second
+	relistBackoffMax     = 30 * time.Second
+	// relistBackoffReset is the time after the last list after which the
+	// backoff starts over from relistBackoffInitial.
+	relistBackoffReset = 5 * time.Minute
+)
+
+// relistBackoffListWatch delays the relists of a reflector, e.g. after its
+// watch expired with 410 Gone, by an exponentially growing and jittered
+// duration. The reflector already backs off between failed attempts, but
+// after an apiserver restart every shard relists at about the same time; the
+// jitter spreads these relists out. The initial list isn't delayed.
+type relistBackoffListWatch struct {
+	lw cache.ListerWatcher
+
+	// mtx protects ctx, listed, lastList and delay
+	mtx      sync.Mutex
+	ctx      context.Context
+	listed   bool
+	lastList time.Time
+	delay    time.Duration
+}
+
+func newRelistBackoffListWatch(ctx context.Context, lw cache.ListerWatcher) *relistBackoffListWatch {
+	return &relistBackoffListWatch{ctx: ctx, lw: lw}
+}
+
+// setContext sets the context whose cancellation ends the delay of the
+// relists, i.e. the one of the reflector currently running.
+func (l *relistBackoffListWatch) setContext(ctx context.Context) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.ctx = ctx
+}
+
+func (l *relistBackoffListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	l.mtx.Lock()
+	ctx := l.ctx
+	l.mtx.Unlock()
+	if d := l.nextDelay(time.Now()); d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+	return l.lw.List(options)
+}
+
+func (l *relistBackoffListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return l.lw.Watch(options)
+}
+
+// nextDelay returns the duration to wait before a list started at the given
+// time.
+func (l *relistBackoffListWatch) nextDelay(now time.Time) time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if !l.listed {
+		l.listed = true
+		l.lastList = now
+		return 0
+	}
+
+	switch {
+	case l.delay == 0 || now.Sub(l.lastList) > relistBackoffReset:
+		l.delay = relistBackoffInitial
+	case l.delay < relistBackoffMax:
+		l.delay = min(2*l.delay, relistBackoffMax)
+	}
+	l.lastList = now
+	return wait.Jitter(l.delay/2, 1.0)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRelistBackoff(t *testing.T) {
+	l := newRelistBackoffListWatch(context.Background(), nil)
+	now := time.Now()
+
+	if d := l.nextDelay(now); d != 0 {
+		t.Fatalf("expected the initial list not to be delayed, got %v", d)
+	}
+
+	// The delays of subsequent relists are jittered within [delay/2, delay).
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second} {
+		now = now.Add(time.Minute)
+		d := l.nextDelay(now)
+		if d < want/2 || d >= want {
+			t.Errorf("expected a delay within [%v, %v), got %v", want/2, want, d)
+		}
+	}
+
+	now = now.Add(relistBackoffReset + time.Second)
+	if d := l.nextDelay(now); d >= relistBackoffInitial {
+		t.Errorf("expected the backoff to start over after %v, got %v", relistBackoffReset, d)
+	}
+}
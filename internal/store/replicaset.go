@@ -18,7 +18,6 @@ package store
 
 import (
 	"context"
-	"strconv"
 
 	basemetrics "k8s.io/component-base/metrics"
 
@@ -171,41 +170,8 @@ func replicaSetMetricFamilies(allowAnnotationsList, allowLabelsList []string) []
 			basemetrics.STABLE,
 			"",
 			wrapReplicaSetFunc(func(r *v1.ReplicaSet) *metric.Family {
-				owners := r.GetOwnerReferences()
-
-				if len(owners) == 0 {
-					return &metric.Family{
-						Metrics: []*metric.Metric{
-							{
-								LabelKeys:   []string{"owner_kind", "owner_name", "owner_is_controller"},
-								LabelValues: []string{"", "", ""},
-								Value:       1,
-							},
-						},
-					}
-				}
-
-				ms := make([]*metric.Metric, len(owners))
-
-				for i, owner := range owners {
-					if owner.Controller != nil {
-						ms[i] = &metric.Metric{
-							LabelValues: []string{owner.Kind, owner.Name, strconv.FormatBool(*owner.Controller)},
-						}
-					} else {
-						ms[i] = &metric.Metric{
-							LabelValues: []string{owner.Kind, owner.Name, "false"},
-						}
-					}
-				}
-
-				for _, m := range ms {
-					m.LabelKeys = []string{"owner_kind", "owner_name", "owner_is_controller"}
-					m.Value = 1
-				}
-
 				return &metric.Family{
-					Metrics: ms,
+					Metrics: ownerMetrics(r.GetOwnerReferences()),
 				}
 			}),
 		),
@@ -18,7 +18,6 @@ package store
 
 import (
 	"context"
-	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -179,33 +178,8 @@ var (
 			basemetrics.ALPHA,
 			"",
 			wrapReplicationControllerFunc(func(r *v1.ReplicationController) *metric.Family {
-				labelKeys := []string{"owner_kind", "owner_name", "owner_is_controller"}
-				ms := []*metric.Metric{}
-
-				owners := r.GetOwnerReferences()
-				if len(owners) == 0 {
-					ms = append(ms, &metric.Metric{
-						LabelKeys:   labelKeys,
-						LabelValues: []string{"", "", ""},
-						Value:       1,
-					})
-				} else {
-					for _, owner := range owners {
-						ownerIsController := "false"
-						if owner.Controller != nil {
-							ownerIsController = strconv.FormatBool(*owner.Controller)
-						}
-
-						ms = append(ms, &metric.Metric{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{owner.Kind, owner.Name, ownerIsController},
-							Value:       1,
-						})
-					}
-				}
-
 				return &metric.Family{
-					Metrics: ms,
+					Metrics: ownerMetrics(r.GetOwnerReferences()),
 				}
 			}),
 		),
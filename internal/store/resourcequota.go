@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -93,6 +94,39 @@ func resourceQuotaMetricFamilies(allowAnnotationsList, allowLabelsList []string)
 				}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_resourcequota_scope",
+			"The scopes and scope selector match expressions of the resource quota.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapResourceQuotaFunc(func(r *v1.ResourceQuota) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, scope := range r.Spec.Scopes {
+					ms = append(ms, &metric.Metric{
+						LabelValues: []string{string(scope), "", ""},
+						Value:       1,
+					})
+				}
+				if r.Spec.ScopeSelector != nil {
+					for _, expr := range r.Spec.ScopeSelector.MatchExpressions {
+						ms = append(ms, &metric.Metric{
+							LabelValues: []string{string(expr.ScopeName), string(expr.Operator), strings.Join(expr.Values, ",")},
+							Value:       1,
+						})
+					}
+				}
+
+				for _, m := range ms {
+					m.LabelKeys = []string{"scope", "operator", "values"}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			descResourceQuotaAnnotationsName,
 			descResourceQuotaAnnotationsHelp,
@@ -39,6 +39,8 @@ func TestResourceQuotaStore(t *testing.T) {
 	# TYPE kube_resourcequota_annotations gauge
 	# TYPE kube_resourcequota_created gauge
 	# TYPE kube_resourcequota_labels gauge
+	# HELP kube_resourcequota_scope The scopes and scope selector match expressions of the resource quota.
+	# TYPE kube_resourcequota_scope gauge
 	`
 	cases := []generateMetricsTestCase{
 		// Verify populating base metric and that metric for unset fields are skipped.
@@ -164,6 +166,31 @@ func TestResourceQuotaStore(t *testing.T) {
 			kube_resourcequota_labels{label_hello="world",namespace="testNS",resourcequota="quotaTest"} 1
 			`,
 		},
+		// Verify scope metric.
+		{
+			Obj: &v1.ResourceQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "quotaTest",
+					Namespace: "testNS",
+				},
+				Spec: v1.ResourceQuotaSpec{
+					Scopes: []v1.ResourceQuotaScope{v1.ResourceQuotaScopeBestEffort},
+					ScopeSelector: &v1.ScopeSelector{
+						MatchExpressions: []v1.ScopedResourceSelectorRequirement{
+							{
+								ScopeName: v1.ResourceQuotaScopePriorityClass,
+								Operator:  v1.ScopeSelectorOpIn,
+								Values:    []string{"high", "medium"},
+							},
+						},
+					},
+				},
+			},
+			Want: metadata + `
+			kube_resourcequota_scope{namespace="testNS",resourcequota="quotaTest",operator="",scope="BestEffort",values=""} 1
+			kube_resourcequota_scope{namespace="testNS",resourcequota="quotaTest",operator="In",scope="PriorityClass",values="high,medium"} 1
+			`,
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(resourceQuotaMetricFamilies(c.AllowAnnotationsList, c.AllowLabelsList))
@@ -18,7 +18,6 @@ package store
 
 import (
 	"context"
-	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -161,42 +160,57 @@ func secretMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gene
 			basemetrics.ALPHA,
 			"",
 			wrapSecretFunc(func(j *v1.Secret) *metric.Family {
-				labelKeys := []string{"owner_kind", "owner_name", "owner_is_controller"}
-
-				owners := j.GetOwnerReferences()
-
-				if len(owners) == 0 {
-					return &metric.Family{
-						Metrics: []*metric.Metric{
-							{
-								LabelKeys:   labelKeys,
-								LabelValues: []string{"", "", ""},
-								Value:       1,
-							},
-						},
-					}
+				return &metric.Family{
+					Metrics: ownerMetrics(j.GetOwnerReferences()),
 				}
-
-				ms := make([]*metric.Metric, len(owners))
-
-				for i, owner := range owners {
-					if owner.Controller != nil {
-						ms[i] = &metric.Metric{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{owner.Kind, owner.Name, strconv.FormatBool(*owner.Controller)},
-							Value:       1,
-						}
-					} else {
-						ms[i] = &metric.Metric{
-							LabelKeys:   labelKeys,
-							LabelValues: []string{owner.Kind, owner.Name, "false"},
-							Value:       1,
-						}
-					}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_secret_object_size_bytes",
+			"The size in bytes of the secret as serialized by the API server.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSecretFunc(func(s *v1.Secret) *metric.Family {
+				return &metric.Family{
+					Metrics: objectSizeMetrics(s),
+				}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_secret_data_bytes",
+			"The total size in bytes of the values in the data of the secret.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSecretFunc(func(s *v1.Secret) *metric.Family {
+				size := 0
+				for _, v := range s.Data {
+					size += len(v)
 				}
 
 				return &metric.Family{
-					Metrics: ms,
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(size),
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewOptInFamilyGenerator(
+			"kube_secret_data_keys",
+			"The number of keys in the data of the secret.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSecretFunc(func(s *v1.Secret) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							Value: float64(len(s.Data)),
+						},
+					},
 				}
 			}),
 		),
@@ -160,6 +160,28 @@ func TestSecretStore(t *testing.T) {
 `,
 			MetricNames: []string{"kube_secret_info", "kube_secret_metadata_resource_version", "kube_secret_created", "kube_secret_labels", "kube_secret_type", "kube_secret_owner"},
 		},
+		{
+			Obj: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret5",
+					Namespace: "ns5",
+				},
+				Data: map[string][]byte{
+					"username": []byte("admin"),
+					"password": []byte("s3cr3t!"),
+				},
+				Type: v1.SecretTypeBasicAuth,
+			},
+			Want: `
+				# HELP kube_secret_data_bytes The total size in bytes of the values in the data of the secret.
+				# HELP kube_secret_data_keys The number of keys in the data of the secret.
+				# TYPE kube_secret_data_bytes gauge
+				# TYPE kube_secret_data_keys gauge
+				kube_secret_data_bytes{namespace="ns5",secret="secret5"} 12
+				kube_secret_data_keys{namespace="ns5",secret="secret5"} 2
+`,
+			MetricNames: []string{"kube_secret_data_bytes", "kube_secret_data_keys"},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(secretMetricFamilies(nil, nil))
@@ -18,6 +18,7 @@ package store
 
 import (
 	"context"
+	"strconv"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -128,6 +129,64 @@ func serviceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 				return &metric.Family{Metrics: []*metric.Metric{&m}}
 			}),
 		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_service_spec_session_affinity",
+			"Session affinity of the service.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				if s.Spec.SessionAffinity == "" {
+					return &metric.Family{Metrics: []*metric.Metric{}}
+				}
+				m := metric.Metric{
+					LabelKeys:   []string{"session_affinity"},
+					LabelValues: []string{string(s.Spec.SessionAffinity)},
+					Value:       1,
+				}
+				return &metric.Family{Metrics: []*metric.Metric{&m}}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_service_spec_external_traffic_policy",
+			"External traffic policy of the service.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				if s.Spec.ExternalTrafficPolicy == "" {
+					return &metric.Family{Metrics: []*metric.Metric{}}
+				}
+				m := metric.Metric{
+					LabelKeys:   []string{"external_traffic_policy"},
+					LabelValues: []string{string(s.Spec.ExternalTrafficPolicy)},
+					Value:       1,
+				}
+				return &metric.Family{Metrics: []*metric.Metric{&m}}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_service_spec_ip_families",
+			"IP families of the service. One series for each family",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				ms := make([]*metric.Metric, len(s.Spec.IPFamilies))
+
+				for i, family := range s.Spec.IPFamilies {
+					ms[i] = &metric.Metric{
+						LabelKeys:   []string{"ip_family"},
+						LabelValues: []string{string(family)},
+						Value:       1,
+					}
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
 		*generator.NewFamilyGeneratorWithStability(
 			"kube_service_spec_external_ip",
 			"Service external ips. One series for each ip",
@@ -179,6 +238,34 @@ func serviceMetricFamilies(allowAnnotationsList, allowLabelsList []string) []gen
 					}
 				}
 
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			"kube_service_status_load_balancer_ingress_ports",
+			"Service load balancer ingress ports status. One series for each port of each ingress",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapSvcFunc(func(s *v1.Service) *metric.Family {
+				ms := []*metric.Metric{}
+
+				for _, ingress := range s.Status.LoadBalancer.Ingress {
+					for _, port := range ingress.Ports {
+						portError := ""
+						if port.Error != nil {
+							portError = *port.Error
+						}
+						ms = append(ms, &metric.Metric{
+							LabelKeys:   []string{"ip", "hostname", "port", "protocol", "error"},
+							LabelValues: []string{ingress.IP, ingress.Hostname, strconv.FormatInt(int64(port.Port), 10), string(port.Protocol), portError},
+							Value:       1,
+						})
+					}
+				}
+
 				return &metric.Family{
 					Metrics: ms,
 				}
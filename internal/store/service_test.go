@@ -22,6 +22,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
@@ -40,10 +41,18 @@ func TestServiceStore(t *testing.T) {
 		# TYPE kube_service_labels gauge
 		# HELP kube_service_spec_type [STABLE] Type about service.
 		# TYPE kube_service_spec_type gauge
+		# HELP kube_service_spec_session_affinity Session affinity of the service.
+		# TYPE kube_service_spec_session_affinity gauge
+		# HELP kube_service_spec_external_traffic_policy External traffic policy of the service.
+		# TYPE kube_service_spec_external_traffic_policy gauge
+		# HELP kube_service_spec_ip_families IP families of the service. One series for each family
+		# TYPE kube_service_spec_ip_families gauge
 		# HELP kube_service_spec_external_ip [STABLE] Service external ips. One series for each ip
 		# TYPE kube_service_spec_external_ip gauge
 		# HELP kube_service_status_load_balancer_ingress [STABLE] Service load balancer ingress status
 		# TYPE kube_service_status_load_balancer_ingress gauge
+		# HELP kube_service_status_load_balancer_ingress_ports Service load balancer ingress ports status. One series for each port of each ingress
+		# TYPE kube_service_status_load_balancer_ingress_ports gauge
 	`
 	cases := []generateMetricsTestCase{
 		{
@@ -212,6 +221,56 @@ func TestServiceStore(t *testing.T) {
 				kube_service_spec_external_ip{external_ip="1.2.3.10",namespace="default",service="test-service6",uid="uid6"} 1
 			`,
 		},
+		{
+			Obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-service-dual-stack",
+					Namespace: "default",
+					UID:       "uid7",
+				},
+				Spec: v1.ServiceSpec{
+					Type:                  v1.ServiceTypeLoadBalancer,
+					SessionAffinity:       v1.ServiceAffinityClientIP,
+					ExternalTrafficPolicy: v1.ServiceExternalTrafficPolicyLocal,
+					IPFamilies:            []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol},
+				},
+				Status: v1.ServiceStatus{
+					LoadBalancer: v1.LoadBalancerStatus{
+						Ingress: []v1.LoadBalancerIngress{
+							{
+								IP: "1.2.3.9",
+								Ports: []v1.PortStatus{
+									{Port: 80, Protocol: v1.ProtocolTCP},
+									{Port: 443, Protocol: v1.ProtocolTCP, Error: ptr.To("CertificateError")},
+								},
+							},
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_service_spec_external_traffic_policy External traffic policy of the service.
+				# HELP kube_service_spec_ip_families IP families of the service. One series for each family
+				# HELP kube_service_spec_session_affinity Session affinity of the service.
+				# HELP kube_service_status_load_balancer_ingress_ports Service load balancer ingress ports status. One series for each port of each ingress
+				# TYPE kube_service_spec_external_traffic_policy gauge
+				# TYPE kube_service_spec_ip_families gauge
+				# TYPE kube_service_spec_session_affinity gauge
+				# TYPE kube_service_status_load_balancer_ingress_ports gauge
+				kube_service_spec_external_traffic_policy{external_traffic_policy="Local",namespace="default",service="test-service-dual-stack",uid="uid7"} 1
+				kube_service_spec_ip_families{ip_family="IPv4",namespace="default",service="test-service-dual-stack",uid="uid7"} 1
+				kube_service_spec_ip_families{ip_family="IPv6",namespace="default",service="test-service-dual-stack",uid="uid7"} 1
+				kube_service_spec_session_affinity{namespace="default",service="test-service-dual-stack",session_affinity="ClientIP",uid="uid7"} 1
+				kube_service_status_load_balancer_ingress_ports{error="",hostname="",ip="1.2.3.9",namespace="default",port="80",protocol="TCP",service="test-service-dual-stack",uid="uid7"} 1
+				kube_service_status_load_balancer_ingress_ports{error="CertificateError",hostname="",ip="1.2.3.9",namespace="default",port="443",protocol="TCP",service="test-service-dual-stack",uid="uid7"} 1
+`,
+			MetricNames: []string{
+				"kube_service_spec_external_traffic_policy",
+				"kube_service_spec_ip_families",
+				"kube_service_spec_session_affinity",
+				"kube_service_status_load_balancer_ingress_ports",
+			},
+		},
 	}
 	for i, c := range cases {
 		c.Func = generator.ComposeMetricGenFuncs(serviceMetricFamilies(nil, nil))
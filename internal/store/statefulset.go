@@ -219,7 +219,6 @@ func statefulSetMetricFamilies(allowAnnotationsList, allowLabelsList []string) [
 			"Count of retention policy for StatefulSet template PVCs",
 			metric.Gauge,
 			basemetrics.ALPHA,
-
 			"",
 			wrapStatefulSetFunc(func(s *v1.StatefulSet) *metric.Family {
 				deletedPolicyLabel := ""
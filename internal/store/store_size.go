@@ -0,0 +1,241 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+// storeSizeMetrics stores the pointer of the kube_state_metrics_objects
+// metric and the collector of the kube_state_metrics_store_bytes metric.
+type storeSizeMetrics struct {
+	Objects *prometheus.GaugeVec
+	Bytes   *storeBytesCollector
+}
+
+// newStoreSizeMetrics takes in a prometheus registry and initializes and
+// registers the store size metrics. It returns those registered metrics.
+func newStoreSizeMetrics(r prometheus.Registerer) *storeSizeMetrics {
+	bytes := &storeBytesCollector{
+		desc: prometheus.NewDesc(
+			"kube_state_metrics_store_bytes",
+			"Estimated number of bytes of the metrics held in memory for a resource.",
+			[]string{"resource"}, nil,
+		),
+		stores: map[string][]*metricsstore.MetricsStore{},
+	}
+	r.MustRegister(bytes)
+
+	return &storeSizeMetrics{
+		Objects: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_objects",
+				Help: "Number of objects of a resource cached by kube-state-metrics, per namespace.",
+			},
+			[]string{"resource", "namespace"},
+		),
+		Bytes: bytes,
+	}
+}
+
+// storeBytesCollector computes the size of the stores of each resource when
+// it is collected, so the stores don't need to account it on every change.
+type storeBytesCollector struct {
+	desc *prometheus.Desc
+
+	// mtx protects stores
+	mtx    sync.Mutex
+	stores map[string][]*metricsstore.MetricsStore
+}
+
+// register sets the stores of the given resource, replacing the previous ones
+// of the resource, e.g. when the stores are rebuilt after a change of the
+// sharding.
+func (c *storeBytesCollector) register(resource string, stores []cache.Store) {
+	metricsStores := make([]*metricsstore.MetricsStore, 0, len(stores))
+	for _, s := range stores {
+		if ms, ok := s.(*metricsstore.MetricsStore); ok {
+			metricsStores = append(metricsStores, ms)
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.stores[resource] = metricsStores
+}
+
+// Describe implements the prometheus.Collector interface.
+func (c *storeBytesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (c *storeBytesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for resource, stores := range c.stores {
+		bytes := 0
+		for _, s := range stores {
+			bytes += s.Bytes()
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(bytes), resource)
+	}
+}
+
+// objectCountTracker counts the objects of a resource per namespace.
+type objectCountTracker struct {
+	resource string
+	metrics  *storeSizeMetrics
+
+	// mtx protects objects and totals
+	mtx     sync.Mutex
+	objects map[types.UID]string
+	totals  map[string]int
+}
+
+// newObjectCountTracker returns an objectCountTracker for the given resource,
+// forgetting the counts of previously built stores of the resource. It
+// returns nil if there are no metrics to report to.
+func newObjectCountTracker(resource string, metrics *storeSizeMetrics) *objectCountTracker {
+	if metrics == nil {
+		return nil
+	}
+
+	metrics.Objects.DeletePartialMatch(prometheus.Labels{"resource": resource})
+	return &objectCountTracker{
+		resource: resource,
+		metrics:  metrics,
+		objects:  map[types.UID]string{},
+		totals:   map[string]int{},
+	}
+}
+
+// wrapStore returns a store which counts the objects added to and removed
+// from the given store. The store holds the objects of the given namespace,
+// or of all namespaces if it is empty.
+func (t *objectCountTracker) wrapStore(store cache.Store, namespace string) cache.Store {
+	if t == nil {
+		return store
+	}
+
+	return &objectCountStore{Store: store, tracker: t, namespace: namespace}
+}
+
+func (t *objectCountTracker) set(obj interface{}) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if _, ok := t.objects[o.GetUID()]; ok {
+		return
+	}
+	t.objects[o.GetUID()] = o.GetNamespace()
+	t.add(o.GetNamespace(), 1)
+}
+
+func (t *objectCountTracker) forget(obj interface{}) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.remove(o.GetUID())
+}
+
+// forgetNamespace forgets the objects of the given namespace, or all objects
+// if it is empty.
+func (t *objectCountTracker) forgetNamespace(namespace string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	for uid, ns := range t.objects {
+		if namespace == "" || ns == namespace {
+			t.remove(uid)
+		}
+	}
+}
+
+func (t *objectCountTracker) remove(uid types.UID) {
+	namespace, ok := t.objects[uid]
+	if !ok {
+		return
+	}
+	delete(t.objects, uid)
+	t.add(namespace, -1)
+}
+
+func (t *objectCountTracker) add(namespace string, n int) {
+	t.totals[namespace] += n
+	if t.totals[namespace] == 0 {
+		delete(t.totals, namespace)
+		t.metrics.Objects.DeleteLabelValues(t.resource, namespace)
+		return
+	}
+	t.metrics.Objects.WithLabelValues(t.resource, namespace).Set(float64(t.totals[namespace]))
+}
+
+// objectCountStore counts the objects which are added to, deleted from or
+// replaced in the wrapped store.
+type objectCountStore struct {
+	cache.Store
+	tracker   *objectCountTracker
+	namespace string
+}
+
+// Add counts the added object.
+func (s *objectCountStore) Add(obj interface{}) error {
+	s.tracker.set(obj)
+	return s.Store.Add(obj)
+}
+
+// Update counts the updated object if it wasn't counted yet.
+func (s *objectCountStore) Update(obj interface{}) error {
+	s.tracker.set(obj)
+	return s.Store.Update(obj)
+}
+
+// Delete forgets the deleted object.
+func (s *objectCountStore) Delete(obj interface{}) error {
+	s.tracker.forget(deletedObject(obj))
+	return s.Store.Delete(obj)
+}
+
+// Replace forgets all objects of the store and counts the ones of the new
+// list.
+func (s *objectCountStore) Replace(list []interface{}, resourceVersion string) error {
+	s.tracker.forgetNamespace(s.namespace)
+	for _, obj := range list {
+		s.tracker.set(obj)
+	}
+	return s.Store.Replace(list, resourceVersion)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+)
+
+func TestStoreSizeMetrics(t *testing.T) {
+	metrics := newStoreSizeMetrics(prometheus.NewRegistry())
+	tracker := newObjectCountTracker("*v1.ConfigMap", metrics)
+
+	families := configMapMetricFamilies(nil, nil)
+	store := metricsstore.NewMetricsStore(
+		generator.ExtractMetricFamilyHeaders(families),
+		generator.ComposeMetricGenFuncs(families),
+	)
+	metrics.Bytes.register("*v1.ConfigMap", []cache.Store{store})
+	wrapped := tracker.wrapStore(store, v1.NamespaceAll)
+
+	cm := func(name, ns string) *v1.ConfigMap {
+		return &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, UID: types.UID("uid-" + name)}}
+	}
+	objects := func(ns string) float64 {
+		return testutil.ToFloat64(metrics.Objects.WithLabelValues("*v1.ConfigMap", ns))
+	}
+
+	if err := wrapped.Replace([]interface{}{cm("cm1", "ns1"), cm("cm2", "ns1"), cm("cm3", "ns2")}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := objects("ns1"); got != 2 {
+		t.Errorf("expected 2 objects in ns1, got %v", got)
+	}
+	if err := wrapped.Update(cm("cm3", "ns2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := wrapped.Delete(cm("cm1", "ns1")); err != nil {
+		t.Fatal(err)
+	}
+	if got := objects("ns1"); got != 1 {
+		t.Errorf("expected 1 object in ns1 after delete, got %v", got)
+	}
+	if got := objects("ns2"); got != 1 {
+		t.Errorf("expected 1 object in ns2 after update, got %v", got)
+	}
+
+	if got := testutil.CollectAndCount(metrics.Bytes, "kube_state_metrics_store_bytes"); got != 1 {
+		t.Fatalf("expected a store bytes metric for the configmap resource, got %d", got)
+	}
+	if got := testutil.ToFloat64(metrics.Bytes); got != float64(store.Bytes()) || got == 0 {
+		t.Errorf("expected %d store bytes, got %v", store.Bytes(), got)
+	}
+
+	if err := wrapped.Replace(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.CollectAndCount(metrics.Objects); got != 0 {
+		t.Errorf("expected no object counts after replacing the store with an empty list, got %d", got)
+	}
+}
@@ -24,14 +24,20 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
 var (
 	invalidLabelCharRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	metricNameRE       = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE        = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 	matchAllCap        = regexp.MustCompile("([a-z0-9])([A-Z])")
 	conditionStatuses  = []v1.ConditionStatus{v1.ConditionTrue, v1.ConditionFalse, v1.ConditionUnknown}
 )
@@ -50,6 +56,207 @@ func resourceVersionMetric(rv string) []*metric.Metric {
 
 }
 
+// ownerMetrics returns one metric for each of the given owner references,
+// or a single metric with empty labels if there are none.
+func ownerMetrics(owners []metav1.OwnerReference) []*metric.Metric {
+	labelKeys := []string{"owner_kind", "owner_name", "owner_is_controller"}
+	if len(owners) == 0 {
+		return []*metric.Metric{
+			{
+				LabelKeys:   labelKeys,
+				LabelValues: []string{"", "", ""},
+				Value:       1,
+			},
+		}
+	}
+
+	ms := make([]*metric.Metric, len(owners))
+	for i, owner := range owners {
+		ownerIsController := "false"
+		if owner.Controller != nil {
+			ownerIsController = strconv.FormatBool(*owner.Controller)
+		}
+		ms[i] = &metric.Metric{
+			LabelKeys:   labelKeys,
+			LabelValues: []string{owner.Kind, owner.Name, ownerIsController},
+			Value:       1,
+		}
+	}
+	return ms
+}
+
+// withOwnerFamily appends the opt-in kube_<resource>_owner family to the
+// families of a resource. The given wrap function of the resource adds its
+// default labels.
+func withOwnerFamily[T metav1.Object](families []generator.FamilyGenerator, resource string, wrap func(func(T) *metric.Family) func(interface{}) *metric.Family) []generator.FamilyGenerator {
+	return append(families, *generator.NewOptInFamilyGenerator(
+		"kube_"+resource+"_owner",
+		"Information about the owner of the "+resource+".",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrap(func(obj T) *metric.Family {
+			return &metric.Family{
+				Metrics: ownerMetrics(obj.GetOwnerReferences()),
+			}
+		}),
+	))
+}
+
+// withAnnotationsFamily appends the kube_<resource>_annotations family to the
+// families of a resource, for resources whose families are not generated with
+// the annotations allowlist. The family only has series for the annotations
+// allowed by the given allowAnnotationsList.
+func withAnnotationsFamily[T metav1.Object](families []generator.FamilyGenerator, resource string, allowAnnotationsList []string, wrap func(func(T) *metric.Family) func(interface{}) *metric.Family) []generator.FamilyGenerator {
+	return append(families, *generator.NewFamilyGeneratorWithStability(
+		"kube_"+resource+"_annotations",
+		"Kubernetes annotations converted to Prometheus labels.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrap(func(obj T) *metric.Family {
+			if len(allowAnnotationsList) == 0 {
+				return &metric.Family{}
+			}
+			annotationKeys, annotationValues := createPrometheusLabelKeysValues("annotation", obj.GetAnnotations(), allowAnnotationsList)
+			return &metric.Family{
+				Metrics: []*metric.Metric{
+					{
+						LabelKeys:   annotationKeys,
+						LabelValues: annotationValues,
+						Value:       1,
+					},
+				},
+			}
+		}),
+	))
+}
+
+// withDeletionTimestampFamily appends the kube_<resource>_deletion_timestamp
+// family to the families of a resource, unless the resource already generates
+// it. The family only has a series while the object is terminating, so that
+// objects stuck in deletion, e.g. on finalizers, can be alerted on.
+func withDeletionTimestampFamily[T metav1.Object](families []generator.FamilyGenerator, resource string, wrap func(func(T) *metric.Family) func(interface{}) *metric.Family) []generator.FamilyGenerator {
+	name := "kube_" + resource + "_deletion_timestamp"
+	for _, f := range families {
+		if f.Name == name {
+			return families
+		}
+	}
+
+	return append(families, *generator.NewFamilyGeneratorWithStability(
+		name,
+		"Unix deletion timestamp",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrap(func(obj T) *metric.Family {
+			ms := []*metric.Metric{}
+
+			if t := obj.GetDeletionTimestamp(); t != nil && !t.IsZero() {
+				ms = append(ms, &metric.Metric{
+					Value: float64(t.Unix()),
+				})
+			}
+
+			return &metric.Family{
+				Metrics: ms,
+			}
+		}),
+	))
+}
+
+// finalizerMetrics returns a metric for each of the given finalizers.
+func finalizerMetrics(finalizers []string) []*metric.Metric {
+	ms := make([]*metric.Metric, 0, len(finalizers))
+	for _, finalizer := range finalizers {
+		ms = append(ms, &metric.Metric{
+			LabelKeys:   []string{"finalizer"},
+			LabelValues: []string{finalizer},
+			Value:       1,
+		})
+	}
+	return ms
+}
+
+// lastManagedByMetrics returns the manager and the operation of the most
+// recent entry of the given managed fields.
+func lastManagedByMetrics(managedFields []metav1.ManagedFieldsEntry) []*metric.Metric {
+	var last *metav1.ManagedFieldsEntry
+	for i := range managedFields {
+		f := &managedFields[i]
+		if last == nil || (f.Time != nil && (last.Time == nil || !f.Time.Before(last.Time))) {
+			last = f
+		}
+	}
+	if last == nil {
+		return []*metric.Metric{}
+	}
+
+	return []*metric.Metric{
+		{
+			LabelKeys:   []string{"manager", "operation"},
+			LabelValues: []string{last.Manager, string(last.Operation)},
+			Value:       1,
+		},
+	}
+}
+
+// withObjectMetaFamilies appends the opt-in kube_<resource>_last_managed_by
+// and kube_<resource>_finalizer families, the deletion timestamp family of
+// withDeletionTimestampFamily and, if withOwner is set, the owner family of
+// withOwnerFamily to the families of a resource.
+func withObjectMetaFamilies[T metav1.Object](families []generator.FamilyGenerator, resource string, withOwner bool, wrap func(func(T) *metric.Family) func(interface{}) *metric.Family) []generator.FamilyGenerator {
+	if withOwner {
+		families = withOwnerFamily(families, resource, wrap)
+	}
+	families = withDeletionTimestampFamily(families, resource, wrap)
+
+	return append(families, *generator.NewOptInFamilyGenerator(
+		"kube_"+resource+"_finalizer",
+		"The finalizers of the "+resource+", which block its deletion until they are removed.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrap(func(obj T) *metric.Family {
+			return &metric.Family{
+				Metrics: finalizerMetrics(obj.GetFinalizers()),
+			}
+		}),
+	), *generator.NewOptInFamilyGenerator(
+		"kube_"+resource+"_last_managed_by",
+		"The manager and operation of the last change to the "+resource+", as recorded in its managed fields.",
+		metric.Gauge,
+		basemetrics.ALPHA,
+		"",
+		wrap(func(obj T) *metric.Family {
+			return &metric.Family{
+				Metrics: lastManagedByMetrics(obj.GetManagedFields()),
+			}
+		}),
+	))
+}
+
+// objectSizeMetrics returns the size of the protobuf serialization of the
+// given object, which is the encoding built-in resources are stored with in
+// etcd.
+func objectSizeMetrics(obj interface{ Size() int }) []*metric.Metric {
+	return []*metric.Metric{
+		{
+			Value: float64(obj.Size()),
+		},
+	}
+}
+
+// deletedObject returns the object of the given deleted object, which may be a
+// cache.DeletedFinalStateUnknown tombstone.
+func deletedObject(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
+
 func boolFloat64(b bool) float64 {
 	if b {
 		return 1
@@ -100,15 +307,20 @@ func mapToPrometheusLabels(labels map[string]string, prefix string) ([]string, [
 	conflicts := make(map[string]*conflictDesc)
 	for _, k := range sortedKeys {
 		labelKey := labelName(prefix, k)
+		if labelKey != prefix+"_"+k {
+			labelKeysSanitized.add(prefix, k)
+		}
 		if conflict, ok := conflicts[labelKey]; ok {
 			if conflict.count == 1 {
 				// this is the first conflict for the label,
 				// so we have to go back and rename the initial label that we've already added
 				labelKeys[conflict.initial] = labelConflictSuffix(labelKeys[conflict.initial], conflict.count)
+				labelKeysConflicted.add(prefix, sortedKeys[conflict.initial])
 			}
 
 			conflict.count++
 			labelKey = labelConflictSuffix(labelKey, conflict.count)
+			labelKeysConflicted.add(prefix, k)
 		} else {
 			// we'll need this info later in case there are conflicts
 			conflicts[labelKey] = &conflictDesc{
@@ -119,6 +331,24 @@ func mapToPrometheusLabels(labels map[string]string, prefix string) ([]string, [
 		labelKeys = append(labelKeys, labelKey)
 		labelValues = append(labelValues, labels[k])
 	}
+
+	// The suffixed label keys may still collide with keys which end with a
+	// suffix themselves, e.g. foo_bar_conflict1, so suffix them again until
+	// all label keys are unique.
+	seen := make(map[string]struct{}, len(labelKeys))
+	for i, labelKey := range labelKeys {
+		for n := 1; ; n++ {
+			if _, ok := seen[labelKey]; !ok {
+				break
+			}
+			labelKey = labelConflictSuffix(labelKeys[i], n)
+		}
+		if labelKey != labelKeys[i] {
+			labelKeys[i] = labelKey
+			labelKeysConflicted.add(prefix, sortedKeys[i])
+		}
+		seen[labelKey] = struct{}{}
+	}
 	return labelKeys, labelValues
 }
 
@@ -20,8 +20,13 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 )
 
 func TestIsHugePageSizeFromResourceName(t *testing.T) {
@@ -243,6 +248,23 @@ func TestKubeLabelsToPrometheusLabels(t *testing.T) {
 				"snake_case",
 			},
 		},
+		{
+			kubeLabels: map[string]string{
+				"foo-bar":           "hyphen",
+				"foo_bar":           "underscore",
+				"foo_bar_conflict1": "suffixed",
+			},
+			expectKeys: []string{
+				"label_foo_bar_conflict1",
+				"label_foo_bar_conflict2",
+				"label_foo_bar_conflict1_conflict1",
+			},
+			expectValues: []string{
+				"hyphen",
+				"underscore",
+				"suffixed",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -266,6 +288,49 @@ func TestKubeLabelsToPrometheusLabels(t *testing.T) {
 
 }
 
+func TestPrometheusLabelName(t *testing.T) {
+	for key, expected := range map[string]string{
+		"app":                      "app",
+		"app.kubernetes.io/name":   "app_kubernetes_io_name",
+		"123.example.com/team":     "_123_example_com_team",
+		"":                         "_",
+		"_underscore":              "_underscore",
+		"tenant.example.com/owner": "tenant_example_com_owner",
+	} {
+		if got := PrometheusLabelName(key); got != expected {
+			t.Errorf("expected label name %q for key %q, got %q", expected, key, got)
+		}
+	}
+}
+
+func TestLabelKeysCountedOnce(t *testing.T) {
+	labels := map[string]string{"app": "a", "foo.bar": "b", "foo_bar": "c", "team.example.com/owner": "d"}
+	mapToPrometheusLabels(labels, "label")
+	PrometheusLabelName("team.example.com/owner")
+	sanitized, conflicted := labelKeysSanitized.len(), labelKeysConflicted.len()
+
+	// Regenerating the metrics of the same object doesn't count its keys again.
+	for i := 0; i < 3; i++ {
+		mapToPrometheusLabels(labels, "label")
+		PrometheusLabelName("team.example.com/owner")
+	}
+	if got := labelKeysSanitized.len(); got != sanitized {
+		t.Errorf("expected %d sanitized keys after regenerating the labels, got %d", sanitized, got)
+	}
+	if got := labelKeysConflicted.len(); got != conflicted {
+		t.Errorf("expected %d conflicted keys after regenerating the labels, got %d", conflicted, got)
+	}
+
+	s := &labelKeySet{}
+	for _, key := range []string{"foo.bar", "foo.bar", "team.example.com/owner"} {
+		s.add("label", key)
+	}
+	s.add("annotation", "foo.bar")
+	if got := s.len(); got != 3 {
+		t.Errorf("expected 3 distinct keys, got %d", got)
+	}
+}
+
 func TestMergeKeyValues(t *testing.T) {
 	testCases := []struct {
 		name               string
@@ -319,3 +384,234 @@ func TestMergeKeyValues(t *testing.T) {
 		})
 	}
 }
+
+func TestWithOwnerFamily(t *testing.T) {
+	families := withOwnerFamily(configMapMetricFamilies(nil, nil), "configmap", wrapConfigMapFunc)
+	if f := families[len(families)-1]; f.Name != "kube_configmap_owner" || !f.OptIn {
+		t.Fatalf("expected opt-in kube_configmap_owner to be appended, got %s", f.Name)
+	}
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap1",
+					Namespace: "ns1",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "Deployment",
+							Name:       "app",
+							Controller: ptr.To(true),
+						},
+						{
+							Kind: "ConfigMap",
+							Name: "base",
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_configmap_owner Information about the owner of the configmap.
+				# TYPE kube_configmap_owner gauge
+				kube_configmap_owner{configmap="configmap1",namespace="ns1",owner_is_controller="true",owner_kind="Deployment",owner_name="app"} 1
+				kube_configmap_owner{configmap="configmap1",namespace="ns1",owner_is_controller="false",owner_kind="ConfigMap",owner_name="base"} 1
+`,
+			MetricNames: []string{"kube_configmap_owner"},
+		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap2",
+					Namespace: "ns2",
+				},
+			},
+			Want: `
+				# HELP kube_configmap_owner Information about the owner of the configmap.
+				# TYPE kube_configmap_owner gauge
+				kube_configmap_owner{configmap="configmap2",namespace="ns2",owner_is_controller="",owner_kind="",owner_name=""} 1
+`,
+			MetricNames: []string{"kube_configmap_owner"},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(families)
+		c.Headers = generator.ExtractMetricFamilyHeaders(families)
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
+
+func TestWithObjectMetaFamilies(t *testing.T) {
+	families := withObjectMetaFamilies(configMapMetricFamilies(nil, nil), "configmap", false, wrapConfigMapFunc)
+	if f := families[len(families)-1]; f.Name != "kube_configmap_last_managed_by" || !f.OptIn {
+		t.Fatalf("expected opt-in kube_configmap_last_managed_by to be appended, got %s", f.Name)
+	}
+	if f := withObjectMetaFamilies(configMapMetricFamilies(nil, nil), "configmap", true, wrapConfigMapFunc); len(f) != len(families)+1 {
+		t.Fatalf("expected the owner family to be appended as well, got %d families", len(f))
+	}
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap1",
+					Namespace: "ns1",
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{
+							Manager:   "kubectl-client-side-apply",
+							Operation: metav1.ManagedFieldsOperationUpdate,
+							Time:      &metav1.Time{Time: time.Unix(1501569018, 0)},
+						},
+						{
+							Manager:   "helm",
+							Operation: metav1.ManagedFieldsOperationApply,
+							Time:      &metav1.Time{Time: time.Unix(1501777018, 0)},
+						},
+						{
+							Manager:   "kubelet",
+							Operation: metav1.ManagedFieldsOperationUpdate,
+						},
+					},
+				},
+			},
+			Want: `
+				# HELP kube_configmap_last_managed_by The manager and operation of the last change to the configmap, as recorded in its managed fields.
+				# TYPE kube_configmap_last_managed_by gauge
+				kube_configmap_last_managed_by{configmap="configmap1",manager="helm",namespace="ns1",operation="Apply"} 1
+`,
+			MetricNames: []string{"kube_configmap_last_managed_by"},
+		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap2",
+					Namespace: "ns2",
+				},
+			},
+			Want: `
+				# HELP kube_configmap_last_managed_by The manager and operation of the last change to the configmap, as recorded in its managed fields.
+				# TYPE kube_configmap_last_managed_by gauge
+`,
+			MetricNames: []string{"kube_configmap_last_managed_by"},
+		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "configmap3",
+					Namespace:  "ns3",
+					Finalizers: []string{"example.com/cleanup", "example.com/backup"},
+				},
+			},
+			Want: `
+				# HELP kube_configmap_finalizer The finalizers of the configmap, which block its deletion until they are removed.
+				# TYPE kube_configmap_finalizer gauge
+				kube_configmap_finalizer{configmap="configmap3",finalizer="example.com/cleanup",namespace="ns3"} 1
+				kube_configmap_finalizer{configmap="configmap3",finalizer="example.com/backup",namespace="ns3"} 1
+`,
+			MetricNames: []string{"kube_configmap_finalizer"},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(families)
+		c.Headers = generator.ExtractMetricFamilyHeaders(families)
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
+
+func TestWithAnnotationsFamily(t *testing.T) {
+	obj := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "limitrange1",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				"team":  "storage",
+				"owner": "alice",
+			},
+		},
+	}
+
+	cases := []struct {
+		allowAnnotationsList []string
+		want                 string
+	}{
+		{
+			allowAnnotationsList: []string{"team"},
+			want: `
+				# HELP kube_limitrange_annotations Kubernetes annotations converted to Prometheus labels.
+				# TYPE kube_limitrange_annotations gauge
+				kube_limitrange_annotations{annotation_team="storage",limitrange="limitrange1",namespace="ns1"} 1
+`,
+		},
+		{
+			want: `
+				# HELP kube_limitrange_annotations Kubernetes annotations converted to Prometheus labels.
+				# TYPE kube_limitrange_annotations gauge
+`,
+		},
+	}
+	for i, c := range cases {
+		families := withAnnotationsFamily(limitRangeMetricFamilies, "limitrange", c.allowAnnotationsList, wrapLimitRangeFunc)
+		tc := generateMetricsTestCase{
+			Obj:         obj,
+			Want:        c.want,
+			MetricNames: []string{"kube_limitrange_annotations"},
+			Func:        generator.ComposeMetricGenFuncs(families),
+			Headers:     generator.ExtractMetricFamilyHeaders(families),
+		}
+		if err := tc.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
+
+func TestWithDeletionTimestampFamily(t *testing.T) {
+	families := withDeletionTimestampFamily(configMapMetricFamilies(nil, nil), "configmap", wrapConfigMapFunc)
+	if f := families[len(families)-1]; f.Name != "kube_configmap_deletion_timestamp" {
+		t.Fatalf("expected kube_configmap_deletion_timestamp to be appended, got %s", f.Name)
+	}
+	if f := withDeletionTimestampFamily(families, "configmap", wrapConfigMapFunc); len(f) != len(families) {
+		t.Fatalf("expected an existing deletion timestamp family not to be appended again, got %d families", len(f))
+	}
+
+	cases := []generateMetricsTestCase{
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "configmap1",
+					Namespace:         "ns1",
+					DeletionTimestamp: &metav1.Time{Time: time.Unix(1800000000, 0)},
+					Finalizers:        []string{"example.com/cleanup"},
+				},
+			},
+			Want: `
+				# HELP kube_configmap_deletion_timestamp Unix deletion timestamp
+				# TYPE kube_configmap_deletion_timestamp gauge
+				kube_configmap_deletion_timestamp{configmap="configmap1",namespace="ns1"} 1.8e+09
+`,
+			MetricNames: []string{"kube_configmap_deletion_timestamp"},
+		},
+		{
+			Obj: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "configmap2",
+					Namespace: "ns2",
+				},
+			},
+			Want: `
+				# HELP kube_configmap_deletion_timestamp Unix deletion timestamp
+				# TYPE kube_configmap_deletion_timestamp gauge
+`,
+			MetricNames: []string{"kube_configmap_deletion_timestamp"},
+		},
+	}
+	for i, c := range cases {
+		c.Func = generator.ComposeMetricGenFuncs(families)
+		c.Headers = generator.ExtractMetricFamilyHeaders(families)
+		if err := c.run(); err != nil {
+			t.Errorf("unexpected collecting result in %vth run:\n%s", i, err)
+		}
+	}
+}
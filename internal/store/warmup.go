@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// warmupMetrics stores the pointer of the kube_state_metrics_warmup_duration_seconds metric.
+type warmupMetrics struct {
+	Duration *prometheus.GaugeVec
+}
+
+// newWarmupMetrics takes in a prometheus registry and initializes and
+// registers the warmup metrics. It returns those registered metrics.
+func newWarmupMetrics(r prometheus.Registerer) *warmupMetrics {
+	return &warmupMetrics{
+		Duration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_warmup_duration_seconds",
+				Help: "Time in seconds from the start of the stores until the initial list of a resource was populated into its store.",
+			},
+			[]string{"resource"},
+		),
+	}
+}
+
+// warmupGate bounds the number of reflectors performing their initial list at
+// the same time. Reflectors are admitted in the order they were queued, so
+// resources that are built first also warm up first.
+type warmupGate struct {
+	ctx     context.Context
+	started time.Time
+	metrics *warmupMetrics
+	// slots is nil if the number of concurrent initial lists is unbounded.
+	slots chan struct{}
+
+	// pending is the number of reflectors which neither populated their
+	// initial list into their store nor failed it yet.
+	pending atomic.Int64
+
+	// mtx protects last
+	mtx sync.Mutex
+	// last is closed once the most recently queued reflector was admitted.
+	last chan struct{}
+}
+
+// newWarmupGate returns a warmupGate admitting at most concurrency initial
+// lists at a time. A concurrency of zero or less means no limit.
+func newWarmupGate(ctx context.Context, concurrency int, metrics *warmupMetrics) *warmupGate {
+	g := &warmupGate{
+		ctx:     ctx,
+		started: time.Now(),
+		metrics: metrics,
+	}
+	if concurrency > 0 {
+		g.slots = make(chan struct{}, concurrency)
+	}
+	return g
+}
+
+// run queues the given reflector and starts it once a slot is available. The
+// slot is released once the initial list was populated into the store or
+// failed, so that a single failing resource does not block the others. start
+// has to call the given skip function if it does not run the reflector. If ctx
+// is done while the reflector waits for a slot, e.g. because its namespace was
+// deleted, it is started right away without a slot.
+func (g *warmupGate) run(ctx context.Context, resource string, store cache.Store, lw cache.ListerWatcher, start func(s cache.Store, lw cache.ListerWatcher, skip func())) {
+	t := &warmupTracker{gate: g, resource: resource, Store: store}
+	tlw := &warmupListWatch{tracker: t, lw: lw}
+	g.pending.Add(1)
+
+	if g.slots == nil {
+		start(t, tlw, t.skip)
+		return
+	}
+
+	g.mtx.Lock()
+	previous := g.last
+	admitted := make(chan struct{})
+	g.last = admitted
+	g.mtx.Unlock()
+
+	go func() {
+		if previous != nil {
+			select {
+			case <-previous:
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() == nil {
+			select {
+			case g.slots <- struct{}{}:
+				t.holdsSlot = true
+			case <-ctx.Done():
+			}
+		}
+		close(admitted)
+		if g.ctx.Err() != nil {
+			return
+		}
+		start(t, tlw, t.skip)
+	}()
+}
+
+// synced returns whether all reflectors run by the gate populated their
+// initial list into their store or failed it. A failed list doesn't hold back
+// readiness, as it may keep failing, e.g. if the resource is forbidden.
+func (g *warmupGate) synced() bool {
+	return g.pending.Load() == 0
+}
+
+// warmupTracker wraps the store of a single reflector in order to detect the
+// end of its initial list.
+type warmupTracker struct {
+	cache.Store
+	gate      *warmupGate
+	resource  string
+	holdsSlot bool
+	listed    sync.Once
+	done      sync.Once
+	released  sync.Once
+}
+
+// Replace is called by the reflector with the result of a successful list.
+func (t *warmupTracker) Replace(list []interface{}, resourceVersion string) error {
+	err := t.Store.Replace(list, resourceVersion)
+	t.listed.Do(func() {
+		if t.gate.metrics != nil {
+			t.gate.metrics.Duration.WithLabelValues(t.resource).Set(time.Since(t.gate.started).Seconds())
+		}
+	})
+	t.finish()
+	t.release()
+	return err
+}
+
+// warmupListWatch wraps the ListerWatcher of a single reflector in order to
+// detect failures of its initial list.
+type warmupListWatch struct {
+	tracker *warmupTracker
+	lw      cache.ListerWatcher
+}
+
+// List releases the slot of the reflector on failure, it will be retried
+// without holding back the initial list of other resources or readiness.
+func (w *warmupListWatch) List(options metav1.ListOptions) (runtime.Object, error) {
+	res, err := w.lw.List(options)
+	if err != nil {
+		w.tracker.fail(err)
+		w.tracker.release()
+	}
+	return res, err
+}
+
+// Watch delegates to the wrapped ListerWatcher.
+func (w *warmupListWatch) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	return w.lw.Watch(options)
+}
+
+// finish marks the initial list of the reflector as done.
+func (t *warmupTracker) finish() {
+	t.done.Do(func() {
+		t.gate.pending.Add(-1)
+	})
+}
+
+// fail marks the initial list of the reflector as done if it failed before a
+// list succeeded.
+func (t *warmupTracker) fail(err error) {
+	t.done.Do(func() {
+		klog.ErrorS(err, "Initial list failed, not waiting for it to report ready", "resource", t.resource)
+		t.gate.pending.Add(-1)
+	})
+}
+
+// skip marks the initial list of a reflector which is not run as done and
+// releases its slot.
+func (t *warmupTracker) skip() {
+	t.finish()
+	t.release()
+}
+
+func (t *warmupTracker) release() {
+	t.released.Do(func() {
+		if t.holdsSlot {
+			<-t.gate.slots
+		}
+	})
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+type failingListWatch struct{}
+
+func (failingListWatch) List(_ metav1.ListOptions) (runtime.Object, error) {
+	return nil, errors.New("forbidden")
+}
+
+func (failingListWatch) Watch(_ metav1.ListOptions) (watch.Interface, error) {
+	return nil, errors.New("forbidden")
+}
+
+func TestWarmupGateOrdering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metrics := newWarmupMetrics(prometheus.NewRegistry())
+	g := newWarmupGate(ctx, 1, metrics)
+
+	started := make(chan string, 3)
+	stores := make(chan cache.Store, 3)
+	for _, r := range []string{"pods", "endpointslices", "configmaps"} {
+		r := r
+		g.run(g.ctx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(s cache.Store, _ cache.ListerWatcher, _ func()) {
+			started <- r
+			stores <- s
+		})
+	}
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		select {
+		case r := <-started:
+			got = append(got, r)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for reflector %d to be admitted, admitted so far: %v", i, got)
+		}
+		select {
+		case r := <-started:
+			t.Fatalf("expected a single reflector to be admitted at a time, but %s was admitted as well", r)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if err := (<-stores).Replace(nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if want := []string{"pods", "endpointslices", "configmaps"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected reflectors to be admitted in order %v, got %v", want, got)
+	}
+	if n := testutil.CollectAndCount(metrics.Duration); n != 3 {
+		t.Fatalf("expected warmup duration to be recorded for 3 resources, got %d", n)
+	}
+}
+
+func TestWarmupGateReleasesOnListError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := newWarmupGate(ctx, 1, nil)
+
+	started := make(chan cache.ListerWatcher, 2)
+	for _, r := range []string{"pods", "configmaps"} {
+		g.run(g.ctx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(_ cache.Store, lw cache.ListerWatcher, _ func()) {
+			started <- lw
+		})
+	}
+
+	lw := <-started
+	if _, err := lw.List(metav1.ListOptions{}); err == nil {
+		t.Fatal("expected list to fail")
+	}
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the failing list to release its slot")
+	}
+}
+
+func TestOrderedResources(t *testing.T) {
+	b := NewBuilder()
+	if err := b.WithEnabledResources([]string{"configmaps", "endpointslices", "nodes", "pods"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WithInitialListOrder([]string{"pods", "deployments", "endpointslices"}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"pods", "endpointslices", "configmaps", "nodes"}
+	if got := b.orderedResources(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if err := b.WithInitialListOrder([]string{"foo"}); err == nil {
+		t.Fatal("expected an error for a non-existent resource")
+	}
+}
+
+func TestWarmupGateSynced(t *testing.T) {
+	g := newWarmupGate(context.Background(), 0, nil)
+	var trackers []cache.Store
+	for _, r := range []string{"pods", "nodes"} {
+		g.run(g.ctx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(s cache.Store, _ cache.ListerWatcher, _ func()) {
+			trackers = append(trackers, s)
+		})
+	}
+	if g.synced() {
+		t.Fatal("expected gate not to be synced before the initial lists")
+	}
+
+	for i, s := range trackers {
+		if err := s.Replace(nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		// A relist doesn't count twice.
+		if err := s.Replace(nil, ""); err != nil {
+			t.Fatal(err)
+		}
+		if want := i == len(trackers)-1; g.synced() != want {
+			t.Fatalf("expected synced %t after %d initial lists", want, i+1)
+		}
+	}
+}
+
+func TestWarmupGateSyncedOnListError(t *testing.T) {
+	g := newWarmupGate(context.Background(), 0, nil)
+	var lws []cache.ListerWatcher
+	var trackers []cache.Store
+	for _, r := range []string{"pods", "nodes"} {
+		g.run(g.ctx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(s cache.Store, lw cache.ListerWatcher, _ func()) {
+			trackers = append(trackers, s)
+			lws = append(lws, lw)
+		})
+	}
+
+	if err := trackers[0].Replace(nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	// A list failing after a successful one doesn't count twice.
+	if _, err := lws[0].List(metav1.ListOptions{}); err == nil {
+		t.Fatal("expected list to fail")
+	}
+	if g.synced() {
+		t.Fatal("expected gate not to be synced before the initial list of nodes")
+	}
+
+	// A forbidden resource must not hold back readiness forever.
+	for i := 0; i < 2; i++ {
+		if _, err := lws[1].List(metav1.ListOptions{}); err == nil {
+			t.Fatal("expected list to fail")
+		}
+	}
+	if !g.synced() {
+		t.Fatal("expected gate to be synced after the initial list of nodes failed")
+	}
+}
+
+func TestWarmupGateSkip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := newWarmupGate(ctx, 1, nil)
+
+	started := make(chan string, 2)
+	for _, r := range []string{"pods", "configmaps"} {
+		r := r
+		g.run(g.ctx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(_ cache.Store, _ cache.ListerWatcher, skip func()) {
+			started <- r
+			if r == "pods" {
+				skip()
+			}
+		})
+	}
+
+	for _, want := range []string{"pods", "configmaps"} {
+		select {
+		case r := <-started:
+			if r != want {
+				t.Fatalf("expected %s to be admitted, got %s", want, r)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the skipped reflector to release its slot for %s", want)
+		}
+	}
+	if g.synced() {
+		t.Fatal("expected gate not to be synced before the initial list of configmaps")
+	}
+}
+
+func TestWarmupGateCancelledWhileQueued(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	g := newWarmupGate(ctx, 1, nil)
+
+	started := make(chan string, 3)
+	nsCtx, nsCancel := context.WithCancel(ctx)
+	for _, r := range []string{"pods", "configmaps", "secrets"} {
+		r := r
+		runCtx := ctx
+		if r == "configmaps" {
+			runCtx = nsCtx
+		}
+		g.run(runCtx, r, cache.NewStore(cache.MetaNamespaceKeyFunc), failingListWatch{}, func(_ cache.Store, _ cache.ListerWatcher, skip func()) {
+			started <- r
+			if r == "configmaps" {
+				skip()
+			}
+		})
+	}
+
+	if r := <-started; r != "pods" {
+		t.Fatalf("expected pods to be admitted first, got %s", r)
+	}
+
+	// configmaps waits for the slot held by pods, cancelling its namespace
+	// starts it without a slot, secrets keeps waiting for pods.
+	nsCancel()
+	select {
+	case r := <-started:
+		if r != "configmaps" {
+			t.Fatalf("expected configmaps to be started after its context was cancelled, got %s", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected configmaps to be started after its context was cancelled")
+	}
+	select {
+	case r := <-started:
+		t.Fatalf("expected %s not to be admitted while pods holds the slot", r)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
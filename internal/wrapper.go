@@ -20,10 +20,14 @@ import (
 	"context"
 	"errors"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/common/version"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 	"k8s.io/klog/v2"
@@ -34,6 +38,10 @@ import (
 
 // RunKubeStateMetricsWrapper is a wrapper around KSM, delegated to the root command.
 func RunKubeStateMetricsWrapper(opts *options.Options) {
+	if err := opts.SetupLogging(); err != nil {
+		klog.ErrorS(err, "Failed to set up logging")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
 
 	KSMRunOrDie := func(ctx context.Context) {
 		if err := app.RunKubeStateMetricsWrapper(ctx, opts); err != nil {
@@ -42,7 +50,25 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 		}
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// rootCtx is cancelled on SIGINT and SIGTERM, which shuts down the
+	// servers and stops the reflectors of the running instance.
+	rootCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var mu sync.Mutex
+	ctx, cancel := context.WithCancel(rootCtx)
+	restart := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		cancel()
+		if rootCtx.Err() != nil {
+			return
+		}
+		// Wait for the ports to be released.
+		<-time.After(3 * time.Second)
+		ctx, cancel = context.WithCancel(rootCtx)
+		go KSMRunOrDie(ctx)
+	}
 	if file := options.GetConfigFile(*opts); file != "" {
 		cfgViper := viper.New()
 		cfgViper.SetConfigType("yaml")
@@ -57,11 +83,7 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 		}
 		cfgViper.OnConfigChange(func(e fsnotify.Event) {
 			klog.InfoS("Changes detected", "name", e.Name)
-			cancel()
-			// Wait for the ports to be released.
-			<-time.After(3 * time.Second)
-			ctx, cancel = context.WithCancel(context.Background())
-			go KSMRunOrDie(ctx)
+			restart()
 		})
 		cfgViper.WatchConfig()
 
@@ -87,11 +109,7 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 		}
 		crcViper.OnConfigChange(func(e fsnotify.Event) {
 			klog.InfoS("Changes detected", "name", e.Name)
-			cancel()
-			// Wait for the ports to be released.
-			<-time.After(3 * time.Second)
-			ctx, cancel = context.WithCancel(context.Background())
-			go KSMRunOrDie(ctx)
+			restart()
 		})
 		crcViper.WatchConfig()
 	}
@@ -109,15 +127,47 @@ func RunKubeStateMetricsWrapper(opts *options.Options) {
 		}
 		kubecfgViper.OnConfigChange(func(e fsnotify.Event) {
 			klog.InfoS("Changes detected", "name", e.Name)
-			cancel()
-			// Wait for the ports to be released.
-			<-time.After(3 * time.Second)
-			ctx, cancel = context.WithCancel(context.Background())
-			go KSMRunOrDie(ctx)
+			restart()
 		})
 		kubecfgViper.WatchConfig()
 	}
-	klog.InfoS("Starting kube-state-metrics")
+	if files := opts.ListFiles(); len(files) > 0 {
+		go options.WatchListFiles(rootCtx, files, opts.ListFilePollInterval, func() {
+			if err := opts.ReloadListFiles(); err != nil {
+				klog.ErrorS(err, "Failed to reload list files, keeping the previous lists", "files", files)
+				return
+			}
+			klog.InfoS("Changes detected", "files", files)
+			restart()
+		})
+	}
+	go func() {
+		sighup := make(chan os.Signal, 1)
+		signal.Notify(sighup, syscall.SIGHUP)
+		for {
+			select {
+			case <-rootCtx.Done():
+				return
+			case <-sighup:
+				klog.InfoS("Reload requested", "trigger", "SIGHUP")
+			case <-app.ReloadRequests():
+				klog.InfoS("Reload requested", "trigger", "endpoint")
+			}
+			if len(opts.ListFiles()) > 0 {
+				if err := opts.ReloadListFiles(); err != nil {
+					klog.ErrorS(err, "Failed to reload list files, keeping the previous lists", "files", opts.ListFiles())
+				}
+			}
+			restart()
+		}
+	}()
+	klog.InfoS("Starting kube-state-metrics", "version", version.Version, "revision", version.Revision, "buildDate", version.BuildDate, "platform", version.GoOS+"/"+version.GoArch)
 	KSMRunOrDie(ctx)
-	select {}
+	if opts.DumpTo != "" || opts.DryRun {
+		// The metrics were dumped or the dry run is done, there is nothing
+		// left to serve.
+		return
+	}
+	<-rootCtx.Done()
+	klog.InfoS("Shutting down kube-state-metrics")
 }
@@ -31,6 +31,7 @@ func main() {
 		internal.RunKubeStateMetricsWrapper(opts)
 	}
 	opts.AddFlags(cmd)
+	cmd.AddCommand(internal.NewDocsCommand(), internal.NewServeCommand(cmd), internal.NewValidateCommand(cmd, opts), internal.NewVersionCommand())
 	if err := opts.Parse(); err != nil {
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
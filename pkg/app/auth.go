@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// delegatedAuthCacheTTL is the duration for which the outcome of a review is
+// cached, so scrapes don't cause requests to the apiserver each time.
+const delegatedAuthCacheTTL = time.Minute
+
+type delegatedAuthResult struct {
+	status  int
+	expires time.Time
+}
+
+// delegatedAuthHandler authenticates the bearer token of requests with a
+// TokenReview and authorizes them with a SubjectAccessReview of their
+// non-resource URL, like the kubelet does.
+type delegatedAuthHandler struct {
+	client     kubernetes.Interface
+	next       http.Handler
+	publicPath map[string]struct{}
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]delegatedAuthResult
+}
+
+// newDelegatedAuthHandler returns a handler delegating the authentication and
+// authorization of the requests to the apiserver before passing them on to
+// next. Requests of the given public paths, e.g. probes, are passed on as is.
+func newDelegatedAuthHandler(client kubernetes.Interface, next http.Handler, publicPaths ...string) http.Handler {
+	h := &delegatedAuthHandler{
+		client:     client,
+		next:       next,
+		publicPath: make(map[string]struct{}, len(publicPaths)),
+		cache:      make(map[[sha256.Size]byte]delegatedAuthResult),
+	}
+	for _, p := range publicPaths {
+		h.publicPath[p] = struct{}{}
+	}
+	return h
+}
+
+func (h *delegatedAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.publicPath[r.URL.Path]; ok {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	verb := strings.ToLower(r.Method)
+	status, err := h.review(r.Context(), token, verb, r.URL.Path)
+	if err != nil {
+		klog.ErrorS(err, "Failed to delegate authentication and authorization", "path", r.URL.Path)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// review returns http.StatusOK if the token is allowed to access the path with
+// the given verb, http.StatusUnauthorized if it isn't valid and
+// http.StatusForbidden otherwise.
+func (h *delegatedAuthHandler) review(ctx context.Context, token, verb, path string) (int, error) {
+	key := sha256.Sum256([]byte(verb + "\x00" + path + "\x00" + token))
+	now := time.Now()
+
+	h.mu.Lock()
+	res, ok := h.cache[key]
+	h.mu.Unlock()
+	if ok && now.Before(res.expires) {
+		return res.status, nil
+	}
+
+	status, err := h.delegate(ctx, token, verb, path)
+	if err != nil {
+		return 0, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for k, v := range h.cache {
+		if !now.Before(v.expires) {
+			delete(h.cache, k)
+		}
+	}
+	h.cache[key] = delegatedAuthResult{status: status, expires: now.Add(delegatedAuthCacheTTL)}
+	return status, nil
+}
+
+func (h *delegatedAuthHandler) delegate(ctx context.Context, token, verb, path string) (int, error) {
+	tr, err := h.client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if !tr.Status.Authenticated {
+		return http.StatusUnauthorized, nil
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(tr.Status.User.Extra))
+	for k, v := range tr.Status.User.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := h.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   tr.Status.User.Username,
+			UID:    tr.Status.User.UID,
+			Groups: tr.Status.User.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: verb,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if !sar.Status.Allowed {
+		return http.StatusForbidden, nil
+	}
+	return http.StatusOK, nil
+}
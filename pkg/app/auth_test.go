@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestDelegatedAuthHandler(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	reviews := 0
+	client.PrependReactor("create", "tokenreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		tr := action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch tr.Spec.Token {
+		case "prometheus":
+			tr.Status.Authenticated = true
+			tr.Status.User.Username = "system:serviceaccount:monitoring:prometheus"
+		case "other":
+			tr.Status.Authenticated = true
+			tr.Status.User.Username = "system:serviceaccount:default:other"
+		}
+		return true, tr, nil
+	})
+	client.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		sar := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		sar.Status.Allowed = sar.Spec.User == "system:serviceaccount:monitoring:prometheus" &&
+			sar.Spec.NonResourceAttributes.Path == metricsPath &&
+			sar.Spec.NonResourceAttributes.Verb == "get"
+		return true, sar, nil
+	})
+
+	h := newDelegatedAuthHandler(client, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), healthzPath)
+
+	tests := []struct {
+		desc  string
+		path  string
+		token string
+		want  int
+	}{
+		{desc: "public path", path: healthzPath, want: http.StatusOK},
+		{desc: "missing token", path: metricsPath, want: http.StatusUnauthorized},
+		{desc: "invalid token", path: metricsPath, token: "invalid", want: http.StatusUnauthorized},
+		{desc: "forbidden user", path: metricsPath, token: "other", want: http.StatusForbidden},
+		{desc: "forbidden path", path: inventoryPath, token: "prometheus", want: http.StatusForbidden},
+		{desc: "allowed user", path: metricsPath, token: "prometheus", want: http.StatusOK},
+		{desc: "cached review", path: metricsPath, token: "prometheus", want: http.StatusOK},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest("GET", "http://localhost:8080"+test.path, nil)
+		if test.token != "" {
+			req.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != test.want {
+			t.Errorf("%s: expected %d status code but got %d", test.desc, test.want, w.Code)
+		}
+	}
+	if reviews != 4 {
+		t.Errorf("expected 4 token reviews but got %d", reviews)
+	}
+}
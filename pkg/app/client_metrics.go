@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clientmetrics "k8s.io/client-go/tools/metrics"
+)
+
+// The client-go metrics can only be registered once per process, so the same
+// collectors are registered in the registry of each run of kube-state-metrics.
+var (
+	clientRequestLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rest_client_request_duration_seconds",
+			Help:    "Latency of the requests of kube-state-metrics to the apiserver, by verb and host.",
+			Buckets: []float64{0.005, 0.025, 0.1, 0.25, 0.5, 1, 2, 4, 8, 15, 30, 60},
+		},
+		[]string{"verb", "host"},
+	)
+	clientRequestResult = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rest_client_requests_total",
+			Help: "Number of requests of kube-state-metrics to the apiserver, by status code, method and host.",
+		},
+		[]string{"code", "method", "host"},
+	)
+)
+
+type clientLatencyAdapter struct {
+	m *prometheus.HistogramVec
+}
+
+func (a clientLatencyAdapter) Observe(_ context.Context, verb string, u url.URL, latency time.Duration) {
+	a.m.WithLabelValues(verb, u.Host).Observe(latency.Seconds())
+}
+
+type clientResultAdapter struct {
+	m *prometheus.CounterVec
+}
+
+func (a clientResultAdapter) Increment(_ context.Context, code, method, host string) {
+	a.m.WithLabelValues(code, method, host).Inc()
+}
+
+// registerClientMetrics registers the latency and result metrics of the
+// requests of the Kubernetes clients in the given registry.
+func registerClientMetrics(r prometheus.Registerer) {
+	clientmetrics.Register(clientmetrics.RegisterOpts{
+		RequestLatency: clientLatencyAdapter{m: clientRequestLatency},
+		RequestResult:  clientResultAdapter{m: clientRequestResult},
+	})
+	r.MustRegister(clientRequestLatency, clientRequestResult)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// familyFilter is a filter of the metric families along with the reason the
+// dry run reports for the families it filters out.
+type familyFilter struct {
+	filter generator.FamilyGeneratorFilter
+	reason string
+}
+
+// servedResource is a resource served by the apiserver.
+type servedResource struct {
+	group      string
+	namespaced bool
+}
+
+// dryRun checks that the apiserver serves the enabled resources and that they
+// can be listed and watched in the given namespaces, and writes which
+// collectors and metric families of the built-in resources would be enabled
+// to w, along with the reason of the skipped ones. It returns an error if an
+// enabled resource can not be collected.
+func dryRun(ctx context.Context, w io.Writer, client kubernetes.Interface, families map[string][]generator.FamilyGenerator, resources []string, namespaces options.NamespaceList, filters []familyFilter) error {
+	served, err := servedResources(client.Discovery())
+	if err != nil {
+		return fmt.Errorf("failed to discover the resources served by the apiserver: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLLECTOR\tFAMILY\tSTATUS\tREASON")
+	var failed []string
+	for _, resource := range resources {
+		generators, ok := families[resource]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t\tenabled\tcustom resource, not checked\n", resource)
+			continue
+		}
+		r, ok := served[resource]
+		if !ok {
+			fmt.Fprintf(tw, "%s\t\tskipped\tnot served by the apiserver\n", resource)
+			failed = append(failed, resource)
+			continue
+		}
+		denied, err := deniedAccess(ctx, client, resource, r, namespaces)
+		if err != nil {
+			return err
+		}
+		if len(denied) > 0 {
+			fmt.Fprintf(tw, "%s\t\tskipped\tforbidden: %s\n", resource, strings.Join(denied, ", "))
+			failed = append(failed, resource)
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t\tenabled\t\n", resource)
+		for _, f := range generators {
+			if reason := skipReason(f, filters); reason != "" {
+				fmt.Fprintf(tw, "%s\t%s\tskipped\t%s\n", resource, f.Name, reason)
+			} else {
+				fmt.Fprintf(tw, "%s\t%s\tenabled\t\n", resource, f.Name)
+			}
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write the dry run: %w", err)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("enabled resources can not be collected: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// servedResources returns the resources served by the apiserver by name. The
+// resources of the API groups whose discovery failed are logged and left out.
+func servedResources(client discovery.DiscoveryInterface) (map[string]servedResource, error) {
+	_, lists, err := client.ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		klog.ErrorS(err, "Failed to discover some API groups, their resources are reported as not served")
+	}
+
+	// Prefer the built-in groups to custom resources of the same name, the
+	// core group being listed first.
+	sort.SliceStable(lists, func(i, j int) bool {
+		return !strings.Contains(lists[i].GroupVersion, ".") && strings.Contains(lists[j].GroupVersion, ".")
+	})
+	served := map[string]servedResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			// Skip subresources, e.g. pods/status.
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			if _, ok := served[r.Name]; !ok {
+				served[r.Name] = servedResource{group: gv.Group, namespaced: r.Namespaced}
+			}
+		}
+	}
+	return served, nil
+}
+
+// deniedAccess returns the verbs kube-state-metrics lacks to list and watch
+// the given resource in the given namespaces, e.g. "watch in default".
+func deniedAccess(ctx context.Context, client kubernetes.Interface, resource string, r servedResource, namespaces options.NamespaceList) ([]string, error) {
+	if !r.namespaced {
+		namespaces = options.DefaultNamespaces
+	}
+
+	var denied []string
+	for _, ns := range namespaces {
+		for _, verb := range []string{"list", "watch"} {
+			review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: ns,
+						Verb:      verb,
+						Group:     r.group,
+						Resource:  resource,
+					},
+				},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to review the access to %s: %w", resource, err)
+			}
+			if review.Status.Allowed {
+				continue
+			}
+			if ns == metav1.NamespaceAll {
+				denied = append(denied, verb)
+			} else {
+				denied = append(denied, verb+" in "+ns)
+			}
+		}
+	}
+	return denied, nil
+}
+
+// skipReason returns the reason of the first filter the given family does not
+// pass, or an empty string if it passes all of them.
+func skipReason(f generator.FamilyGenerator, filters []familyFilter) string {
+	for _, filter := range filters {
+		if !filter.filter.Test(f) {
+			return filter.reason
+		}
+	}
+	return ""
+}
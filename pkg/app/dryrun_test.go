@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/optin"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestDryRun(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true},
+				{Name: "secrets", Namespaced: true},
+			},
+		},
+	}
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = attributes.Resource != "secrets" || attributes.Verb != "watch" || attributes.Namespace != "kube-system"
+		return true, review, nil
+	})
+
+	storeBuilder := store.NewBuilder()
+	if err := storeBuilder.WithEnabledResources([]string{"configmaps", "leases", "secrets"}); err != nil {
+		t.Fatal(err)
+	}
+	optInFilter, err := optin.NewMetricFamilyFilter(options.MetricSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = dryRun(context.Background(), buf, client, storeBuilder.FamilyGenerators(), []string{"configmaps", "leases", "secrets"}, options.NamespaceList{"default", "kube-system"}, []familyFilter{
+		{filter: optInFilter, reason: "opt-in"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "leases, secrets") {
+		t.Errorf("expected an error for leases and secrets, got %v", err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	output := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"configmaps enabled",
+		"configmaps kube_configmap_info enabled",
+		"configmaps kube_configmap_owner skipped opt-in",
+		"leases skipped not served by the apiserver",
+		"secrets skipped forbidden: watch in kube-system",
+	} {
+		if !strings.Contains(output, want+"\n") {
+			t.Errorf("expected %q in the dry run output:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(output, "kube_secret_info") {
+		t.Errorf("expected no families of the skipped secrets collector:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
+)
+
+// dumpPollInterval is the interval at which the initial lists are checked
+// for completion before the metrics are dumped.
+const dumpPollInterval = time.Second
+
+// dumpMetrics builds the stores once, waits until they populated their
+// initial list and writes the metrics to the given file. The file is
+// compressed with gzip if its name ends with .gz.
+func dumpMetrics(ctx context.Context, m *metricshandler.MetricsHandler, synced func() bool, shard int32, totalShards int, path string) error {
+	m.ConfigureSharding(ctx, shard, totalShards)
+	klog.InfoS("Waiting for the initial lists before dumping the metrics", "file", path)
+	if err := wait.PollUntilContextCancel(ctx, dumpPollInterval, true, func(context.Context) (bool, error) {
+		return synced(), nil
+	}); err != nil {
+		return fmt.Errorf("failed to wait for the initial lists: %w", err)
+	}
+
+	// Write to a temporary file first, so the file is either complete or
+	// not written at all.
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create the dump file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := writeDump(f, m, strings.HasSuffix(path, ".gz")); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the dump file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write the dump file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write the dump file: %w", err)
+	}
+	klog.InfoS("Dumped metrics", "file", path)
+	return nil
+}
+
+func writeDump(w io.Writer, m *metricshandler.MetricsHandler, compress bool) error {
+	if !compress {
+		return m.WriteAll(w)
+	}
+
+	gz := gzip.NewWriter(w)
+	if err := m.WriteAll(gz); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// requestLogLevel is the verbosity at which the requests to the servers are
+// logged, which --log-level=debug enables.
+const requestLogLevel = 4
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the wrapped ResponseWriter, for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// newRequestLogHandler returns a handler logging the requests passed on to
+// next, along with the status and duration of their response.
+func newRequestLogHandler(server string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !klog.V(requestLogLevel).Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		klog.V(requestLogLevel).InfoS("Served request", "server", server, "method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start), "remoteAddr", r.RemoteAddr)
+	})
+}
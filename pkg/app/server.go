@@ -32,8 +32,8 @@ import (
 
 	"gopkg.in/yaml.v3"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Initialize common client auth plugins.
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
 	"github.com/KimMachineGun/automemlimit/memlimit"
@@ -48,24 +48,38 @@ import (
 
 	"k8s.io/kube-state-metrics/v2/internal/discovery"
 	"k8s.io/kube-state-metrics/v2/internal/store"
-	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
 	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
-	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
 	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
-	"k8s.io/kube-state-metrics/v2/pkg/optin"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
+	"k8s.io/kube-state-metrics/v2/pkg/otlp"
 	"k8s.io/kube-state-metrics/v2/pkg/util"
 	"k8s.io/kube-state-metrics/v2/pkg/util/proc"
 )
 
 const (
-	metricsPath = "/metrics"
-	healthzPath = "/healthz"
-	livezPath   = "/livez"
-	readyzPath  = "/readyz"
+	metricsPath          = "/metrics"
+	collectorMetricsPath = "/metrics/{collector}"
+	namespaceMetricsPath = "/metrics/namespace/{namespace}"
+	inventoryPath        = "/metrics-inventory"
+	objectsPath          = "/objects"
+	healthzPath          = "/healthz"
+	livezPath            = "/livez"
+	readyzPath           = "/readyz"
+	reloadPath           = "/-/reload"
+	versionPath          = "/version"
 )
 
+// reloadRequests holds the reloads requested through the reload endpoint until
+// they are received, see ReloadRequests.
+var reloadRequests = make(chan struct{}, 1)
+
+// ReloadRequests returns the channel on which the reloads requested through the
+// reload endpoint of the metrics server are received.
+func ReloadRequests() <-chan struct{} {
+	return reloadRequests
+}
+
 // promLogger implements promhttp.Logger
 type promLogger struct{}
 
@@ -80,8 +94,8 @@ func (pl promLogger) Log(v ...interface{}) error {
 }
 
 // RunKubeStateMetricsWrapper runs KSM with context cancellation.
-func RunKubeStateMetricsWrapper(ctx context.Context, opts *options.Options) error {
-	err := RunKubeStateMetrics(ctx, opts)
+func RunKubeStateMetricsWrapper(ctx context.Context, opts *options.Options, factories ...customresource.RegistryFactory) error {
+	err := RunKubeStateMetrics(ctx, opts, factories...)
 	if ctx.Err() == context.Canceled {
 		klog.Infoln("Restarting: kube-state-metrics, metrics will be reset")
 		return nil
@@ -92,10 +106,12 @@ func RunKubeStateMetricsWrapper(ctx context.Context, opts *options.Options) erro
 // RunKubeStateMetrics will build and run the kube-state-metrics.
 // Any out-of-tree custom resource metrics could be registered by newing a registry factory
 // which implements customresource.RegistryFactory and pass all factories into this function.
-func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
+// The resources of the factories are enabled in addition to the ones of opts.
+func RunKubeStateMetrics(ctx context.Context, opts *options.Options, factories ...customresource.RegistryFactory) error {
 	promLogger := promLogger{}
 	ksmMetricsRegistry := prometheus.NewRegistry()
 	ksmMetricsRegistry.MustRegister(versionCollector.NewCollector("kube_state_metrics"))
+	registerClientMetrics(ksmMetricsRegistry)
 	durationVec := promauto.With(ksmMetricsRegistry).NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:        "http_request_duration_seconds",
@@ -133,9 +149,17 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		Name: "kube_state_metrics_custom_resource_state_cache",
 		Help: "Net amount of CRDs affecting the cache currently.",
 	})
+	resourcesDiscoveredGauge := promauto.With(ksmMetricsRegistry).NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kube_state_metrics_resources_discovered",
+		Help: "Custom resources which are currently discovered from the CRDs of the cluster.",
+	}, []string{"group", "version", "kind"})
 	storeBuilder := store.NewBuilder()
 	storeBuilder.WithMetrics(ksmMetricsRegistry)
 
+	if err := opts.ApplyProfile(); err != nil {
+		return err
+	}
+
 	got := options.GetConfigFile(*opts)
 	if got != "" {
 		configFile, err := os.ReadFile(filepath.Clean(got))
@@ -175,7 +199,8 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		}
 	}
 
-	kubeConfig, err := clientcmd.BuildConfigFromFlags(opts.Apiserver, opts.Kubeconfig)
+	clientOptions := util.NewClientOptions(opts)
+	kubeConfig, err := util.BuildConfig(clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to build config from flags: %v", err)
 	}
@@ -186,8 +211,6 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		return err
 	}
 
-	var factories []customresource.RegistryFactory
-
 	if opts.CustomResourceConfigFile != "" {
 		crcFile, err := os.ReadFile(filepath.Clean(opts.CustomResourceConfigFile))
 		if err != nil {
@@ -200,87 +223,45 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 
 	}
 
-	resources := make([]string, len(factories))
-
-	for i, factory := range factories {
-		resources[i] = factory.Name()
-	}
-
-	switch {
-	case len(opts.Resources) == 0 && !opts.CustomResourcesOnly:
-		resources = append(resources, options.DefaultResources.AsSlice()...)
-		klog.InfoS("Used default resources")
-	case opts.CustomResourcesOnly:
-		// enable custom resource only, these resources will be populated later on
-		klog.InfoS("Used CRD resources only")
-	default:
-		resources = append(resources, opts.Resources.AsSlice()...)
-		klog.InfoS("Used resources", "resources", resources)
-	}
-
-	if err := storeBuilder.WithEnabledResources(resources); err != nil {
-		return fmt.Errorf("failed to set up resources: %v", err)
-	}
-
-	namespaces := opts.Namespaces.GetNamespaces()
-	nsFieldSelector := namespaces.GetExcludeNSFieldSelector(opts.NamespacesDenylist)
-	var nodeFieldSelector string
-	if opts.TrackUnscheduledPods {
-		nodeFieldSelector = "spec.nodeName="
-		klog.InfoS("Using spec.nodeName= to select unscheduable pods without node")
-	} else {
-		nodeFieldSelector = opts.Node.GetNodeFieldSelector()
-	}
-	merged, err := storeBuilder.MergeFieldSelectors([]string{nsFieldSelector, nodeFieldSelector})
-	if err != nil {
-		return err
-	}
-	storeBuilder.WithNamespaces(namespaces)
-	storeBuilder.WithFieldSelectorFilter(merged)
-
-	allowDenyList, err := allowdenylist.New(opts.MetricAllowlist, opts.MetricDenylist)
+	setup, err := setupStoreBuilder(storeBuilder, opts, factories)
 	if err != nil {
 		return err
 	}
-
-	err = allowDenyList.Parse()
-	if err != nil {
-		return fmt.Errorf("error initializing the allowdeny list: %v", err)
-	}
-
-	klog.InfoS("Metric allow-denylisting", "allowDenyStatus", allowDenyList.Status())
-
-	optInMetricFamilyFilter, err := optin.NewMetricFamilyFilter(opts.MetricOptInList)
-	if err != nil {
-		return fmt.Errorf("error initializing the opt-in metric list: %v", err)
-	}
-
-	if optInMetricFamilyFilter.Count() > 0 {
-		klog.InfoS("Metrics which were opted into", "optInMetricsFamilyStatus", optInMetricFamilyFilter.Status())
+	if len(setup.factories) > 0 {
+		customResourceClients, err := util.CreateCustomResourceClients(clientOptions, setup.factories...)
+		if err != nil {
+			return fmt.Errorf("failed to create custom resource clients: %v", err)
+		}
+		storeBuilder.WithCustomResourceClients(customResourceClients)
+		storeBuilder.WithGenerateCustomResourceStoresFunc(storeBuilder.DefaultGenerateCustomResourceStoresFunc())
 	}
-
-	storeBuilder.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(
-		allowDenyList,
-		optInMetricFamilyFilter,
-	))
-
-	storeBuilder.WithUsingAPIServerCache(opts.UseAPIServerCache)
-	storeBuilder.WithGenerateStoresFunc(storeBuilder.DefaultGenerateStoresFunc())
 	proc.StartReaper()
 
-	storeBuilder.WithUtilOptions(opts)
-	kubeClient, err := util.CreateKubeClient(opts.Apiserver, opts.Kubeconfig)
+	kubeClient, err := util.CreateKubeClient(clientOptions)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %v", err)
 	}
 	storeBuilder.WithKubeClient(kubeClient)
-
-	storeBuilder.WithSharding(opts.Shard, opts.TotalShards)
-	if err := storeBuilder.WithAllowAnnotations(opts.AnnotationsAllowList); err != nil {
-		return fmt.Errorf("failed to set up annotations allowlist: %v", err)
+	if len(opts.MetadataOnlyResources) > 0 {
+		metadataClient, err := metadata.NewForConfig(kubeConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create metadata client: %v", err)
+		}
+		storeBuilder.WithMetadataClient(metadataClient)
 	}
-	if err := storeBuilder.WithAllowLabels(opts.LabelsAllowList); err != nil {
-		return fmt.Errorf("failed to set up labels allowlist: %v", err)
+	storeBuilder.WithSharding(opts.Shard, opts.TotalShards)
+
+	if opts.DryRun {
+		if config != nil {
+			if _, err := customresourcestate.FromConfig(config, nil); err != nil {
+				return err
+			}
+		}
+		return dryRun(ctx, os.Stdout, kubeClient, storeBuilder.FamilyGenerators(), setup.resources, setup.namespaces, []familyFilter{
+			{filter: setup.allowDenyList, reason: "not allowed by --metric-allowlist or --metric-denylist"},
+			{filter: setup.optInFilter, reason: "opt-in, not in --metric-opt-in-list"},
+			{filter: setup.stabilityFilter, reason: "not stable, --metric-stability=stable"},
+		})
 	}
 
 	ksmMetricsRegistry.MustRegister(
@@ -296,6 +277,17 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		storeBuilder,
 		opts.EnableGZIPEncoding,
 	)
+	m.EnableCollectorMetrics(ksmMetricsRegistry)
+	if opts.MetricsCacheTTL > 0 {
+		m.EnableResponseCache(opts.MetricsCacheTTL, ksmMetricsRegistry)
+	}
+	if opts.DumpTo != "" {
+		if config != nil {
+			return fmt.Errorf("--dump-to does not support custom resource state metrics")
+		}
+		return dumpMetrics(ctx, m, storeBuilder.Synced, opts.Shard, opts.TotalShards, opts.DumpTo)
+	}
+
 	// Run MetricsHandler
 	if config == nil {
 		ctxMetricsHandler, cancel := context.WithCancel(ctx)
@@ -305,15 +297,26 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 			cancel()
 		})
 	}
+	// Run OTLP exporter
+	if opts.OTLPEndpoint != "" {
+		exporter := otlp.NewExporter(opts.OTLPEndpoint, opts.OTLPInterval, m.WriteAll)
+		ctxExporter, cancel := context.WithCancel(ctx)
+		g.Add(func() error {
+			return exporter.Run(ctxExporter)
+		}, func(error) {
+			cancel()
+		})
+	}
 
 	tlsConfig := opts.TLSConfig
 
 	// A nil CRS config implies that we need to hold off on all CRS operations.
 	if config != nil {
 		discovererInstance := &discovery.CRDiscoverer{
-			CRDsAddEventsCounter:    crdsAddEventsCounter,
-			CRDsDeleteEventsCounter: crdsDeleteEventsCounter,
-			CRDsCacheCountGauge:     crdsCacheCountGauge,
+			CRDsAddEventsCounter:     crdsAddEventsCounter,
+			CRDsDeleteEventsCounter:  crdsDeleteEventsCounter,
+			CRDsCacheCountGauge:      crdsCacheCountGauge,
+			ResourcesDiscoveredGauge: resourcesDiscoveredGauge,
 		}
 		// This starts a goroutine that will watch for any new GVKs to extract from CRDs.
 		err = discovererInstance.StartDiscovery(ctx, kubeConfig)
@@ -335,10 +338,10 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		)
 	}
 
-	telemetryMux := buildTelemetryServer(ksmMetricsRegistry)
+	telemetryMux := buildTelemetryServer(ksmMetricsRegistry, storeBuilder.Synced)
 	telemetryListenAddress := net.JoinHostPort(opts.TelemetryHost, strconv.Itoa(opts.TelemetryPort))
 	telemetryServer := http.Server{
-		Handler:           telemetryMux,
+		Handler:           newRequestLogHandler("telemetry", telemetryMux),
 		ReadHeaderTimeout: 5 * time.Second}
 	telemetryFlags := web.FlagConfig{
 		WebListenAddresses: &[]string{telemetryListenAddress},
@@ -346,10 +349,14 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 		WebConfigFile:      &tlsConfig,
 	}
 
-	metricsMux := buildMetricsServer(m, durationVec, kubeClient)
+	metricsMux := buildMetricsServer(m, storeBuilder.Inventory(), storeBuilder.Objects(), durationVec, kubeClient, opts.EnableReloadEndpoint)
 	metricsServerListenAddress := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	var metricsHandler http.Handler = metricsMux
+	if opts.DelegateAuth {
+		metricsHandler = newDelegatedAuthHandler(kubeClient, metricsMux, healthzPath, livezPath)
+	}
 	metricsServer := http.Server{
-		Handler:           metricsMux,
+		Handler:           newRequestLogHandler("metrics", metricsHandler),
 		ReadHeaderTimeout: opts.ServerReadHeaderTimeout,
 		ReadTimeout:       opts.ServerReadTimeout,
 		WriteTimeout:      opts.ServerWriteTimeout,
@@ -375,6 +382,18 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	// Run Metrics server
 	{
 		g.Add(func() error {
+			if opts.ListenUnixSocket != "" {
+				mode, err := strconv.ParseUint(opts.ListenUnixSocketMode, 8, 32)
+				if err != nil {
+					return fmt.Errorf("invalid unix socket mode %q: %v", opts.ListenUnixSocketMode, err)
+				}
+				l, err := listenUnixSocket(opts.ListenUnixSocket, os.FileMode(mode))
+				if err != nil {
+					return fmt.Errorf("failed to listen on unix socket: %v", err)
+				}
+				klog.InfoS("Started metrics server", "metricsServerSocket", opts.ListenUnixSocket)
+				return metricsServer.Serve(l)
+			}
 			klog.InfoS("Started metrics server", "metricsServerAddress", metricsServerListenAddress)
 			return web.ListenAndServe(&metricsServer, &metricsFlags, promLogger)
 		}, func(error) {
@@ -392,16 +411,16 @@ func RunKubeStateMetrics(ctx context.Context, opts *options.Options) error {
 	return nil
 }
 
-func buildTelemetryServer(registry prometheus.Gatherer) *http.ServeMux {
+func buildTelemetryServer(registry prometheus.Gatherer, synced func() bool) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// Add metricsPath
 	mux.Handle(metricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{ErrorLog: promLogger{}}))
 
-	// Add readyzPath
+	// Add readyzPath, which only reports ready once all stores populated their initial list
 	mux.Handle(readyzPath, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		count, err := util.GatherAndCount(registry)
-		if err != nil || count == 0 {
+		if err != nil || count == 0 || !synced() {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			w.Write([]byte(http.StatusText(http.StatusServiceUnavailable)))
 			return
@@ -443,7 +462,7 @@ func handleClusterDelegationForProber(client kubernetes.Interface, probeType str
 	}
 }
 
-func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prometheus.ObserverVec, client kubernetes.Interface) *http.ServeMux {
+func buildMetricsServer(m *metricshandler.MetricsHandler, inventory, objects http.Handler, durationObserver prometheus.ObserverVec, client kubernetes.Interface, enableReload bool) *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// TODO: This doesn't belong into serveMetrics
@@ -456,6 +475,20 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 	// Add metricsPath
 	mux.Handle(metricsPath, promhttp.InstrumentHandlerDuration(durationObserver, m))
 
+	// Add collectorMetricsPath
+	mux.Handle(collectorMetricsPath, promhttp.InstrumentHandlerDuration(durationObserver, http.HandlerFunc(m.ServeCollector)))
+
+	// Add namespaceMetricsPath
+	mux.Handle(namespaceMetricsPath, promhttp.InstrumentHandlerDuration(durationObserver, http.HandlerFunc(m.ServeNamespace)))
+
+	// Add inventoryPath
+	mux.Handle(inventoryPath, inventory)
+
+	// Add objectsPath
+	if objects != nil {
+		mux.Handle(objectsPath, objects)
+	}
+
 	// Add livezPath
 	mux.Handle(livezPath, handleClusterDelegationForProber(client, livezPath))
 
@@ -465,6 +498,14 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 		w.Write([]byte(http.StatusText(http.StatusOK)))
 	})
 
+	// Add reloadPath
+	if enableReload {
+		mux.HandleFunc(reloadPath, handleReload)
+	}
+
+	// Add versionPath
+	mux.HandleFunc(versionPath, handleVersion)
+
 	// Add index
 	landingConfig := web.LandingConfig{
 		Name:        "kube-state-metrics",
@@ -475,6 +516,10 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 				Address: metricsPath,
 				Text:    "Metrics",
 			},
+			{
+				Address: inventoryPath,
+				Text:    "Metrics inventory",
+			},
 			{
 				Address: healthzPath,
 				Text:    "Healthz",
@@ -483,6 +528,10 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 				Address: livezPath,
 				Text:    "Livez",
 			},
+			{
+				Address: versionPath,
+				Text:    "Version",
+			},
 		},
 	}
 	landingPage, err := web.NewLandingPage(landingConfig)
@@ -493,6 +542,47 @@ func buildMetricsServer(m *metricshandler.MetricsHandler, durationObserver prome
 	return mux
 }
 
+// handleReload requests a reload of kube-state-metrics. Requests made while a
+// previous one wasn't received yet are merged with it.
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Only POST requests allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	select {
+	case reloadRequests <- struct{}{}:
+	default:
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(http.StatusText(http.StatusOK)))
+}
+
+// listenUnixSocket listens on the Unix domain socket at the given path with
+// the given permissions. A socket left behind at the path, e.g. by a previous
+// process which was killed, is removed first. The socket is removed again when
+// the listener is closed.
+func listenUnixSocket(path string, mode os.FileMode) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != os.ModeSocket {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
 // md5HashAsMetricValue creates an md5 hash and returns the most significant bytes that fit into a float64
 // Taken from https://github.com/prometheus/alertmanager/blob/6ef6e6868dbeb7984d2d577dd4bf75c65bf1904f/config/coordinator.go#L149
 func md5HashAsMetricValue(data []byte) float64 {
@@ -504,6 +594,15 @@ func md5HashAsMetricValue(data []byte) float64 {
 	return float64(binary.LittleEndian.Uint64(bytes))
 }
 
+// genericResourceAllowList returns the allowlist of the given generic
+// resource, or the one of all resources given as '*'.
+func genericResourceAllowList(allowList map[string][]string, name string) []string {
+	if l, ok := allowList[name]; ok {
+		return l
+	}
+	return allowList["*"]
+}
+
 func resolveCustomResourceConfig(opts *options.Options) (customresourcestate.ConfigDecoder, error) {
 	if s := opts.CustomResourceConfig; s != "" {
 		return yaml.NewDecoder(strings.NewReader(s)), nil
@@ -18,10 +18,13 @@ package app
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -206,6 +209,7 @@ func TestFullScrapeCycle(t *testing.T) {
 # HELP kube_pod_container_info [STABLE] Information about a container in a pod.
 # HELP kube_pod_container_resource_limits The number of requested limit resource by a container. It is recommended to use the kube_pod_resource_limits metric exposed by kube-scheduler instead, as it is more precise.
 # HELP kube_pod_container_resource_requests The number of requested request resource by a container. It is recommended to use the kube_pod_resource_requests metric exposed by kube-scheduler instead, as it is more precise.
+# HELP kube_pod_container_restart_policy Describes the restart policy in use by a container, which is the restart policy of the pod unless set on the container.
 # HELP kube_pod_container_state_started [STABLE] Start time in unix timestamp for a pod container.
 # HELP kube_pod_container_status_last_terminated_exitcode Describes the exit code for the last container in terminated state.
 # HELP kube_pod_container_status_last_terminated_reason Describes the last reason the container was in terminated state.
@@ -259,6 +263,7 @@ func TestFullScrapeCycle(t *testing.T) {
 # TYPE kube_pod_container_info gauge
 # TYPE kube_pod_container_resource_limits gauge
 # TYPE kube_pod_container_resource_requests gauge
+# TYPE kube_pod_container_restart_policy gauge
 # TYPE kube_pod_container_state_started gauge
 # TYPE kube_pod_container_status_last_terminated_exitcode gauge
 # TYPE kube_pod_container_status_last_terminated_reason gauge
@@ -323,6 +328,8 @@ kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",
 kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1",node="node1",resource="storage",unit="byte"} 4e+08
 kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2",node="node1",resource="cpu",unit="core"} 0.3
 kube_pod_container_resource_requests{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2",node="node1",resource="memory",unit="byte"} 2e+08
+kube_pod_container_restart_policy{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1",type="Always"} 1
+kube_pod_container_restart_policy{namespace="default",pod="pod0",uid="abc-0",container="pod1_con2",type="Always"} 1
 kube_pod_container_status_last_terminated_exitcode{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1"} 137
 kube_pod_container_status_last_terminated_reason{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1",reason="OOMKilled"} 1
 kube_pod_container_status_last_terminated_timestamp{namespace="default",pod="pod0",uid="abc-0",container="pod1_con1"} 1.501779547e+09
@@ -381,7 +388,14 @@ kube_pod_status_reason{namespace="default",pod="pod0",uid="abc-0",reason="Unexpe
 		}
 	}
 
-	telemetryMux := buildTelemetryServer(reg)
+	telemetryMux := buildTelemetryServer(reg, builder.Synced)
+
+	reqReady := httptest.NewRequest("GET", "http://localhost:8081/readyz", nil)
+	wReady := httptest.NewRecorder()
+	telemetryMux.ServeHTTP(wReady, reqReady)
+	if wReady.Code != 200 {
+		t.Fatalf("expected 200 status code for synced stores but got %v", wReady.Code)
+	}
 
 	req2 := httptest.NewRequest("GET", "http://localhost:8081/metrics", nil)
 
@@ -432,6 +446,77 @@ kube_state_metrics_total_shards 1
 
 // TestShardingEquivalenceScrapeCycle is a simple smoke test covering the entire cycle from
 // cache filling to scraping comparing a sharded with an unsharded setup.
+func TestReloadEndpoint(t *testing.T) {
+	w := httptest.NewRecorder()
+	handleReload(w, httptest.NewRequest("GET", "http://localhost:8080/-/reload", nil))
+	if w.Code != 405 {
+		t.Fatalf("expected 405 status code for GET but got %v", w.Code)
+	}
+	select {
+	case <-ReloadRequests():
+		t.Fatal("expected no reload request for GET")
+	default:
+	}
+
+	for i := 0; i < 2; i++ {
+		w = httptest.NewRecorder()
+		handleReload(w, httptest.NewRequest("POST", "http://localhost:8080/-/reload", nil))
+		if w.Code != 200 {
+			t.Fatalf("expected 200 status code for POST but got %v", w.Code)
+		}
+	}
+	select {
+	case <-ReloadRequests():
+	default:
+		t.Fatal("expected a reload request for POST")
+	}
+	select {
+	case <-ReloadRequests():
+		t.Fatal("expected pending reload requests to be merged")
+	default:
+	}
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ksm.sock")
+
+	l, err := listenUnixSocket(path, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("expected socket mode 0600 but got %v", fi.Mode().Perm())
+	}
+
+	// A socket left behind by a previous process is replaced.
+	if err := os.Rename(path, path+".stale"); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if err := os.Rename(path+".stale", path); err != nil {
+		t.Fatal(err)
+	}
+	l, err = listenUnixSocket(path, 0660)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected socket to be removed on close, got %v", err)
+	}
+
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := listenUnixSocket(path, 0660); err == nil {
+		t.Fatal("expected error for existing file which is not a socket")
+	}
+}
+
 func TestShardingEquivalenceScrapeCycle(t *testing.T) {
 	t.Parallel()
 
@@ -973,3 +1058,58 @@ func (f *fooFactory) ListWatch(customResourceClient interface{}, ns string, fiel
 		},
 	}
 }
+
+func TestDumpMetrics(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	if err := pod(kubeClient, 0); err != nil {
+		t.Fatalf("failed to insert sample pod %v", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	builder := store.NewBuilder()
+	builder.WithMetrics(prometheus.NewRegistry())
+	if err := builder.WithEnabledResources([]string{"pods"}); err != nil {
+		t.Fatal(err)
+	}
+	builder.WithKubeClient(kubeClient)
+	builder.WithNamespaces(options.DefaultNamespaces)
+	builder.WithGenerateStoresFunc(builder.DefaultGenerateStoresFunc())
+	l, err := allowdenylist.New(map[string]struct{}{}, map[string]struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder.WithFamilyGeneratorFilter(l)
+	builder.WithAllowLabels(map[string][]string{})
+
+	handler := metricshandler.New(&options.Options{}, kubeClient, builder, false)
+	path := filepath.Join(t.TempDir(), "metrics.prom.gz")
+	if err := dumpMetrics(ctx, handler, builder.Synced, 0, 1, path); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("expected a gzip compressed dump: %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), `kube_pod_info{namespace="default",pod="pod0"`) {
+		t.Fatalf("expected the metrics of pod0 in the dump, got:\n%s", body)
+	}
+
+	matches, err := filepath.Glob(path + ".tmp*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("expected no temporary files to be left, got %v", matches)
+	}
+}
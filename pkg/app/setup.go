@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/optin"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// builderSetup is what setupStoreBuilder derived from the options which is
+// needed after the store builder is configured.
+type builderSetup struct {
+	// factories are the given custom resource factories and the ones of the
+	// generic resources.
+	factories       []customresource.RegistryFactory
+	resources       []string
+	namespaces      options.NamespaceList
+	allowDenyList   *allowdenylist.AllowDenyList
+	optInFilter     *optin.MetricFamilyFilter
+	stabilityFilter generator.StabilityFamilyGeneratorFilter
+}
+
+// setupStoreBuilder configures the store builder from the options, except for
+// the clients, so that the options are checked the same way by
+// RunKubeStateMetrics and ValidateOptions.
+func setupStoreBuilder(storeBuilder *store.Builder, opts *options.Options, factories []customresource.RegistryFactory) (builderSetup, error) {
+	var genericFactories []customresource.RegistryFactory
+	for _, r := range opts.GenericResources {
+		gvr, err := customresource.ParseGroupVersionResource(r)
+		if err != nil {
+			return builderSetup{}, fmt.Errorf("failed to parse generic resources: %v", err)
+		}
+		name := gvr.GroupResource().String()
+		genericFactories = append(genericFactories, customresource.NewGenericRegistryFactory(
+			gvr,
+			genericResourceAllowList(opts.AnnotationsAllowList, name),
+			genericResourceAllowList(opts.LabelsAllowList, name),
+		))
+	}
+	if err := storeBuilder.WithGenericResourceStoreFactories(genericFactories...); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up generic resources: %v", err)
+	}
+	storeBuilder.WithCustomResourceStoreFactories(factories...)
+	factories = append(factories, genericFactories...)
+
+	resources := make([]string, len(factories))
+
+	for i, factory := range factories {
+		resources[i] = factory.Name()
+	}
+
+	switch {
+	case len(opts.Resources) == 0 && !opts.CustomResourcesOnly:
+		resources = append(resources, options.DefaultResources.AsSlice()...)
+		klog.InfoS("Used default resources")
+	case opts.CustomResourcesOnly:
+		// enable custom resource only, these resources will be populated later on
+		klog.InfoS("Used CRD resources only")
+	default:
+		resources = append(resources, opts.Resources.AsSlice()...)
+		klog.InfoS("Used resources", "resources", resources)
+	}
+
+	if err := storeBuilder.WithEnabledResources(resources); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up resources: %v", err)
+	}
+	if err := storeBuilder.WithInitialListOrder(opts.InitialListOrder); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up initial list order: %v", err)
+	}
+	storeBuilder.WithInitialListConcurrency(opts.InitialListConcurrency)
+	storeBuilder.WithResyncPeriod(opts.ResyncPeriod)
+	storeBuilder.WithRelistPeriod(opts.RelistPeriod)
+	storeBuilder.WithObjectInventory(opts.EnableObjectsEndpoint)
+
+	namespaces := opts.Namespaces.GetNamespaces()
+	nsFilter, err := options.NewNamespaceFilter(namespaces, opts.NamespacesDenylist)
+	if err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up namespace filter: %v", err)
+	}
+	var nsFieldSelector string
+	if nsFilter != nil {
+		// Glob patterns can neither be expressed as namespaced reflectors nor as
+		// field selectors, so all namespaces are watched and filtered instead.
+		klog.InfoS("Using namespace patterns", "namespaces", namespaces, "namespacesDenylist", opts.NamespacesDenylist)
+		namespaces = options.DefaultNamespaces
+		storeBuilder.WithNamespacePatternFilter(nsFilter)
+	} else {
+		nsFieldSelector = namespaces.GetExcludeNSFieldSelector(opts.NamespacesDenylist)
+	}
+	var nodeFieldSelector string
+	if opts.TrackUnscheduledPods {
+		nodeFieldSelector = "spec.nodeName="
+		klog.InfoS("Using spec.nodeName= to select unscheduable pods without node")
+	} else {
+		nodeFieldSelector = opts.Node.GetNodeFieldSelector()
+	}
+	merged, err := storeBuilder.MergeFieldSelectors([]string{nsFieldSelector, nodeFieldSelector})
+	if err != nil {
+		return builderSetup{}, err
+	}
+	storeBuilder.WithNamespaces(namespaces)
+	storeBuilder.WithFieldSelectorFilter(merged)
+	if err := storeBuilder.WithResourceFieldSelectors(opts.ResourceFieldSelectors); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up resource field selectors: %v", err)
+	}
+	if err := storeBuilder.WithObjectsLabelSelector(opts.ObjectsLabelSelector); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up objects label selector: %v", err)
+	}
+	if err := storeBuilder.WithResourceLabelSelectors(opts.ResourceLabelSelectors); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up resource label selectors: %v", err)
+	}
+
+	allowDenyList, err := allowdenylist.New(opts.MetricAllowlist, opts.MetricDenylist)
+	if err != nil {
+		return builderSetup{}, err
+	}
+
+	err = allowDenyList.Parse()
+	if err != nil {
+		return builderSetup{}, fmt.Errorf("error initializing the allowdeny list: %v", err)
+	}
+
+	klog.InfoS("Metric allow-denylisting", "allowDenyStatus", allowDenyList.Status())
+
+	optInMetricFamilyFilter, err := optin.NewMetricFamilyFilter(opts.MetricOptInList)
+	if err != nil {
+		return builderSetup{}, fmt.Errorf("error initializing the opt-in metric list: %v", err)
+	}
+
+	if optInMetricFamilyFilter.Count() > 0 {
+		klog.InfoS("Metrics which were opted into", "optInMetricsFamilyStatus", optInMetricFamilyFilter.Status())
+	}
+
+	stabilityFilter, err := generator.NewStabilityFamilyGeneratorFilter(opts.MetricStability)
+	if err != nil {
+		return builderSetup{}, fmt.Errorf("error initializing the metric stability filter: %v", err)
+	}
+
+	storeBuilder.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(
+		allowDenyList,
+		optInMetricFamilyFilter,
+		stabilityFilter,
+	))
+
+	storeBuilder.WithUsingAPIServerCache(opts.UseAPIServerCache)
+	storeBuilder.WithNamespaceRecreationTracking(opts.TrackNamespaceRecreation)
+	storeBuilder.WithGenerateStoresFunc(storeBuilder.DefaultGenerateStoresFunc())
+	storeBuilder.WithUtilOptions(opts)
+	if err := storeBuilder.WithMetadataOnlyResources(opts.MetadataOnlyResources); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up metadata-only resources: %v", err)
+	}
+
+	if err := storeBuilder.WithAllowAnnotations(opts.AnnotationsAllowList); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up annotations allowlist: %v", err)
+	}
+	if err := storeBuilder.WithAllowLabels(opts.LabelsAllowList); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up labels allowlist: %v", err)
+	}
+	if err := storeBuilder.WithMetricAliases(opts.MetricAliases); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up metric aliases: %v", err)
+	}
+	if err := storeBuilder.WithMetricLabelsDenylist(opts.MetricLabelsDenylist); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up metric labels denylist: %v", err)
+	}
+	if err := storeBuilder.WithMetricTimestampInfo(opts.MetricTimestampInfo); err != nil {
+		return builderSetup{}, fmt.Errorf("failed to set up metric timestamp info: %v", err)
+	}
+
+	return builderSetup{
+		factories:       factories,
+		resources:       resources,
+		namespaces:      namespaces,
+		allowDenyList:   allowDenyList,
+		optInFilter:     optInMetricFamilyFilter,
+		stabilityFilter: stabilityFilter,
+	}, nil
+}
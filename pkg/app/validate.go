@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"k8s.io/kube-state-metrics/v2/internal/store"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+// ValidateOptions checks the given options with the setup of
+// RunKubeStateMetrics, without connecting to the apiserver: the options config
+// file, the custom resource state configuration, the resources, the selectors
+// and the metric lists. Unlike RunKubeStateMetrics, it returns an error for an
+// invalid options config file instead of waiting for it to be fixed.
+func ValidateOptions(opts *options.Options) error {
+	if err := opts.ApplyProfile(); err != nil {
+		return err
+	}
+	if file := options.GetConfigFile(*opts); file != "" {
+		configFile, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return fmt.Errorf("failed to read opts config file: %v", err)
+		}
+		if err := yaml.Unmarshal(configFile, opts); err != nil {
+			return fmt.Errorf("failed to unmarshal opts config file: %v", err)
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	config, err := resolveCustomResourceConfig(opts)
+	if err != nil {
+		return err
+	}
+	if config != nil {
+		if _, err := customresourcestate.FromConfig(config, nil); err != nil {
+			return err
+		}
+	}
+	_, err = setupStoreBuilder(store.NewBuilder(), opts, nil)
+	return err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*options.Options)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			modify: func(*options.Options) {},
+		},
+		{
+			name: "unknown resource",
+			modify: func(o *options.Options) {
+				o.Resources = options.ResourceSet{"notaresources": struct{}{}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid metric allowlist",
+			modify: func(o *options.Options) {
+				o.MetricAllowlist = options.MetricSet{"kube_pod_(": struct{}{}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown metric stability",
+			modify: func(o *options.Options) {
+				o.MetricStability = "beta"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid custom resource state config",
+			modify: func(o *options.Options) {
+				o.CustomResourceConfig = "spec: ["
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := options.NewOptions()
+			tc.modify(opts)
+			err := ValidateOptions(opts)
+			if tc.wantErr && err == nil {
+				t.Fatal("expected an error, got none")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
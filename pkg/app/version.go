@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/prometheus/common/version"
+	"k8s.io/klog/v2"
+)
+
+// versionInfo is the build information served by the version endpoint.
+type versionInfo struct {
+	Version   string `json:"version"`
+	Revision  string `json:"revision"`
+	Branch    string `json:"branch"`
+	BuildUser string `json:"buildUser"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// handleVersion serves the build information as JSON.
+func handleVersion(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := WriteVersion(w, "json"); err != nil {
+		klog.ErrorS(err, "Failed to write version")
+	}
+}
+
+// WriteVersion writes the build information set through the ldflags of the
+// build to w, as text like --version if format is text, or as JSON like the
+// version endpoint if it is json.
+func WriteVersion(w io.Writer, format string) error {
+	switch format {
+	case "text":
+		_, err := fmt.Fprintln(w, version.Print("kube-state-metrics"))
+		return err
+	case "json":
+		return json.NewEncoder(w).Encode(versionInfo{
+			Version:   version.Version,
+			Revision:  version.Revision,
+			Branch:    version.Branch,
+			BuildUser: version.BuildUser,
+			BuildDate: version.BuildDate,
+			GoVersion: version.GoVersion,
+			Platform:  version.GoOS + "/" + version.GoArch,
+		})
+	default:
+		return fmt.Errorf("unknown version format %q, must be one of text or json", format)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package benchmark
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	"k8s.io/kube-state-metrics/v2/pkg/builder"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/metricshandler"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+var clusters = []struct {
+	name    string
+	cluster Cluster
+}{
+	{"small", Cluster{Namespaces: 10, Nodes: 10, Deployments: 100, ReplicasPerDeployment: 3}},
+	{"large", Cluster{Namespaces: 50, Nodes: 200, Deployments: 1000, ReplicasPerDeployment: 5}},
+}
+
+// newHandler returns a metrics handler serving the metrics of the given
+// cluster once the initial lists of all resources completed.
+func newHandler(tb testing.TB, ctx context.Context, c Cluster) *metricshandler.MetricsHandler {
+	tb.Helper()
+
+	kubeClient := fake.NewSimpleClientset(c.Objects()...)
+	b := builder.NewBuilder()
+	b.WithMetrics(prometheus.NewRegistry())
+	if err := b.WithEnabledResources(options.DefaultResources.AsSlice()); err != nil {
+		tb.Fatal(err)
+	}
+	b.WithKubeClient(kubeClient)
+	b.WithNamespaces(options.DefaultNamespaces)
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+	allowDenyList, err := allowdenylist.New(options.MetricSet{}, options.MetricSet{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if err := allowDenyList.Parse(); err != nil {
+		tb.Fatal(err)
+	}
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(allowDenyList))
+	if err := b.WithAllowAnnotations(map[string][]string{}); err != nil {
+		tb.Fatal(err)
+	}
+	if err := b.WithAllowLabels(map[string][]string{}); err != nil {
+		tb.Fatal(err)
+	}
+
+	handler := metricshandler.New(&options.Options{}, kubeClient, b, false)
+	handler.ConfigureSharding(ctx, 0, 1)
+	for !b.Synced() {
+		if ctx.Err() != nil {
+			tb.Fatal("stores did not sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return handler
+}
+
+// BenchmarkSync measures the time and allocations of the initial lists of a
+// cluster until all stores are synced.
+func BenchmarkSync(b *testing.B) {
+	for _, c := range clusters {
+		b.Run(c.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				objects := c.cluster.Objects()
+				b.StartTimer()
+
+				newHandler(b, ctx, c.cluster)
+
+				b.StopTimer()
+				cancel()
+				b.ReportMetric(float64(len(objects)), "objects")
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+// BenchmarkScrape measures the latency, allocations and response size of
+// scrapes of the metrics of a synced cluster.
+func BenchmarkScrape(b *testing.B) {
+	for _, c := range clusters {
+		b.Run(c.name, func(b *testing.B) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			handler := newHandler(b, ctx, c.cluster)
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/metrics", nil)
+
+			var size int
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, req)
+				if w.Code != http.StatusOK {
+					b.Fatalf("expected 200 status code but got %v", w.Code)
+				}
+				size = w.Body.Len()
+			}
+			b.ReportMetric(float64(size), "bytes/scrape")
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package benchmark generates synthetic clusters to benchmark kube-state-metrics
+// against, e.g. with fake clientsets. The benchmarks of this package measure
+// the scrape latency, allocations and response size of the Builder for
+// clusters of different sizes, see `make test-benchmark`.
+package benchmark
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+// Cluster describes the size of a synthetic cluster. The deployments are
+// spread across the namespaces and their pods across the nodes.
+type Cluster struct {
+	Namespaces            int
+	Nodes                 int
+	Deployments           int
+	ReplicasPerDeployment int
+}
+
+// creationTimestamp is the creation timestamp of all generated objects, so
+// the generated metrics don't depend on the time they are generated at.
+var creationTimestamp = metav1.NewTime(time.Unix(1500000000, 0))
+
+// Objects returns the namespaces, nodes, deployments, replicasets and pods of
+// the cluster.
+func (c Cluster) Objects() []runtime.Object {
+	objects := make([]runtime.Object, 0, c.Namespaces+c.Nodes+c.Deployments*(2+c.ReplicasPerDeployment))
+	for i := 0; i < c.Namespaces; i++ {
+		objects = append(objects, namespace(i))
+	}
+	for i := 0; i < c.Nodes; i++ {
+		objects = append(objects, node(i))
+	}
+	for i := 0; i < c.Deployments; i++ {
+		ns := fmt.Sprintf("namespace-%d", i%max(c.Namespaces, 1))
+		d := deployment(ns, i, c.ReplicasPerDeployment)
+		rs := replicaSet(d)
+		objects = append(objects, d, rs)
+		for j := 0; j < c.ReplicasPerDeployment; j++ {
+			nodeName := fmt.Sprintf("node-%d", (i*c.ReplicasPerDeployment+j)%max(c.Nodes, 1))
+			objects = append(objects, pod(rs, j, nodeName))
+		}
+	}
+	return objects
+}
+
+func namespace(i int) *v1.Namespace {
+	name := fmt.Sprintf("namespace-%d", i)
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: creationTimestamp,
+			Labels:            map[string]string{"kubernetes.io/metadata.name": name},
+		},
+		Status: v1.NamespaceStatus{Phase: v1.NamespaceActive},
+	}
+}
+
+func node(i int) *v1.Node {
+	name := fmt.Sprintf("node-%d", i)
+	capacity := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("16"),
+		v1.ResourceMemory: resource.MustParse("64Gi"),
+		v1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name),
+			CreationTimestamp: creationTimestamp,
+			Labels: map[string]string{
+				"kubernetes.io/hostname":           name,
+				"topology.kubernetes.io/zone":      fmt.Sprintf("zone-%d", i%3),
+				"node.kubernetes.io/instance-type": "standard-16",
+			},
+		},
+		Spec: v1.NodeSpec{ProviderID: "fake://" + name},
+		Status: v1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Conditions: []v1.NodeCondition{
+				{Type: v1.NodeReady, Status: v1.ConditionTrue},
+				{Type: v1.NodeMemoryPressure, Status: v1.ConditionFalse},
+				{Type: v1.NodeDiskPressure, Status: v1.ConditionFalse},
+				{Type: v1.NodePIDPressure, Status: v1.ConditionFalse},
+			},
+			NodeInfo: v1.NodeSystemInfo{
+				KernelVersion:           "6.1.0",
+				OSImage:                 "Linux",
+				ContainerRuntimeVersion: "containerd://1.7.0",
+				KubeletVersion:          "v1.30.0",
+			},
+		},
+	}
+}
+
+func deployment(ns string, i, replicas int) *appsv1.Deployment {
+	name := fmt.Sprintf("deployment-%d", i)
+	labels := map[string]string{"app": name}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         ns,
+			UID:               types.UID(ns + "-" + name),
+			CreationTimestamp: creationTimestamp,
+			Generation:        1,
+			Labels:            labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(replicas)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       podSpec(""),
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           int32(replicas),
+			UpdatedReplicas:    int32(replicas),
+			ReadyReplicas:      int32(replicas),
+			AvailableReplicas:  int32(replicas),
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: v1.ConditionTrue},
+				{Type: appsv1.DeploymentProgressing, Status: v1.ConditionTrue},
+			},
+		},
+	}
+}
+
+func replicaSet(d *appsv1.Deployment) *appsv1.ReplicaSet {
+	name := d.Name + "-5d9c8b7f6"
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         d.Namespace,
+			UID:               types.UID(d.Namespace + "-" + name),
+			CreationTimestamp: creationTimestamp,
+			Generation:        1,
+			Labels:            d.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: d.Name, UID: d.UID, Controller: ptr.To(true)},
+			},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: d.Spec.Replicas,
+			Selector: d.Spec.Selector,
+			Template: d.Spec.Template,
+		},
+		Status: appsv1.ReplicaSetStatus{
+			ObservedGeneration:   1,
+			Replicas:             *d.Spec.Replicas,
+			FullyLabeledReplicas: *d.Spec.Replicas,
+			ReadyReplicas:        *d.Spec.Replicas,
+			AvailableReplicas:    *d.Spec.Replicas,
+		},
+	}
+}
+
+func pod(rs *appsv1.ReplicaSet, i int, nodeName string) *v1.Pod {
+	name := fmt.Sprintf("%s-%d", rs.Name, i)
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         rs.Namespace,
+			UID:               types.UID(rs.Namespace + "-" + name),
+			CreationTimestamp: creationTimestamp,
+			Labels:            rs.Labels,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: rs.Name, UID: rs.UID, Controller: ptr.To(true)},
+			},
+		},
+		Spec: podSpec(nodeName),
+		Status: v1.PodStatus{
+			Phase:     v1.PodRunning,
+			HostIP:    "10.0.0.1",
+			PodIP:     "10.1.0.1",
+			StartTime: &creationTimestamp,
+			QOSClass:  v1.PodQOSBurstable,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: creationTimestamp},
+				{Type: v1.PodInitialized, Status: v1.ConditionTrue, LastTransitionTime: creationTimestamp},
+				{Type: v1.ContainersReady, Status: v1.ConditionTrue, LastTransitionTime: creationTimestamp},
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: creationTimestamp},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:         "app",
+					Image:        "registry.k8s.io/app:v1",
+					ImageID:      "registry.k8s.io/app@sha256:0123456789abcdef",
+					ContainerID:  "containerd://" + name,
+					Ready:        true,
+					Started:      ptr.To(true),
+					RestartCount: 1,
+					State:        v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: creationTimestamp}},
+				},
+			},
+		},
+	}
+}
+
+func podSpec(nodeName string) v1.PodSpec {
+	return v1.PodSpec{
+		NodeName:      nodeName,
+		SchedulerName: "default-scheduler",
+		RestartPolicy: v1.RestartPolicyAlways,
+		Containers: []v1.Container{
+			{
+				Name:  "app",
+				Image: "registry.k8s.io/app:v1",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("100m"),
+						v1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("256Mi"),
+					},
+				},
+			},
+		},
+	}
+}
@@ -18,9 +18,12 @@ package builder
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
 
 	internalstore "k8s.io/kube-state-metrics/v2/internal/store"
@@ -59,16 +62,61 @@ func (b *Builder) WithEnabledResources(c []string) error {
 	return b.internal.WithEnabledResources(c)
 }
 
+// WithInitialListConcurrency sets the maximum number of resources performing
+// their initial list at the same time.
+func (b *Builder) WithInitialListConcurrency(c int) {
+	b.internal.WithInitialListConcurrency(c)
+}
+
+// WithResyncPeriod sets the resync period of the reflectors.
+func (b *Builder) WithResyncPeriod(d time.Duration) {
+	b.internal.WithResyncPeriod(d)
+}
+
+// WithRelistPeriod sets the period after which the reflectors list their
+// objects again.
+func (b *Builder) WithRelistPeriod(d time.Duration) {
+	b.internal.WithRelistPeriod(d)
+}
+
+// WithInitialListOrder sets the resources whose initial list is started first.
+func (b *Builder) WithInitialListOrder(r []string) error {
+	return b.internal.WithInitialListOrder(r)
+}
+
 // WithNamespaces sets the namespaces property of a Builder.
 func (b *Builder) WithNamespaces(n options.NamespaceList) {
 	b.internal.WithNamespaces(n)
 }
 
+// WithNamespacePatternFilter sets the namespacePatternFilter property of a Builder.
+func (b *Builder) WithNamespacePatternFilter(f *options.NamespaceFilter) {
+	b.internal.WithNamespacePatternFilter(f)
+}
+
 // WithFieldSelectorFilter sets the fieldSelector property of a Builder.
 func (b *Builder) WithFieldSelectorFilter(fieldSelectorFilter string) {
 	b.internal.WithFieldSelectorFilter(fieldSelectorFilter)
 }
 
+// WithResourceFieldSelectors configures additional field selectors of the
+// given resources, keyed by resource name.
+func (b *Builder) WithResourceFieldSelectors(selectors map[string]string) error {
+	return b.internal.WithResourceFieldSelectors(selectors)
+}
+
+// WithObjectsLabelSelector configures the label selector the watched objects
+// have to match.
+func (b *Builder) WithObjectsLabelSelector(selector string) error {
+	return b.internal.WithObjectsLabelSelector(selector)
+}
+
+// WithResourceLabelSelectors configures the label selectors of the given
+// resources, keyed by resource name.
+func (b *Builder) WithResourceLabelSelectors(selectors map[string]string) error {
+	return b.internal.WithResourceLabelSelectors(selectors)
+}
+
 // WithSharding sets the shard and totalShards property of a Builder.
 func (b *Builder) WithSharding(shard int32, totalShards int) {
 	b.internal.WithSharding(shard, totalShards)
@@ -84,7 +132,19 @@ func (b *Builder) WithKubeClient(c clientset.Interface) {
 	b.internal.WithKubeClient(c)
 }
 
-// WithCustomResourceClients sets the customResourceClients property of a Builder.
+// WithMetadataClient sets the metadataClient property of a Builder.
+func (b *Builder) WithMetadataClient(c metadata.Interface) {
+	b.internal.WithMetadataClient(c)
+}
+
+// WithMetadataOnlyResources configures the given resources to be listed and
+// watched as metadata only.
+func (b *Builder) WithMetadataOnlyResources(r []string) error {
+	return b.internal.WithMetadataOnlyResources(r)
+}
+
+// WithCustomResourceClients adds the given clients to the customResourceClients
+// property of a Builder.
 func (b *Builder) WithCustomResourceClients(cs map[string]interface{}) {
 	b.internal.WithCustomResourceClients(cs)
 }
@@ -94,6 +154,12 @@ func (b *Builder) WithUsingAPIServerCache(u bool) {
 	b.internal.WithUsingAPIServerCache(u)
 }
 
+// WithNamespaceRecreationTracking configures whether the reflectors of the
+// namespaces of a Builder are restarted when their namespace is recreated.
+func (b *Builder) WithNamespaceRecreationTracking(t bool) {
+	b.internal.WithNamespaceRecreationTracking(t)
+}
+
 // WithFamilyGeneratorFilter configures the family generator filter which decides which
 // metrics are to be exposed by the store build by the Builder.
 func (b *Builder) WithFamilyGeneratorFilter(l generator.FamilyGeneratorFilter) {
@@ -110,6 +176,47 @@ func (b *Builder) WithAllowLabels(l map[string][]string) error {
 	return b.internal.WithAllowLabels(l)
 }
 
+// WithMetricAliases configures the legacy names under which metric families
+// are exposed in addition to their own name, keyed by the family name.
+func (b *Builder) WithMetricAliases(a map[string]string) error {
+	return b.internal.WithMetricAliases(a)
+}
+
+// WithMetricTimestampInfo configures the timestamp families which are
+// additionally exposed as info families with an RFC3339 timestamp label.
+func (b *Builder) WithMetricTimestampInfo(m options.MetricSet) error {
+	return b.internal.WithMetricTimestampInfo(m)
+}
+
+// WithMetricLabelsDenylist configures the labels which are dropped from the
+// metrics of the given families, keyed by family name.
+func (b *Builder) WithMetricLabelsDenylist(denylist map[string][]string) error {
+	return b.internal.WithMetricLabelsDenylist(denylist)
+}
+
+// Synced returns whether the stores of the last build populated the initial
+// list of their resource.
+func (b *Builder) Synced() bool {
+	return b.internal.Synced()
+}
+
+// Inventory returns a handler serving the enabled metric families as JSON.
+func (b *Builder) Inventory() http.Handler {
+	return b.internal.Inventory()
+}
+
+// WithObjectInventory sets whether the cached objects are recorded for the
+// object inventory served by Objects.
+func (b *Builder) WithObjectInventory(enabled bool) {
+	b.internal.WithObjectInventory(enabled)
+}
+
+// Objects returns a handler serving the cached objects as JSON, or nil if the
+// object inventory is disabled.
+func (b *Builder) Objects() http.Handler {
+	return b.internal.Objects()
+}
+
 // WithGenerateStoresFunc configures a custom generate store function
 func (b *Builder) WithGenerateStoresFunc(f ksmtypes.BuildStoresFunc) {
 	b.internal.WithGenerateStoresFunc(f)
@@ -130,6 +237,11 @@ func (b *Builder) WithCustomResourceStoreFactories(fs ...customresource.Registry
 	b.internal.WithCustomResourceStoreFactories(fs...)
 }
 
+// WithGenericResourceStoreFactories configures the stores of generic resources.
+func (b *Builder) WithGenericResourceStoreFactories(fs ...customresource.RegistryFactory) error {
+	return b.internal.WithGenericResourceStoreFactories(fs...)
+}
+
 // Build initializes and registers all enabled stores.
 // Returns metric writers.
 func (b *Builder) Build() metricsstore.MetricsWriterList {
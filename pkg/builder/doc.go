@@ -0,0 +1,30 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package builder is the public entry point for embedding kube-state-metrics
+// as a library. It exposes the same Builder the kube-state-metrics binary uses
+// to set up its stores, and is covered by the Go API compatibility of the
+// module: methods of ksmtypes.BuilderInterface are only removed after a
+// deprecation period.
+//
+// A Builder needs at least a context (WithContext), the self metrics registry
+// (WithMetrics), a Kubernetes client (WithKubeClient), the namespaces to watch
+// (WithNamespaces), the resources to watch (WithEnabledResources) and a
+// FamilyGeneratorFilter (WithFamilyGeneratorFilter) before Build is called.
+// The filter is usually built from an allowdenylist.AllowDenyList. Build
+// starts the reflectors and returns the MetricsWriterList from which the
+// metrics are written out.
+package builder
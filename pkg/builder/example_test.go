@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builder_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
+	"k8s.io/kube-state-metrics/v2/pkg/builder"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func Example() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		panic(err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	// Only expose the pod phase and restart metrics.
+	allowDenyList, err := allowdenylist.New(map[string]struct{}{
+		"kube_pod_status_phase":                    {},
+		"kube_pod_container_status_restarts_total": {},
+	}, map[string]struct{}{})
+	if err != nil {
+		panic(err)
+	}
+	if err := allowDenyList.Parse(); err != nil {
+		panic(err)
+	}
+
+	b := builder.NewBuilder()
+	b.WithContext(ctx)
+	b.WithMetrics(prometheus.NewRegistry())
+	b.WithKubeClient(kubeClient)
+	b.WithNamespaces(options.NamespaceList{"default"})
+	b.WithFamilyGeneratorFilter(generator.NewCompositeFamilyGeneratorFilter(allowDenyList))
+	if err := b.WithEnabledResources([]string{"pods"}); err != nil {
+		panic(err)
+	}
+	b.WithGenerateStoresFunc(b.DefaultGenerateStoresFunc())
+
+	for _, w := range b.Build() {
+		if err := w.WriteAll(os.Stdout); err != nil {
+			panic(err)
+		}
+	}
+}
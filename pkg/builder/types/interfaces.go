@@ -18,11 +18,14 @@ package types
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
 
 	"github.com/prometheus/client_golang/prometheus"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/cache"
 
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
@@ -34,22 +37,41 @@ import (
 type BuilderInterface interface {
 	WithMetrics(r prometheus.Registerer)
 	WithEnabledResources(c []string) error
+	WithInitialListConcurrency(c int)
+	WithInitialListOrder(r []string) error
+	WithResyncPeriod(d time.Duration)
+	WithRelistPeriod(d time.Duration)
 	WithNamespaces(n options.NamespaceList)
+	WithNamespacePatternFilter(f *options.NamespaceFilter)
 	WithFieldSelectorFilter(fieldSelectors string)
+	WithResourceFieldSelectors(selectors map[string]string) error
+	WithObjectsLabelSelector(selector string) error
+	WithResourceLabelSelectors(selectors map[string]string) error
 	WithSharding(shard int32, totalShards int)
 	WithContext(ctx context.Context)
 	WithKubeClient(c clientset.Interface)
+	WithMetadataClient(c metadata.Interface)
+	WithMetadataOnlyResources(r []string) error
 	WithCustomResourceClients(cs map[string]interface{})
 	WithUsingAPIServerCache(u bool)
+	WithNamespaceRecreationTracking(t bool)
 	WithFamilyGeneratorFilter(l generator.FamilyGeneratorFilter)
 	WithAllowAnnotations(a map[string][]string) error
 	WithAllowLabels(l map[string][]string) error
+	WithMetricAliases(a map[string]string) error
+	WithMetricLabelsDenylist(denylist map[string][]string) error
+	WithMetricTimestampInfo(m options.MetricSet) error
 	WithGenerateStoresFunc(f BuildStoresFunc)
 	DefaultGenerateStoresFunc() BuildStoresFunc
 	DefaultGenerateCustomResourceStoresFunc() BuildCustomResourceStoresFunc
 	WithCustomResourceStoreFactories(fs ...customresource.RegistryFactory)
+	WithGenericResourceStoreFactories(fs ...customresource.RegistryFactory) error
 	Build() metricsstore.MetricsWriterList
 	BuildStores() [][]cache.Store
+	Synced() bool
+	Inventory() http.Handler
+	WithObjectInventory(enabled bool)
+	Objects() http.Handler
 	WithGenerateCustomResourceStoresFunc(f BuildCustomResourceStoresFunc)
 }
 
@@ -0,0 +1,258 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresource
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	generator "k8s.io/kube-state-metrics/v2/pkg/metric_generator"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+var invalidMetricNameCharRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// genericResource is a RegistryFactory providing the baseline metrics of any
+// resource, watching its objects as unstructured objects.
+type genericResource struct {
+	gvr                  schema.GroupVersionResource
+	metricNamePrefix     string
+	allowAnnotationsList []string
+	allowLabelsList      []string
+}
+
+var _ RegistryFactory = &genericResource{}
+
+// ParseGroupVersionResource parses a resource given as group/version/resource,
+// or as version/resource for the core group.
+//
+// Example: cert-manager.io/v1/certificates, v1/events
+func ParseGroupVersionResource(s string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(s, "/")
+	for _, p := range parts {
+		if p == "" {
+			return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected group/version/resource or version/resource", s)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected group/version/resource or version/resource", s)
+	}
+}
+
+// NewGenericRegistryFactory returns a RegistryFactory for the given resource,
+// which generates the kube_<group>_<resource>_info, _created, _labels and
+// _annotations metrics. It gives baseline coverage to resources which have no
+// store of their own. The factory is named resource.group, e.g.
+// certificates.cert-manager.io.
+func NewGenericRegistryFactory(gvr schema.GroupVersionResource, allowAnnotationsList, allowLabelsList []string) RegistryFactory {
+	prefix := "kube_"
+	if gvr.Group != "" {
+		prefix += invalidMetricNameCharRE.ReplaceAllString(gvr.Group, "_") + "_"
+	}
+	prefix += invalidMetricNameCharRE.ReplaceAllString(gvr.Resource, "_")
+
+	return &genericResource{
+		gvr:                  gvr,
+		metricNamePrefix:     prefix,
+		allowAnnotationsList: allowAnnotationsList,
+		allowLabelsList:      allowLabelsList,
+	}
+}
+
+func (g *genericResource) Name() string {
+	return g.gvr.GroupResource().String()
+}
+
+func (g *genericResource) CreateClient(cfg *rest.Config) (interface{}, error) {
+	c, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c.Resource(g.gvr), nil
+}
+
+func (g *genericResource) MetricFamilyGenerators() []generator.FamilyGenerator {
+	return []generator.FamilyGenerator{
+		*generator.NewFamilyGeneratorWithStability(
+			g.metricNamePrefix+"_info",
+			fmt.Sprintf("Information about the %s.", g.Name()),
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapUnstructuredFunc(func(u *unstructured.Unstructured) *metric.Family {
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"kind", "uid"},
+							LabelValues: []string{u.GetKind(), string(u.GetUID())},
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			g.metricNamePrefix+"_created",
+			"Unix creation timestamp",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapUnstructuredFunc(func(u *unstructured.Unstructured) *metric.Family {
+				ms := []*metric.Metric{}
+				created := u.GetCreationTimestamp()
+				if !created.IsZero() {
+					ms = append(ms, &metric.Metric{
+						Value: float64(created.Unix()),
+					})
+				}
+
+				return &metric.Family{
+					Metrics: ms,
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			g.metricNamePrefix+"_annotations",
+			"Kubernetes annotations converted to Prometheus labels.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapUnstructuredFunc(func(u *unstructured.Unstructured) *metric.Family {
+				if len(g.allowAnnotationsList) == 0 {
+					return &metric.Family{}
+				}
+				annotationKeys, annotationValues := allowedLabelKeysValues("annotation", u.GetAnnotations(), g.allowAnnotationsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   annotationKeys,
+							LabelValues: annotationValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+		*generator.NewFamilyGeneratorWithStability(
+			g.metricNamePrefix+"_labels",
+			"Kubernetes labels converted to Prometheus labels.",
+			metric.Gauge,
+			basemetrics.ALPHA,
+			"",
+			wrapUnstructuredFunc(func(u *unstructured.Unstructured) *metric.Family {
+				if len(g.allowLabelsList) == 0 {
+					return &metric.Family{}
+				}
+				labelKeys, labelValues := allowedLabelKeysValues("label", u.GetLabels(), g.allowLabelsList)
+				return &metric.Family{
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   labelKeys,
+							LabelValues: labelValues,
+							Value:       1,
+						},
+					},
+				}
+			}),
+		),
+	}
+}
+
+// ExpectedType returns an unstructured object without an apiVersion, so the
+// stores of the resource are identified by its name.
+func (g *genericResource) ExpectedType() interface{} {
+	return &unstructured.Unstructured{}
+}
+
+func (g *genericResource) ListWatch(customResourceClient interface{}, ns string, fieldSelector string) cache.ListerWatcher {
+	api := customResourceClient.(dynamic.NamespaceableResourceInterface).Namespace(ns)
+	ctx := context.Background()
+	return &cache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.FieldSelector = fieldSelector
+			return api.List(ctx, opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.FieldSelector = fieldSelector
+			return api.Watch(ctx, opts)
+		},
+	}
+}
+
+func wrapUnstructuredFunc(f func(*unstructured.Unstructured) *metric.Family) func(interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		u := obj.(*unstructured.Unstructured)
+
+		metricFamily := f(u)
+
+		for _, m := range metricFamily.Metrics {
+			m.LabelKeys = append([]string{"namespace", "name"}, m.LabelKeys...)
+			m.LabelValues = append([]string{u.GetNamespace(), u.GetName()}, m.LabelValues...)
+		}
+
+		return metricFamily
+	}
+}
+
+// allowedLabelKeysValues converts the allowed keys of the given map to
+// Prometheus label names with the given prefix, sorted by name. Keys which
+// convert to the same name are only kept once.
+func allowedLabelKeysValues(prefix string, m map[string]string, allowList []string) ([]string, []string) {
+	allowed := map[string]string{}
+	if allowList[0] == options.LabelWildcard {
+		for k, v := range m {
+			allowed[prefix+"_"+invalidMetricNameCharRE.ReplaceAllString(k, "_")] = v
+		}
+	} else {
+		for _, k := range allowList {
+			if v, ok := m[k]; ok {
+				allowed[prefix+"_"+invalidMetricNameCharRE.ReplaceAllString(k, "_")] = v
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(allowed))
+	for k := range allowed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = allowed[k]
+	}
+	return keys, values
+}
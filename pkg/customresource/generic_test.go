@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customresource
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseGroupVersionResource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    schema.GroupVersionResource
+		wantErr bool
+	}{
+		{in: "cert-manager.io/v1/certificates", want: schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}},
+		{in: "v1/events", want: schema.GroupVersionResource{Version: "v1", Resource: "events"}},
+		{in: "certificates", wantErr: true},
+		{in: "cert-manager.io//certificates", wantErr: true},
+		{in: "a/b/c/d", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGroupVersionResource(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: expected error %t, got %v", tt.in, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestGenericRegistryFactory(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	f := NewGenericRegistryFactory(gvr, nil, []string{"app"})
+	if f.Name() != "certificates.cert-manager.io" {
+		t.Fatalf("unexpected name %q", f.Name())
+	}
+
+	u := &unstructured.Unstructured{}
+	u.SetKind("Certificate")
+	u.SetNamespace("ns1")
+	u.SetName("cert1")
+	u.SetUID("uid1")
+	u.SetCreationTimestamp(metav1.NewTime(time.Unix(1500000000, 0)))
+	u.SetLabels(map[string]string{"app": "web", "team": "a"})
+
+	var got []string
+	for _, g := range f.MetricFamilyGenerators() {
+		got = append(got, string(g.Generate(u).ByteSlice()))
+	}
+
+	want := []string{
+		`kube_cert_manager_io_certificates_info{namespace="ns1",name="cert1",kind="Certificate",uid="uid1"} 1`,
+		`kube_cert_manager_io_certificates_created{namespace="ns1",name="cert1"} 1.5e+09`,
+		``,
+		`kube_cert_manager_io_certificates_labels{namespace="ns1",name="cert1",label_app="web"} 1`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d families, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if strings.TrimSpace(got[i]) != want[i] {
+			t.Errorf("expected %q, got %q", want[i], strings.TrimSpace(got[i]))
+		}
+	}
+}
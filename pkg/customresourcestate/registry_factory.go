@@ -529,7 +529,7 @@ func addPathLabels(obj interface{}, labels map[string]valuePath, result map[stri
 				if strings.HasSuffix(star, "*") {
 					k = star[:len(star)-1] + k
 				}
-				result[store.SanitizeLabelName(k)] = fmt.Sprintf("%v", v)
+				result[store.PrometheusLabelName(k)] = fmt.Sprintf("%v", v)
 			}
 		}
 	}
@@ -542,7 +542,7 @@ func addPathLabels(obj interface{}, labels map[string]valuePath, result map[stri
 		if value == nil {
 			continue
 		}
-		result[store.SanitizeLabelName(k)] = fmt.Sprintf("%v", value)
+		result[store.PrometheusLabelName(k)] = fmt.Sprintf("%v", value)
 	}
 }
 
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"fmt"
+)
+
+// AliasFamilyGenerators returns the given family generators together with an
+// alias family generator for each family which has an alias, i.e. a legacy
+// name, in the given map of family names to aliases. The alias families are
+// generated with the same generate function, which allows renaming a metric
+// family while still exposing it under its legacy name. Aliases which are the
+// name of one of the given families are skipped, as they would expose the
+// family twice. An alias is enabled whenever its family is, so it is not
+// opt-in on its own.
+func AliasFamilyGenerators(aliases map[string]string, families []FamilyGenerator) []FamilyGenerator {
+	if len(aliases) == 0 {
+		return families
+	}
+
+	names := make(map[string]struct{}, len(families))
+	for _, family := range families {
+		names[family.Name] = struct{}{}
+	}
+
+	aliased := make([]FamilyGenerator, 0, len(families))
+	for _, family := range families {
+		aliased = append(aliased, family)
+		alias, ok := aliases[family.Name]
+		if !ok {
+			continue
+		}
+		if _, ok := names[alias]; ok {
+			continue
+		}
+		a := family
+		a.Name = alias
+		a.OptIn = false
+		a.Help = fmt.Sprintf("(Deprecated alias of %s) %s", family.Name, family.Help)
+		aliased = append(aliased, a)
+	}
+
+	return aliased
+}
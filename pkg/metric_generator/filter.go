@@ -16,6 +16,12 @@ limitations under the License.
 
 package generator
 
+import (
+	"fmt"
+
+	basemetrics "k8s.io/component-base/metrics"
+)
+
 // FamilyGeneratorFilter represents a filter which decides whether a metric
 // family is exposed by the store or not
 type FamilyGeneratorFilter interface {
@@ -59,3 +65,40 @@ func FilterFamilyGenerators(filter FamilyGeneratorFilter, families []FamilyGener
 func NewCompositeFamilyGeneratorFilter(filters ...FamilyGeneratorFilter) CompositeFamilyGeneratorFilter {
 	return CompositeFamilyGeneratorFilter{filters}
 }
+
+const (
+	// StabilityExperimental selects the metric families of all stability
+	// levels.
+	StabilityExperimental = "experimental"
+	// StabilityStable selects the stable metric families which are not
+	// deprecated.
+	StabilityStable = "stable"
+)
+
+// StabilityFamilyGeneratorFilter filters metric families by their stability
+// level.
+type StabilityFamilyGeneratorFilter struct {
+	stableOnly bool
+}
+
+// Test returns false for the experimental and deprecated metric families if
+// only stable ones are selected.
+func (filter StabilityFamilyGeneratorFilter) Test(generator FamilyGenerator) bool {
+	if !filter.stableOnly {
+		return true
+	}
+	return generator.StabilityLevel == basemetrics.STABLE && generator.DeprecatedVersion == ""
+}
+
+// NewStabilityFamilyGeneratorFilter creates a filter for the metric families of
+// the given stability, StabilityExperimental or StabilityStable.
+func NewStabilityFamilyGeneratorFilter(stability string) (StabilityFamilyGeneratorFilter, error) {
+	switch stability {
+	case StabilityExperimental, "":
+		return StabilityFamilyGeneratorFilter{}, nil
+	case StabilityStable:
+		return StabilityFamilyGeneratorFilter{stableOnly: true}, nil
+	default:
+		return StabilityFamilyGeneratorFilter{}, fmt.Errorf("unknown metric stability %q, must be one of %s or %s", stability, StabilityExperimental, StabilityStable)
+	}
+}
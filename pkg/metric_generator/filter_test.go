@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"testing"
+
+	basemetrics "k8s.io/component-base/metrics"
+)
+
+func TestStabilityFamilyGeneratorFilter(t *testing.T) {
+	stable := FamilyGenerator{Name: "kube_pod_info", StabilityLevel: basemetrics.STABLE}
+	experimental := FamilyGenerator{Name: "kube_pod_status_reason", StabilityLevel: basemetrics.ALPHA}
+	deprecated := FamilyGenerator{Name: "kube_pod_legacy", StabilityLevel: basemetrics.STABLE, DeprecatedVersion: "2.0.0"}
+
+	tests := []struct {
+		stability string
+		want      []bool
+	}{
+		{stability: "", want: []bool{true, true, true}},
+		{stability: StabilityExperimental, want: []bool{true, true, true}},
+		{stability: StabilityStable, want: []bool{true, false, false}},
+	}
+	for _, test := range tests {
+		filter, err := NewStabilityFamilyGeneratorFilter(test.stability)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.stability, err)
+		}
+		for i, f := range []FamilyGenerator{stable, experimental, deprecated} {
+			if got := filter.Test(f); got != test.want[i] {
+				t.Errorf("expected %v for %s with stability %q, got %v", test.want[i], f.Name, test.stability, got)
+			}
+		}
+	}
+
+	if _, err := NewStabilityFamilyGeneratorFilter("beta"); err == nil {
+		t.Error("expected an error for an unknown stability")
+	}
+}
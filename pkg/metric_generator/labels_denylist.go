@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// LabelsDenylistWildcard denylists labels of all families.
+const LabelsDenylistWildcard = "*"
+
+// LabelsDenylistFamilyGenerators returns the given family generators with the
+// denylisted labels dropped from their metrics. The denylist is keyed by family
+// name, the labels of the LabelsDenylistWildcard key being dropped from all
+// families. Dropping a label which distinguishes the series of a family
+// results in duplicate series.
+func LabelsDenylistFamilyGenerators(denylist map[string][]string, families []FamilyGenerator) []FamilyGenerator {
+	if len(denylist) == 0 {
+		return families
+	}
+
+	res := make([]FamilyGenerator, 0, len(families))
+	for _, family := range families {
+		labels := make(map[string]struct{})
+		for _, l := range denylist[LabelsDenylistWildcard] {
+			labels[l] = struct{}{}
+		}
+		for _, l := range denylist[family.Name] {
+			labels[l] = struct{}{}
+		}
+		if len(labels) > 0 {
+			family.GenerateFunc = labelsDenylistGenerateFunc(labels, family.GenerateFunc)
+		}
+		res = append(res, family)
+	}
+
+	return res
+}
+
+func labelsDenylistGenerateFunc(labels map[string]struct{}, f func(obj interface{}) *metric.Family) func(obj interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		family := f(obj)
+		for _, m := range family.Metrics {
+			labelKeys := make([]string, 0, len(m.LabelKeys))
+			labelValues := make([]string, 0, len(m.LabelValues))
+			for i, k := range m.LabelKeys {
+				if _, ok := labels[k]; ok {
+					continue
+				}
+				labelKeys = append(labelKeys, k)
+				labelValues = append(labelValues, m.LabelValues[i])
+			}
+			m.LabelKeys = labelKeys
+			m.LabelValues = labelValues
+		}
+		return family
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generator
+
+import (
+	"fmt"
+	"time"
+
+	basemetrics "k8s.io/component-base/metrics"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// TimestampInfoFamilyGenerators returns the given family generators together
+// with an info family generator for each timestamp family, e.g.
+// kube_pod_created, in the given set of family names. The info family is named
+// <family>_info and exposes the series of the timestamp family with their Unix
+// timestamp formatted as RFC3339 in the timestamp label, for human readable
+// dashboards.
+func TimestampInfoFamilyGenerators(names map[string]struct{}, families []FamilyGenerator) []FamilyGenerator {
+	if len(names) == 0 {
+		return families
+	}
+
+	res := make([]FamilyGenerator, 0, len(families))
+	for _, family := range families {
+		res = append(res, family)
+		if _, ok := names[family.Name]; !ok {
+			continue
+		}
+		info := family
+		info.Name = family.Name + "_info"
+		info.Help = fmt.Sprintf("Timestamp of %s in RFC3339 format.", family.Name)
+		info.Type = metric.Gauge
+		info.StabilityLevel = basemetrics.ALPHA
+		info.DeprecatedVersion = ""
+		info.GenerateFunc = timestampInfoGenerateFunc(family.GenerateFunc)
+		res = append(res, info)
+	}
+
+	return res
+}
+
+func timestampInfoGenerateFunc(f func(obj interface{}) *metric.Family) func(obj interface{}) *metric.Family {
+	return func(obj interface{}) *metric.Family {
+		family := f(obj)
+		ms := make([]*metric.Metric, 0, len(family.Metrics))
+		for _, m := range family.Metrics {
+			labelKeys := make([]string, 0, len(m.LabelKeys)+1)
+			labelKeys = append(labelKeys, m.LabelKeys...)
+			labelValues := make([]string, 0, len(m.LabelValues)+1)
+			labelValues = append(labelValues, m.LabelValues...)
+			ms = append(ms, &metric.Metric{
+				LabelKeys:   append(labelKeys, "timestamp"),
+				LabelValues: append(labelValues, time.Unix(int64(m.Value), 0).UTC().Format(time.RFC3339)),
+				Value:       1,
+			})
+		}
+		return &metric.Family{Metrics: ms}
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 )
@@ -34,9 +35,12 @@ type MetricsStore struct {
 	// grouped by metric families in order to zip families with their help text in
 	// MetricsStore.WriteAll().
 	metrics map[types.UID][][]byte
+	// namespaces holds the namespace of each namespaced object of metrics.
+	namespaces map[types.UID]string
 
 	// generateMetricsFunc generates metrics based on a given Kubernetes object
-	// and returns them grouped by metric family.
+	// and returns them grouped by metric family. It returns nil if the metrics
+	// of the object could not be generated.
 	generateMetricsFunc func(interface{}) []metric.FamilyInterface
 	// headers contains the header (TYPE and HELP) of each metric family. It is
 	// later on zipped with with their corresponding metric families in
@@ -53,6 +57,7 @@ func NewMetricsStore(headers []string, generateFunc func(interface{}) []metric.F
 		generateMetricsFunc: generateFunc,
 		headers:             headers,
 		metrics:             map[types.UID][][]byte{},
+		namespaces:          map[types.UID]string{},
 	}
 }
 
@@ -60,6 +65,7 @@ func NewMetricsStore(headers []string, generateFunc func(interface{}) []metric.F
 
 // Add inserts adds to the MetricsStore by calling the metrics generator functions and
 // adding the generated metrics to the metrics map that underlies the MetricStore.
+// Objects whose metrics could not be generated are skipped.
 func (s *MetricsStore) Add(obj interface{}) error {
 	o, err := meta.Accessor(obj)
 	if err != nil {
@@ -70,6 +76,11 @@ func (s *MetricsStore) Add(obj interface{}) error {
 	defer s.mutex.Unlock()
 
 	families := s.generateMetricsFunc(obj)
+	if families == nil {
+		delete(s.metrics, o.GetUID())
+		delete(s.namespaces, o.GetUID())
+		return nil
+	}
 	familyStrings := make([][]byte, len(families))
 
 	for i, f := range families {
@@ -77,6 +88,9 @@ func (s *MetricsStore) Add(obj interface{}) error {
 	}
 
 	s.metrics[o.GetUID()] = familyStrings
+	if ns := o.GetNamespace(); ns != "" {
+		s.namespaces[o.GetUID()] = ns
+	}
 
 	return nil
 }
@@ -87,8 +101,12 @@ func (s *MetricsStore) Update(obj interface{}) error {
 	return s.Add(obj)
 }
 
-// Delete deletes an existing entry in the MetricsStore.
+// Delete deletes an existing entry in the MetricsStore. The object may be a
+// cache.DeletedFinalStateUnknown tombstone of the deleted object.
 func (s *MetricsStore) Delete(obj interface{}) error {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
 
 	o, err := meta.Accessor(obj)
 	if err != nil {
@@ -99,6 +117,7 @@ func (s *MetricsStore) Delete(obj interface{}) error {
 	defer s.mutex.Unlock()
 
 	delete(s.metrics, o.GetUID())
+	delete(s.namespaces, o.GetUID())
 
 	return nil
 }
@@ -128,6 +147,7 @@ func (s *MetricsStore) GetByKey(_ string) (item interface{}, exists bool, err er
 func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 	s.mutex.Lock()
 	s.metrics = map[types.UID][][]byte{}
+	s.namespaces = map[types.UID]string{}
 	s.mutex.Unlock()
 
 	for _, o := range list {
@@ -140,6 +160,21 @@ func (s *MetricsStore) Replace(list []interface{}, _ string) error {
 	return nil
 }
 
+// Bytes returns the number of bytes of the metrics held by the MetricsStore,
+// as an estimate of its memory usage.
+func (s *MetricsStore) Bytes() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	bytes := 0
+	for _, families := range s.metrics {
+		for _, f := range families {
+			bytes += len(f)
+		}
+	}
+	return bytes
+}
+
 // Resync implements the Resync method of the store interface.
 func (s *MetricsStore) Resync() error {
 	return nil
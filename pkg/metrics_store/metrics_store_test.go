@@ -18,6 +18,7 @@ package metricsstore
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 )
@@ -83,3 +85,125 @@ func TestObjectsSameNameDifferentNamespaces(t *testing.T) {
 		}
 	}
 }
+
+func TestDeleteTombstone(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_service_info",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"uid"},
+					LabelValues: []string{string(o.GetUID())},
+					Value:       float64(1),
+				},
+			},
+		}}
+	}
+
+	ms := NewMetricsStore([]string{"Information about service."}, genFunc)
+	s := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "service", Namespace: "a", UID: types.UID("a")}}
+	if err := ms.Add(s); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ms.Delete(cache.DeletedFinalStateUnknown{Key: "a/service", Obj: s}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := strings.Builder{}
+	if err := NewMetricsWriter(ms).WriteAll(&w); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	if strings.Contains(w.String(), `uid="a"`) {
+		t.Fatalf("expected the metrics of the deleted service to be removed, got:\n%s", w.String())
+	}
+}
+
+// BenchmarkMetricsStoreLabelInterning compares the heap retained by the store
+// when the label values are allocated for every object with the heap retained
+// when they are shared between objects, as an interning layer would do. The
+// store only keeps the rendered metrics, so both retain the same bytes.
+func BenchmarkMetricsStoreLabelInterning(b *testing.B) {
+	const objects = 10000
+
+	pods := make([]*v1.Pod, objects)
+	for i := range pods {
+		pods[i] = &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("pod-%d", i),
+			Namespace: "kube-system",
+			UID:       types.UID(fmt.Sprintf("uid-%d", i)),
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		value func(string) string
+	}{
+		{
+			name:  "allocated",
+			value: strings.Clone,
+		},
+		{
+			name:  "interned",
+			value: func(s string) string { return s },
+		},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			genFunc := func(obj interface{}) []metric.FamilyInterface {
+				o, err := meta.Accessor(obj)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				return []metric.FamilyInterface{&metric.Family{
+					Name: "kube_pod_info",
+					Metrics: []*metric.Metric{
+						{
+							LabelKeys:   []string{"namespace", "pod", "node"},
+							LabelValues: []string{test.value(o.GetNamespace()), o.GetName(), test.value("node-1")},
+							Value:       float64(1),
+						},
+					},
+				}}
+			}
+
+			b.ReportAllocs()
+			var retained int64
+			for i := 0; i < b.N; i++ {
+				ms := NewMetricsStore([]string{"Information about pod."}, genFunc)
+
+				b.StopTimer()
+				before := heapAlloc()
+				b.StartTimer()
+
+				for _, p := range pods {
+					if err := ms.Add(p); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.StopTimer()
+				retained += heapAlloc() - before
+				runtime.KeepAlive(ms)
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(retained)/float64(b.N*objects), "retained-B/obj")
+		})
+	}
+}
+
+// heapAlloc returns the bytes of the reachable heap objects after a garbage
+// collection.
+func heapAlloc() int64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
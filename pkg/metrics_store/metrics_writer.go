@@ -37,7 +37,8 @@ type MetricsWriterList []*MetricsWriter
 // metrics with the same name coming from different stores end up grouped together.
 // It also ensures that the metric headers are only written out once.
 type MetricsWriter struct {
-	stores []*MetricsStore
+	resource string
+	stores   []*MetricsStore
 }
 
 // NewMetricsWriter creates a new MetricsWriter.
@@ -47,11 +48,56 @@ func NewMetricsWriter(stores ...*MetricsStore) *MetricsWriter {
 	}
 }
 
+// NewResourceMetricsWriter creates a new MetricsWriter for the stores of the
+// given resource.
+func NewResourceMetricsWriter(resource string, stores ...*MetricsStore) *MetricsWriter {
+	return &MetricsWriter{
+		resource: resource,
+		stores:   stores,
+	}
+}
+
+// Resource returns the name of the resource the MetricsWriter writes out, or
+// an empty string if it was created without one.
+func (m MetricsWriter) Resource() string {
+	return m.resource
+}
+
 // WriteAll writes out metrics from the underlying stores to the given writer.
 //
 // WriteAll writes metrics so that the ones with the same name
 // are grouped together when written out.
 func (m MetricsWriter) WriteAll(w io.Writer) error {
+	return m.WriteFamilies(w, nil)
+}
+
+// WriteFamilies writes out the metrics of the families for whose name include
+// returns true from the underlying stores to the given writer. All families
+// are written out if include is nil.
+func (m MetricsWriter) WriteFamilies(w io.Writer, include func(name string) bool) error {
+	return m.WriteValidatedFamilies(w, include, nil)
+}
+
+// WriteValidatedFamilies writes out the metrics like WriteFamilies, but drops
+// the series which the given SeriesValidator has already seen. No series are
+// dropped if the validator is nil.
+func (m MetricsWriter) WriteValidatedFamilies(w io.Writer, include func(name string) bool, v *SeriesValidator) error {
+	return m.writeFamilies(w, "", include, v)
+}
+
+// WriteNamespaceFamilies writes out the metrics like WriteValidatedFamilies,
+// restricted to the objects of the given namespace. The metrics of
+// cluster-scoped objects are not written out.
+func (m MetricsWriter) WriteNamespaceFamilies(w io.Writer, namespace string, include func(name string) bool, v *SeriesValidator) error {
+	if namespace == "" {
+		return nil
+	}
+	return m.writeFamilies(w, namespace, include, v)
+}
+
+// writeFamilies writes out the metrics of the objects of the given namespace,
+// or of all objects if it is empty.
+func (m MetricsWriter) writeFamilies(w io.Writer, namespace string, include func(name string) bool, v *SeriesValidator) error {
 	if len(m.stores) == 0 {
 		return nil
 	}
@@ -63,12 +109,29 @@ func (m MetricsWriter) WriteAll(w io.Writer) error {
 		}(s)
 	}
 
+	hasMetrics := len(m.stores[0].metrics) > 0
+	if namespace != "" {
+		hasMetrics = false
+		for _, s := range m.stores {
+			for _, ns := range s.namespaces {
+				if ns == namespace {
+					hasMetrics = true
+					break
+				}
+			}
+		}
+	}
+
 	for i, help := range m.stores[0].headers {
+		if include != nil && !include(familyName(help)) {
+			continue
+		}
+
 		if help != "" && help != "\n" {
 			help += "\n"
 		}
 
-		if len(m.stores[0].metrics) > 0 {
+		if hasMetrics {
 			_, err := w.Write([]byte(help))
 			if err != nil {
 				return fmt.Errorf("failed to write help text: %v", err)
@@ -76,8 +139,15 @@ func (m MetricsWriter) WriteAll(w io.Writer) error {
 		}
 
 		for _, s := range m.stores {
-			for _, metricFamilies := range s.metrics {
-				_, err := w.Write(metricFamilies[i])
+			for uid, metricFamilies := range s.metrics {
+				if namespace != "" && s.namespaces[uid] != namespace {
+					continue
+				}
+				family := metricFamilies[i]
+				if v != nil {
+					family = v.validate(family, uid)
+				}
+				_, err := w.Write(family)
 				if err != nil {
 					return fmt.Errorf("failed to write metrics family: %v", err)
 				}
@@ -87,6 +157,12 @@ func (m MetricsWriter) WriteAll(w io.Writer) error {
 	return nil
 }
 
+// familyName returns the name of the metric family described by the given header.
+func familyName(header string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(header, "# HELP "), " ")
+	return name
+}
+
 // SanitizeHeaders sanitizes the headers of the given MetricsWriterList.
 func SanitizeHeaders(contentType string, writers MetricsWriterList) MetricsWriterList {
 	var lastHeader string
@@ -28,6 +28,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	"k8s.io/kube-state-metrics/v2/pkg/metric"
 )
@@ -267,6 +268,204 @@ func TestWriteAllWithEmptyStores(t *testing.T) {
 	}
 }
 
+func TestWriteFamilies(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mf1 := metric.Family{
+			Name: "kube_service_info_1",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"uid"},
+					LabelValues: []string{string(o.GetUID())},
+					Value:       float64(1),
+				},
+			},
+		}
+
+		mf2 := metric.Family{
+			Name: "kube_service_info_2",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"uid"},
+					LabelValues: []string{string(o.GetUID())},
+					Value:       float64(1),
+				},
+			},
+		}
+
+		return []metric.FamilyInterface{&mf1, &mf2}
+	}
+	store := NewMetricsStore([]string{
+		"# HELP kube_service_info_1 Info 1 about services\n# TYPE kube_service_info_1 gauge",
+		"# HELP kube_service_info_2 Info 2 about services\n# TYPE kube_service_info_2 gauge",
+	}, genFunc)
+	svc := v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       "a1",
+			Name:      "service",
+			Namespace: "a",
+		},
+	}
+	if err := store.Add(&svc); err != nil {
+		t.Fatal(err)
+	}
+
+	w := strings.Builder{}
+	err := NewMetricsWriter(store).WriteFamilies(&w, func(name string) bool {
+		return name == "kube_service_info_2"
+	})
+	if err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+
+	expected := `# HELP kube_service_info_2 Info 2 about services
+# TYPE kube_service_info_2 gauge
+kube_service_info_2{uid="a1"} 1
+`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Fatalf("unexpected output (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWriteValidatedFamilies(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mf := metric.Family{
+			Name: "kube_service_info",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"namespace"},
+					LabelValues: []string{o.GetNamespace()},
+					Value:       float64(1),
+				},
+				{
+					LabelKeys:   []string{"namespace", "service"},
+					LabelValues: []string{o.GetNamespace(), o.GetName()},
+					Value:       float64(1),
+				},
+			},
+		}
+
+		return []metric.FamilyInterface{&mf}
+	}
+	store := NewMetricsStore([]string{
+		"# HELP kube_service_info Info about services\n# TYPE kube_service_info gauge",
+	}, genFunc)
+	for _, svc := range []v1.Service{
+		{ObjectMeta: metav1.ObjectMeta{UID: "a1", Name: "service-1", Namespace: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{UID: "a2", Name: "service-2", Namespace: "a"}},
+	} {
+		if err := store.Add(&svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var duplicates []string
+	v := NewSeriesValidator()
+	v.OnDuplicate = func(series string, _ types.UID) {
+		duplicates = append(duplicates, series)
+	}
+
+	w := strings.Builder{}
+	if err := NewMetricsWriter(store).WriteValidatedFamilies(&w, nil, v); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+
+	if diff := cmp.Diff([]string{`kube_service_info{namespace="a"}`}, duplicates); diff != "" {
+		t.Fatalf("unexpected duplicates (-want, +got):\n%s", diff)
+	}
+	if got := strings.Count(w.String(), `kube_service_info{namespace="a"} 1`); got != 1 {
+		t.Fatalf("expected the duplicate series to be written out once, got %d times:\n%s", got, w.String())
+	}
+	for _, series := range []string{`service="service-1"`, `service="service-2"`} {
+		if !strings.Contains(w.String(), series) {
+			t.Fatalf("expected series with %s to be kept:\n%s", series, w.String())
+		}
+	}
+}
+
+func TestWriteNamespaceFamilies(t *testing.T) {
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mf := metric.Family{
+			Name: "kube_service_info",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"namespace", "service"},
+					LabelValues: []string{o.GetNamespace(), o.GetName()},
+					Value:       float64(1),
+				},
+			},
+		}
+
+		return []metric.FamilyInterface{&mf}
+	}
+	store := NewMetricsStore([]string{
+		"# HELP kube_service_info Info about services\n# TYPE kube_service_info gauge",
+	}, genFunc)
+	for _, svc := range []v1.Service{
+		{ObjectMeta: metav1.ObjectMeta{UID: "a1", Name: "service-1", Namespace: "a"}},
+		{ObjectMeta: metav1.ObjectMeta{UID: "b1", Name: "service-2", Namespace: "b"}},
+	} {
+		if err := store.Add(&svc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{
+			namespace: "a",
+			want: "# HELP kube_service_info Info about services\n# TYPE kube_service_info gauge\n" +
+				`kube_service_info{namespace="a",service="service-1"} 1` + "\n",
+		},
+		{
+			namespace: "b",
+			want: "# HELP kube_service_info Info about services\n# TYPE kube_service_info gauge\n" +
+				`kube_service_info{namespace="b",service="service-2"} 1` + "\n",
+		},
+		{
+			namespace: "c",
+			want:      "",
+		},
+	}
+
+	for _, test := range tests {
+		w := strings.Builder{}
+		if err := NewMetricsWriter(store).WriteNamespaceFamilies(&w, test.namespace, nil, nil); err != nil {
+			t.Fatalf("failed to write metrics: %v", err)
+		}
+		if diff := cmp.Diff(test.want, w.String()); diff != "" {
+			t.Errorf("unexpected metrics for namespace %q (-want, +got):\n%s", test.namespace, diff)
+		}
+	}
+
+	if err := store.Delete(&v1.Service{ObjectMeta: metav1.ObjectMeta{UID: "a1", Name: "service-1", Namespace: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	w := strings.Builder{}
+	if err := NewMetricsWriter(store).WriteNamespaceFamilies(&w, "a", nil, nil); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+	if w.String() != "" {
+		t.Errorf("expected no metrics for namespace a after deletion, got:\n%s", w.String())
+	}
+}
+
 // No two consecutive headers will be entirely the same. The cases used below are only for their suffixes.
 func TestSanitizeHeaders(t *testing.T) {
 	testcases := []struct {
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsstore
+
+import (
+	"bytes"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SeriesValidator detects series which are written out more than once, e.g.
+// by two objects generating the same family and label set, which makes
+// Prometheus reject the whole scrape. A SeriesValidator holds the series of a
+// single response, so a new one has to be used for each response.
+type SeriesValidator struct {
+	// OnDuplicate is called with each dropped series and the UID of the
+	// object which generated it.
+	OnDuplicate func(series string, uid types.UID)
+
+	seen map[string]struct{}
+}
+
+// NewSeriesValidator returns a new SeriesValidator.
+func NewSeriesValidator() *SeriesValidator {
+	return &SeriesValidator{seen: map[string]struct{}{}}
+}
+
+// validate returns the given rendered metrics of an object without the series
+// which were already seen. The given slice is returned as is if none of its
+// series were seen, otherwise a copy.
+func (v *SeriesValidator) validate(family []byte, uid types.UID) []byte {
+	var out []byte
+	dropped := false
+	for offset := 0; offset < len(family); {
+		line, _, found := bytes.Cut(family[offset:], []byte("\n"))
+		end := offset + len(line)
+		if found {
+			end++
+		}
+
+		// The series is everything but the value at the end of the line.
+		series := line
+		if i := bytes.LastIndexByte(line, ' '); i >= 0 {
+			series = line[:i]
+		}
+		if _, ok := v.seen[string(series)]; ok && len(series) > 0 {
+			if !dropped {
+				out = append([]byte{}, family[:offset]...)
+				dropped = true
+			}
+			if v.OnDuplicate != nil {
+				v.OnDuplicate(string(series), uid)
+			}
+		} else {
+			v.seen[string(series)] = struct{}{}
+			if dropped {
+				out = append(out, family[offset:end]...)
+			}
+		}
+		offset = end
+	}
+
+	if !dropped {
+		return family
+	}
+	return out
+}
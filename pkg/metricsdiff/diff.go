@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metricsdiff compares the metrics exposed by two kube-state-metrics
+// instances, e.g. before and after an upgrade.
+package metricsdiff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+)
+
+// LabelChange describes the label names which differ between the series of a
+// metric family in two expositions.
+type LabelChange struct {
+	Family  string
+	Added   []string
+	Removed []string
+}
+
+// ValueChange describes a series whose value changed by more than the
+// threshold between two expositions.
+type ValueChange struct {
+	Series string
+	A      float64
+	B      float64
+}
+
+// Report is the result of comparing exposition A with exposition B.
+type Report struct {
+	AddedFamilies   []string
+	RemovedFamilies []string
+	LabelChanges    []LabelChange
+	ValueChanges    []ValueChange
+	// AddedSeries and RemovedSeries count the series per family which are only
+	// present in B, respectively A.
+	AddedSeries   map[string]int
+	RemovedSeries map[string]int
+}
+
+// Empty returns true if no differences were found.
+func (r *Report) Empty() bool {
+	return len(r.AddedFamilies) == 0 && len(r.RemovedFamilies) == 0 && len(r.LabelChanges) == 0 &&
+		len(r.ValueChanges) == 0 && len(r.AddedSeries) == 0 && len(r.RemovedSeries) == 0
+}
+
+// Fetch scrapes the given metrics endpoint and parses its exposition.
+func Fetch(ctx context.Context, client *http.Client, url string) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", string(expfmt.NewFormat(expfmt.TypeTextPlain)))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to scrape %s: unexpected status %s", url, resp.Status)
+	}
+
+	families, err := Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics of %s: %w", url, err)
+	}
+	return families, nil
+}
+
+// Parse parses a text exposition. The info and stateset types, which are
+// unknown to the text format, are parsed as gauges.
+func Parse(r io.Reader) (map[string]*dto.MetricFamily, error) {
+	buf := &bytes.Buffer{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# TYPE ") {
+			for _, t := range []metric.Type{metric.Info, metric.StateSet} {
+				if strings.HasSuffix(line, " "+string(t)) {
+					line = strings.TrimSuffix(line, string(t)) + string(metric.Gauge)
+				}
+			}
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	parser := &expfmt.TextParser{}
+	return parser.TextToMetricFamilies(buf)
+}
+
+// Compare compares exposition a with exposition b. Values of series present in
+// both are reported if they differ by more than threshold relative to their
+// value in a.
+func Compare(a, b map[string]*dto.MetricFamily, threshold float64) *Report {
+	r := &Report{
+		AddedSeries:   map[string]int{},
+		RemovedSeries: map[string]int{},
+	}
+
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			r.AddedFamilies = append(r.AddedFamilies, name)
+		}
+	}
+	for name, fa := range a {
+		fb, ok := b[name]
+		if !ok {
+			r.RemovedFamilies = append(r.RemovedFamilies, name)
+			continue
+		}
+
+		labelsA, labelsB := labelNames(fa), labelNames(fb)
+		if change := (LabelChange{Family: name, Added: difference(labelsB, labelsA), Removed: difference(labelsA, labelsB)}); len(change.Added) > 0 || len(change.Removed) > 0 {
+			r.LabelChanges = append(r.LabelChanges, change)
+		}
+
+		seriesA, seriesB := seriesValues(fa), seriesValues(fb)
+		for s, va := range seriesA {
+			vb, ok := seriesB[s]
+			if !ok {
+				r.RemovedSeries[name]++
+				continue
+			}
+			if exceeds(va, vb, threshold) {
+				r.ValueChanges = append(r.ValueChanges, ValueChange{Series: s, A: va, B: vb})
+			}
+		}
+		for s := range seriesB {
+			if _, ok := seriesA[s]; !ok {
+				r.AddedSeries[name]++
+			}
+		}
+	}
+
+	sort.Strings(r.AddedFamilies)
+	sort.Strings(r.RemovedFamilies)
+	sort.Slice(r.LabelChanges, func(i, j int) bool { return r.LabelChanges[i].Family < r.LabelChanges[j].Family })
+	sort.Slice(r.ValueChanges, func(i, j int) bool { return r.ValueChanges[i].Series < r.ValueChanges[j].Series })
+
+	return r
+}
+
+// Write writes a human readable form of the report to w.
+func (r *Report) Write(w io.Writer) error {
+	var b strings.Builder
+	for _, f := range r.AddedFamilies {
+		fmt.Fprintf(&b, "+ family %s\n", f)
+	}
+	for _, f := range r.RemovedFamilies {
+		fmt.Fprintf(&b, "- family %s\n", f)
+	}
+	for _, c := range r.LabelChanges {
+		for _, l := range c.Added {
+			fmt.Fprintf(&b, "+ label %s{%s}\n", c.Family, l)
+		}
+		for _, l := range c.Removed {
+			fmt.Fprintf(&b, "- label %s{%s}\n", c.Family, l)
+		}
+	}
+	for _, f := range sortedKeys(r.AddedSeries) {
+		fmt.Fprintf(&b, "+ %d series of %s\n", r.AddedSeries[f], f)
+	}
+	for _, f := range sortedKeys(r.RemovedSeries) {
+		fmt.Fprintf(&b, "- %d series of %s\n", r.RemovedSeries[f], f)
+	}
+	for _, c := range r.ValueChanges {
+		fmt.Fprintf(&b, "~ %s %v -> %v\n", c.Series, c.A, c.B)
+	}
+	if r.Empty() {
+		b.WriteString("no differences found\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func exceeds(a, b, threshold float64) bool {
+	if a == b || (math.IsNaN(a) && math.IsNaN(b)) {
+		return false
+	}
+	if a == 0 {
+		return true
+	}
+	return math.Abs(b-a)/math.Abs(a) > threshold
+}
+
+func labelNames(f *dto.MetricFamily) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, m := range f.GetMetric() {
+		for _, l := range m.GetLabel() {
+			names[l.GetName()] = struct{}{}
+		}
+	}
+	return names
+}
+
+func seriesValues(f *dto.MetricFamily) map[string]float64 {
+	values := make(map[string]float64, len(f.GetMetric()))
+	for _, m := range f.GetMetric() {
+		labels := make([]string, 0, len(m.GetLabel()))
+		for _, l := range m.GetLabel() {
+			labels = append(labels, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+		}
+		sort.Strings(labels)
+		series := f.GetName() + "{" + strings.Join(labels, ",") + "}"
+
+		switch {
+		case m.GetGauge() != nil:
+			values[series] = m.GetGauge().GetValue()
+		case m.GetCounter() != nil:
+			values[series] = m.GetCounter().GetValue()
+		case m.GetUntyped() != nil:
+			values[series] = m.GetUntyped().GetValue()
+		}
+	}
+	return values
+}
+
+func difference(a, b map[string]struct{}) []string {
+	var res []string
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			res = append(res, k)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	a, err := Parse(strings.NewReader(`# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info gauge
+kube_pod_info{namespace="ns1",pod="pod1"} 1
+kube_pod_info{namespace="ns1",pod="pod2"} 1
+# HELP kube_pod_container_restarts_total The number of container restarts per container.
+# TYPE kube_pod_container_restarts_total counter
+kube_pod_container_restarts_total{container="c1",namespace="ns1",pod="pod1"} 10
+# HELP kube_pod_removed Removed family.
+# TYPE kube_pod_removed gauge
+kube_pod_removed{namespace="ns1",pod="pod1"} 1
+# HELP kube_customresource_info Information about a custom resource.
+# TYPE kube_customresource_info info
+kube_customresource_info{name="foo"} 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Parse(strings.NewReader(`# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info gauge
+kube_pod_info{namespace="ns1",node="node1",pod="pod1"} 1
+# HELP kube_pod_container_restarts_total The number of container restarts per container.
+# TYPE kube_pod_container_restarts_total counter
+kube_pod_container_restarts_total{container="c1",namespace="ns1",pod="pod1"} 12
+# HELP kube_pod_added Added family.
+# TYPE kube_pod_added gauge
+kube_pod_added{namespace="ns1",pod="pod1"} 1
+# HELP kube_customresource_info Information about a custom resource.
+# TYPE kube_customresource_info info
+kube_customresource_info{name="foo"} 1
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := strings.Builder{}
+	if err := Compare(a, b, 0.1).Write(&w); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `+ family kube_pod_added
+- family kube_pod_removed
++ label kube_pod_info{node}
++ 1 series of kube_pod_info
+- 2 series of kube_pod_info
+~ kube_pod_container_restarts_total{container="c1",namespace="ns1",pod="pod1"} 10 -> 12
+`
+	if diff := cmp.Diff(expected, w.String()); diff != "" {
+		t.Fatalf("unexpected report (-want, +got):\n%s", diff)
+	}
+
+	if report := Compare(a, b, 0.5); len(report.ValueChanges) != 0 {
+		t.Fatalf("expected no value changes below the threshold, got %v", report.ValueChanges)
+	}
+	if report := Compare(a, a, 0); !report.Empty() {
+		t.Fatalf("expected no differences when comparing an exposition with itself, got %+v", report)
+	}
+}
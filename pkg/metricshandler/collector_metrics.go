@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"io"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// collectorMetrics track how long each collector takes to write out its
+// metrics, how many bytes it contributes to a response and how many duplicate
+// series of it were dropped.
+type collectorMetrics struct {
+	duration   *prometheus.HistogramVec
+	bytes      *prometheus.GaugeVec
+	duplicates *prometheus.CounterVec
+}
+
+func newCollectorMetrics(r prometheus.Registerer) *collectorMetrics {
+	return &collectorMetrics{
+		duration: promauto.With(r).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kube_state_metrics_collector_generate_duration_seconds",
+				Help:    "Time it took a collector to write out its metrics for a metrics request.",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"collector"},
+		),
+		bytes: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_collector_bytes",
+				Help: "Number of uncompressed bytes a collector contributed to the last rendered metrics response.",
+			},
+			[]string{"collector"},
+		),
+		duplicates: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_duplicate_series_total",
+				Help: "Number of duplicate series of a collector which were dropped from metrics responses, see --validate-output.",
+			},
+			[]string{"collector"},
+		),
+	}
+}
+
+func (c *collectorMetrics) observe(collector string, duration time.Duration, bytes int64) {
+	c.duration.WithLabelValues(collector).Observe(duration.Seconds())
+	c.bytes.WithLabelValues(collector).Set(float64(bytes))
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
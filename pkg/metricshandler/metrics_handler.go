@@ -17,21 +17,29 @@ limitations under the License.
 package metricshandler
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/expfmt"
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -56,6 +64,10 @@ type MetricsHandler struct {
 	curTotalShards     int
 	curShard           int32
 	enableGZIPEncoding bool
+	// responseCache is nil if responses aren't cached.
+	responseCache *responseCache
+	// collectorMetrics is nil if collectors aren't instrumented.
+	collectorMetrics *collectorMetrics
 }
 
 // New creates and returns a new MetricsHandler with the given options.
@@ -69,6 +81,20 @@ func New(opts *options.Options, kubeClient kubernetes.Interface, storeBuilder ks
 	}
 }
 
+// EnableResponseCache caches the rendered responses for the given TTL, with
+// the hit and miss counts of the cache registered in the given registry. It has
+// to be called before the MetricsHandler serves requests.
+func (m *MetricsHandler) EnableResponseCache(ttl time.Duration, r prometheus.Registerer) {
+	m.responseCache = newResponseCache(ttl, r)
+}
+
+// EnableCollectorMetrics registers the write duration and response size of
+// each collector in the given registry. It has to be called before the
+// MetricsHandler serves requests.
+func (m *MetricsHandler) EnableCollectorMetrics(r prometheus.Registerer) {
+	m.collectorMetrics = newCollectorMetrics(r)
+}
+
 // ConfigureSharding (re-)configures sharding. Re-configuration can be done
 // concurrently.
 func (m *MetricsHandler) ConfigureSharding(ctx context.Context, shard int32, totalShards int) {
@@ -85,6 +111,9 @@ func (m *MetricsHandler) ConfigureSharding(ctx context.Context, shard int32, tot
 	m.storeBuilder.WithSharding(shard, totalShards)
 	m.storeBuilder.WithContext(ctx)
 	m.metricsWriters = m.storeBuilder.Build()
+	if m.responseCache != nil {
+		m.responseCache.reset()
+	}
 	m.curShard = shard
 	m.curTotalShards = totalShards
 }
@@ -178,9 +207,83 @@ func (m *MetricsHandler) Run(ctx context.Context) error {
 	return ctx.Err()
 }
 
+// negotiateEncoding returns the content encoding of the response for the
+// given Accept-Encoding header, preferring zstd over gzip, or an empty string
+// if the response isn't compressed. Encodings with a quality of zero are not
+// acceptable.
+func negotiateEncoding(acceptEncoding string, gzipEnabled, zstdEnabled bool) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := strings.ReplaceAll(strings.TrimSpace(params), " ", "")
+		accepted[strings.TrimSpace(encoding)] = q != "q=0" && q != "q=0.0" && q != "q=0.00" && q != "q=0.000"
+	}
+
+	switch {
+	case zstdEnabled && accepted["zstd"]:
+		return "zstd"
+	case gzipEnabled && accepted["gzip"]:
+		return "gzip"
+	default:
+		return ""
+	}
+}
+
 // ServeHTTP implements the http.Handler interface. It writes all generated metrics to the response body.
 // Note that all operations defined within this procedure are performed at every request.
 func (m *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.serve(w, r, "", "")
+}
+
+// ServeNamespace writes the metrics of the objects of the namespace given by
+// the namespace path value of the request to the response body, like
+// ServeHTTP. The metrics of cluster-scoped objects are not written.
+func (m *MetricsHandler) ServeNamespace(w http.ResponseWriter, r *http.Request) {
+	namespace := r.PathValue("namespace")
+	if namespace == "" {
+		http.Error(w, "namespace is required", http.StatusBadRequest)
+		return
+	}
+	m.serve(w, r, namespace, "")
+}
+
+// ServeCollector writes the metrics of the collector given by the collector
+// path value of the request, e.g. pods, to the response body, like ServeHTTP.
+func (m *MetricsHandler) ServeCollector(w http.ResponseWriter, r *http.Request) {
+	collector := r.PathValue("collector")
+	if !m.hasCollector(collector) {
+		http.Error(w, fmt.Sprintf("collector %q is not enabled", collector), http.StatusNotFound)
+		return
+	}
+	m.serve(w, r, "", collector)
+}
+
+// hasCollector returns whether the given collector is enabled.
+func (m *MetricsHandler) hasCollector(collector string) bool {
+	if collector == "" {
+		return false
+	}
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	for _, w := range m.metricsWriters {
+		if w.Resource() == collector {
+			return true
+		}
+	}
+	return false
+}
+
+// serve writes the metrics of the objects of the given namespace, or of all
+// objects if it is empty, to the response body. Only the metrics of the given
+// collector are written unless it is empty.
+func (m *MetricsHandler) serve(w http.ResponseWriter, r *http.Request, namespace, collector string) {
+	include, err := familyFilterFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	m.mtx.RLock()
 	defer m.mtx.RUnlock()
 	resHeader := w.Header()
@@ -195,26 +298,72 @@ func (m *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	resHeader.Set("Content-Type", string(contentType))
 
-	if m.enableGZIPEncoding {
-		// Gzip response if requested. Taken from
-		// github.com/prometheus/client_golang/prometheus/promhttp.decorateWriter.
-		reqHeader := r.Header.Get("Accept-Encoding")
-		parts := strings.Split(reqHeader, ",")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			if part == "gzip" || strings.HasPrefix(part, "gzip;") {
-				writer = gzip.NewWriter(writer)
-				resHeader.Set("Content-Encoding", "gzip")
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), m.enableGZIPEncoding, m.opts.EnableZstdEncoding)
+	if encoding != "" {
+		resHeader.Set("Content-Encoding", encoding)
+	}
+
+	// Responses only differ by their format, encoding, path and query.
+	cacheKey := string(contentType) + "\x00" + encoding + "\x00" + r.URL.Path + "\x00" + r.URL.RawQuery
+	var buf *bytes.Buffer
+	if m.responseCache != nil {
+		if body, ok := m.responseCache.get(cacheKey); ok {
+			if _, err := w.Write(body); err != nil {
+				klog.ErrorS(err, "Failed to write cached response")
 			}
+			return
 		}
+		// Render the response into a buffer in order to cache it.
+		buf = &bytes.Buffer{}
+		writer = buf
+	}
+
+	switch encoding {
+	case "zstd":
+		// The options are valid, so creating the writer doesn't fail.
+		writer, _ = zstd.NewWriter(writer)
+	case "gzip":
+		writer = gzip.NewWriter(writer)
 	}
 
 	m.metricsWriters = metricsstore.SanitizeHeaders(string(contentType), m.metricsWriters)
+	var validator *metricsstore.SeriesValidator
+	if m.opts.ValidateOutput {
+		validator = metricsstore.NewSeriesValidator()
+	}
 	for _, w := range m.metricsWriters {
-		err := w.WriteAll(writer)
-		if err != nil {
+		if collector != "" && w.Resource() != collector {
+			continue
+		}
+		if validator != nil {
+			collector := w.Resource()
+			validator.OnDuplicate = func(series string, uid types.UID) {
+				klog.ErrorS(nil, "Dropped duplicate series", "collector", collector, "series", series, "uid", uid)
+				if m.collectorMetrics != nil {
+					m.collectorMetrics.duplicates.WithLabelValues(collector).Inc()
+				}
+			}
+		}
+
+		if namespace != "" {
+			if err := w.WriteNamespaceFamilies(writer, namespace, include, validator); err != nil {
+				klog.ErrorS(err, "Failed to write metrics")
+			}
+			continue
+		}
+		if m.collectorMetrics == nil || w.Resource() == "" {
+			if err := w.WriteValidatedFamilies(writer, include, validator); err != nil {
+				klog.ErrorS(err, "Failed to write metrics")
+			}
+			continue
+		}
+
+		start := time.Now()
+		cw := &countingWriter{w: writer}
+		if err := w.WriteValidatedFamilies(cw, include, validator); err != nil {
 			klog.ErrorS(err, "Failed to write metrics")
 		}
+		m.collectorMetrics.observe(w.Resource(), time.Since(start), cw.n)
 	}
 
 	// OpenMetrics spec requires that we end with an EOF directive.
@@ -225,13 +374,89 @@ func (m *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// In case we gzipped the response, we have to close the writer.
+	// In case we compressed the response, we have to close the writer.
 	if closer, ok := writer.(io.Closer); ok {
 		err := closer.Close()
 		if err != nil {
 			klog.ErrorS(err, "Failed to close the writer")
 		}
 	}
+
+	if buf != nil {
+		m.responseCache.set(cacheKey, buf.Bytes())
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			klog.ErrorS(err, "Failed to write response")
+		}
+	}
+}
+
+// WriteAll writes the metrics of all stores to w in the text format.
+func (m *MetricsHandler) WriteAll(w io.Writer) error {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	for _, mw := range metricsstore.SanitizeHeaders(string(expfmt.NewFormat(expfmt.TypeTextPlain)), m.metricsWriters) {
+		if err := mw.WriteAll(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// familyFilterFromQuery returns a filter on metric family names for the
+// include and exclude query parameters of a request, whose values are
+// comma-separated lists of regular expressions matching the whole family name.
+// They may also be given as include[] and exclude[], whose values are a single
+// regular expression each, which may contain commas. It returns nil if neither
+// of them is set.
+func familyFilterFromQuery(query url.Values) (func(name string) bool, error) {
+	include, err := queryNameRegexp(slices.Concat(splitQueryValues(query["include"]), query["include[]"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid include query parameter: %w", err)
+	}
+	exclude, err := queryNameRegexp(slices.Concat(splitQueryValues(query["exclude"]), query["exclude[]"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude query parameter: %w", err)
+	}
+	if include == nil && exclude == nil {
+		return nil, nil
+	}
+
+	return func(name string) bool {
+		if exclude != nil && exclude.MatchString(name) {
+			return false
+		}
+		return include == nil || include.MatchString(name)
+	}, nil
+}
+
+// splitQueryValues splits the given comma-separated query values.
+func splitQueryValues(values []string) []string {
+	var split []string
+	for _, value := range values {
+		split = append(split, strings.Split(value, ",")...)
+	}
+	return split
+}
+
+// queryNameRegexp returns a regular expression matching the names matched by
+// any of the given regular expressions, or nil if there are none. Each of them
+// has to be valid on its own.
+func queryNameRegexp(exprs []string) (*regexp.Regexp, error) {
+	var alternatives []string
+	for _, expr := range exprs {
+		if expr = strings.TrimSpace(expr); expr == "" {
+			continue
+		}
+		if _, err := regexp.Compile(expr); err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, "(?:"+expr+")")
+	}
+	if len(alternatives) == 0 {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
 }
 
 func shardingSettingsFromStatefulSet(ss *appsv1.StatefulSet, podName string) (nominal int32, totalReplicas int, err error) {
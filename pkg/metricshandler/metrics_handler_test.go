@@ -0,0 +1,221 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metric"
+	metricsstore "k8s.io/kube-state-metrics/v2/pkg/metrics_store"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		gzip           bool
+		zstd           bool
+		want           string
+	}{
+		{acceptEncoding: "", gzip: true, zstd: true, want: ""},
+		{acceptEncoding: "gzip", gzip: false, zstd: true, want: ""},
+		{acceptEncoding: "gzip", gzip: true, zstd: true, want: "gzip"},
+		{acceptEncoding: "gzip, zstd", gzip: true, zstd: true, want: "zstd"},
+		{acceptEncoding: "gzip, zstd", gzip: true, zstd: false, want: "gzip"},
+		{acceptEncoding: "zstd;q=0, gzip;q=0.5", gzip: true, zstd: true, want: "gzip"},
+		{acceptEncoding: "zstd; q=0.8", gzip: true, zstd: true, want: "zstd"},
+		{acceptEncoding: "deflate, br", gzip: true, zstd: true, want: ""},
+	}
+	for _, test := range tests {
+		if got := negotiateEncoding(test.acceptEncoding, test.gzip, test.zstd); got != test.want {
+			t.Errorf("%q (gzip %t, zstd %t): expected %q, got %q", test.acceptEncoding, test.gzip, test.zstd, test.want, got)
+		}
+	}
+}
+
+func newServiceMetricsStore(t *testing.T) *metricsstore.MetricsStore {
+	t.Helper()
+	genFunc := func(obj interface{}) []metric.FamilyInterface {
+		o, err := meta.Accessor(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []metric.FamilyInterface{&metric.Family{
+			Name: "kube_service_info",
+			Metrics: []*metric.Metric{
+				{
+					LabelKeys:   []string{"namespace", "service"},
+					LabelValues: []string{o.GetNamespace(), o.GetName()},
+					Value:       1,
+				},
+			},
+		}}
+	}
+	store := metricsstore.NewMetricsStore([]string{"# HELP kube_service_info Information about service.\n# TYPE kube_service_info gauge"}, genFunc)
+	if err := store.Add(&v1.Service{ObjectMeta: metav1.ObjectMeta{UID: "a1", Name: "svc1", Namespace: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestResponseCache(t *testing.T) {
+	store := newServiceMetricsStore(t)
+
+	reg := prometheus.NewRegistry()
+	m := New(&options.Options{}, nil, nil, false)
+	m.EnableResponseCache(time.Minute, reg)
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewMetricsWriter(store)}
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:8080/metrics", nil))
+		return w.Body.String()
+	}
+
+	first := scrape()
+	if !strings.Contains(first, `kube_service_info{namespace="a",service="svc1"} 1`) {
+		t.Fatalf("unexpected response:\n%s", first)
+	}
+	if err := store.Add(&v1.Service{ObjectMeta: metav1.ObjectMeta{UID: "a2", Name: "svc2", Namespace: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := scrape(); got != first {
+		t.Fatalf("expected cached response:\n%s\ngot:\n%s", first, got)
+	}
+
+	m.responseCache.reset()
+	if got := scrape(); !strings.Contains(got, `service="svc2"`) {
+		t.Fatalf("expected rendered response after reset, got:\n%s", got)
+	}
+
+	want := `
+		# HELP kube_state_metrics_response_cache_requests_total Number of metrics requests served from the response cache (hit) or rendered (miss).
+		# TYPE kube_state_metrics_response_cache_requests_total counter
+		kube_state_metrics_response_cache_requests_total{result="hit"} 1
+		kube_state_metrics_response_cache_requests_total{result="miss"} 2
+	`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollectorMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(&options.Options{}, nil, nil, false)
+	m.EnableCollectorMetrics(reg)
+	m.metricsWriters = metricsstore.MetricsWriterList{metricsstore.NewResourceMetricsWriter("services", newServiceMetricsStore(t))}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:8080/metrics", nil))
+
+	if got, want := testutil.ToFloat64(m.collectorMetrics.bytes.WithLabelValues("services")), float64(w.Body.Len()); got != want {
+		t.Errorf("expected %v bytes for the services collector, got %v", want, got)
+	}
+	if got := testutil.CollectAndCount(m.collectorMetrics.duration, "kube_state_metrics_collector_generate_duration_seconds"); got != 1 {
+		t.Errorf("expected a duration histogram for the services collector, got %d series", got)
+	}
+}
+
+func TestServeCollector(t *testing.T) {
+	m := New(&options.Options{}, nil, nil, false)
+	m.metricsWriters = metricsstore.MetricsWriterList{
+		metricsstore.NewResourceMetricsWriter("services", newServiceMetricsStore(t)),
+		metricsstore.NewResourceMetricsWriter("endpoints", metricsstore.NewMetricsStore(
+			[]string{"# HELP kube_endpoint_info Information about endpoint.\n# TYPE kube_endpoint_info gauge"},
+			func(interface{}) []metric.FamilyInterface { return nil },
+		)),
+	}
+
+	scrape := func(collector string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "http://localhost:8080/metrics/"+collector, nil)
+		r.SetPathValue("collector", collector)
+		w := httptest.NewRecorder()
+		m.ServeCollector(w, r)
+		return w
+	}
+
+	w := scrape("services")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `kube_service_info{namespace="a",service="svc1"} 1`) {
+		t.Fatalf("unexpected response for the services collector (%d):\n%s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "kube_endpoint_info") {
+		t.Fatalf("expected only the metrics of the services collector, got:\n%s", w.Body.String())
+	}
+
+	if w := scrape("pods"); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a disabled collector, got %d", w.Code)
+	}
+}
+
+func TestFamilyFilterFromQuery(t *testing.T) {
+	names := []string{"kube_pod_info", "kube_pod_container_info", "kube_pod_container_status_ready", "kube_node_info"}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: names},
+		{query: "include=kube_pod_info,kube_node_info", want: []string{"kube_pod_info", "kube_node_info"}},
+		{query: "include[]=kube_pod_.*&exclude[]=kube_pod_container_.*", want: []string{"kube_pod_info"}},
+		{query: "exclude=kube_pod_container_status_ready&exclude[]=kube_node_.*", want: []string{"kube_pod_info", "kube_pod_container_info"}},
+		{query: "include=kube_pod", want: nil},
+		{query: "include[]=kube_node_.{1,4}&include[]=kube_pod_.{1,4}", want: []string{"kube_pod_info", "kube_node_info"}},
+		{query: "include=kube_node_.{1,4}", want: nil},
+	}
+
+	for _, test := range tests {
+		query, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		include, err := familyFilterFromQuery(query)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", test.query, err)
+		}
+
+		var got []string
+		for _, name := range names {
+			if include == nil || include(name) {
+				got = append(got, name)
+			}
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("unexpected families for %q (-want, +got):\n%s", test.query, diff)
+		}
+	}
+
+	for _, query := range []url.Values{
+		{"include[]": {"kube_pod_("}},
+		{"exclude[]": {"kube_pod_info)|(kube_node_info"}},
+	} {
+		if _, err := familyFilterFromQuery(query); err == nil {
+			t.Errorf("expected an error for the invalid regular expression of %v", query)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshandler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// responseCache holds rendered responses of the metrics handler for a TTL, so
+// scrapes of several Prometheus replicas within the TTL are served the same
+// payload instead of rendering it for each of them.
+type responseCache struct {
+	ttl      time.Duration
+	requests *prometheus.CounterVec
+
+	// mtx protects entries
+	mtx     sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	body    []byte
+	expires time.Time
+}
+
+func newResponseCache(ttl time.Duration, r prometheus.Registerer) *responseCache {
+	return &responseCache{
+		ttl: ttl,
+		requests: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kube_state_metrics_response_cache_requests_total",
+				Help: "Number of metrics requests served from the response cache (hit) or rendered (miss).",
+			},
+			[]string{"result"},
+		),
+		entries: make(map[string]cachedResponse),
+	}
+}
+
+// get returns the cached body of the given key if it didn't expire yet.
+func (c *responseCache) get(key string) ([]byte, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		c.requests.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+	c.requests.WithLabelValues("hit").Inc()
+	return e.body, true
+}
+
+// set caches the given body under the given key for the TTL of the cache.
+func (c *responseCache) set(key string, body []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cachedResponse{body: body, expires: now.Add(c.ttl)}
+}
+
+// reset drops all cached responses.
+func (c *responseCache) reset() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.entries = make(map[string]cachedResponse)
+}
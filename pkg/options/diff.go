@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metricsdiff"
+)
+
+// newDiffCommand returns the command comparing the metrics of two
+// kube-state-metrics endpoints.
+func newDiffCommand() *cobra.Command {
+	var a, b string
+	var threshold float64
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the metrics of two kube-state-metrics endpoints.",
+		Long:  "Compare the metrics of two kube-state-metrics endpoints, e.g. before and after an upgrade. Added and removed metric families, label names and series are reported, as well as values which changed by more than --threshold. Exits with 1 if any difference was found.",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			if a == "" || b == "" {
+				klog.ErrorS(errors.New("--a and --b are required"), "Invalid arguments")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 2)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			client := &http.Client{}
+
+			familiesA, err := metricsdiff.Fetch(ctx, client, a)
+			if err != nil {
+				klog.ErrorS(err, "Failed to fetch metrics", "endpoint", a)
+				klog.FlushAndExit(klog.ExitFlushTimeout, 2)
+			}
+			familiesB, err := metricsdiff.Fetch(ctx, client, b)
+			if err != nil {
+				klog.ErrorS(err, "Failed to fetch metrics", "endpoint", b)
+				klog.FlushAndExit(klog.ExitFlushTimeout, 2)
+			}
+
+			report := metricsdiff.Compare(familiesA, familiesB, threshold)
+			if err := report.Write(os.Stdout); err != nil {
+				klog.ErrorS(err, "Failed to write report")
+				klog.FlushAndExit(klog.ExitFlushTimeout, 2)
+			}
+			if !report.Empty() {
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+		},
+	}
+
+	cmd.Flags().StringVar(&a, "a", "", "URL of the metrics endpoint to compare against, e.g. http://old:8080/metrics.")
+	cmd.Flags().StringVar(&b, "b", "", "URL of the metrics endpoint to compare, e.g. http://new:8080/metrics.")
+	cmd.Flags().Float64Var(&threshold, "threshold", 0, "Relative change of a value above which it is reported, e.g. 0.1 for 10%.")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Minute, "Timeout for scraping both endpoints.")
+
+	return cmd
+}
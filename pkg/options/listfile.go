@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// listFilePrefix marks the value of a list flag as the path of a file holding
+// the list, e.g. --namespaces=@/etc/kube-state-metrics/namespaces.
+const listFilePrefix = "@"
+
+// flagValue is the interface of the values of the list flags.
+type flagValue interface {
+	String() string
+	Set(string) error
+	Type() string
+}
+
+// listFileValue is a list flag which additionally accepts @/path/to/file, in
+// which case the list is read from the file.
+type listFileValue struct {
+	flagValue
+	reset func()
+	o     *Options
+}
+
+// listFile returns the value of a list flag which reads @/path/to/file values
+// from the file. reset empties the list before it is read again from the file
+// when the file changed.
+func (o *Options) listFile(v flagValue, reset func()) *listFileValue {
+	return &listFileValue{flagValue: v, reset: reset, o: o}
+}
+
+// Set reads the list from the file if the value has the @ prefix and sets it.
+func (v *listFileValue) Set(value string) error {
+	file, ok := strings.CutPrefix(value, listFilePrefix)
+	if !ok {
+		return v.flagValue.Set(value)
+	}
+
+	list, err := readListFile(file)
+	if err != nil {
+		return err
+	}
+	if v.o.listFiles == nil {
+		v.o.listFiles = map[*listFileValue]string{}
+	}
+	v.o.listFiles[v] = filepath.Clean(file)
+	return v.flagValue.Set(list)
+}
+
+// readListFile reads a list from the given file. Entries are separated by
+// commas or new lines, empty lines and lines starting with # are ignored.
+func readListFile(file string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return "", fmt.Errorf("failed to read list file: %w", err)
+	}
+
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries = append(entries, strings.TrimSuffix(line, ","))
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read list file %s: %w", file, err)
+	}
+	return strings.Join(entries, ","), nil
+}
+
+// ListFiles returns the files the list flags were read from.
+func (o *Options) ListFiles() []string {
+	seen := map[string]struct{}{}
+	files := []string{}
+	for _, file := range o.listFiles {
+		if _, ok := seen[file]; ok {
+			continue
+		}
+		seen[file] = struct{}{}
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// ReloadListFiles reads the list flags which were given as @/path/to/file
+// from their files again. The flags are left unchanged if any of the files
+// can't be read.
+func (o *Options) ReloadListFiles() error {
+	lists := make(map[*listFileValue]string, len(o.listFiles))
+	for v, file := range o.listFiles {
+		list, err := readListFile(file)
+		if err != nil {
+			return err
+		}
+		lists[v] = list
+	}
+
+	for v, list := range lists {
+		v.reset()
+		if err := v.flagValue.Set(list); err != nil {
+			return fmt.Errorf("invalid list in %s: %w", o.listFiles[v], err)
+		}
+	}
+	return nil
+}
+
+// WatchListFiles polls the given files every interval and calls onChange when
+// the content of any of them changed, until the context is done. Files which
+// are mounted from a ConfigMap are replaced through a symlink, so their
+// content is compared rather than their modification time.
+func WatchListFiles(ctx context.Context, files []string, interval time.Duration, onChange func()) {
+	hashes := make(map[string][sha256.Size]byte, len(files))
+	for _, file := range files {
+		if data, err := os.ReadFile(filepath.Clean(file)); err == nil {
+			hashes[file] = sha256.Sum256(data)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed := false
+		for _, file := range files {
+			data, err := os.ReadFile(filepath.Clean(file))
+			if err != nil {
+				klog.ErrorS(err, "Failed to read list file", "file", file)
+				continue
+			}
+			if hash := sha256.Sum256(data); hash != hashes[file] {
+				hashes[file] = hash
+				changed = true
+			}
+		}
+		if changed {
+			onChange()
+		}
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestListFiles(t *testing.T) {
+	dir := t.TempDir()
+	namespaces := filepath.Join(dir, "namespaces")
+	denylist := filepath.Join(dir, "denylist")
+	writeFile(t, namespaces, "# tenants\ntenant-a\ntenant-b,\n\ntenant-c,tenant-d\n")
+	writeFile(t, denylist, "kube_pod_info\n")
+
+	opts := NewOptions()
+	opts.AddFlags(&cobra.Command{Use: "kube-state-metrics"})
+	args := []string{"--namespaces=@" + namespaces, "--metric-denylist=@" + denylist, "--resources=pods"}
+	if err := opts.cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (NamespaceList{"tenant-a", "tenant-b", "tenant-c", "tenant-d"}); !reflect.DeepEqual(opts.Namespaces, expected) {
+		t.Fatalf("expected namespaces %v, got %v", expected, opts.Namespaces)
+	}
+	if expected := (MetricSet{"kube_pod_info": {}}); !reflect.DeepEqual(opts.MetricDenylist, expected) {
+		t.Fatalf("expected metric denylist %v, got %v", expected, opts.MetricDenylist)
+	}
+	if expected := []string{denylist, namespaces}; !reflect.DeepEqual(opts.ListFiles(), expected) {
+		t.Fatalf("expected list files %v, got %v", expected, opts.ListFiles())
+	}
+
+	// Reloading replaces the lists instead of appending to them.
+	writeFile(t, namespaces, "tenant-e\n")
+	if err := opts.ReloadListFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := (NamespaceList{"tenant-e"}); !reflect.DeepEqual(opts.Namespaces, expected) {
+		t.Fatalf("expected namespaces %v after reload, got %v", expected, opts.Namespaces)
+	}
+	if expected := (ResourceSet{"pods": {}}); !reflect.DeepEqual(opts.Resources, expected) {
+		t.Fatalf("expected resources given on the command line to be kept, got %v", opts.Resources)
+	}
+
+	// The lists are kept if a file can't be read.
+	if err := os.Remove(denylist); err != nil {
+		t.Fatal(err)
+	}
+	if err := opts.ReloadListFiles(); err == nil {
+		t.Fatal("expected an error reloading a missing list file")
+	}
+	if expected := (NamespaceList{"tenant-e"}); !reflect.DeepEqual(opts.Namespaces, expected) {
+		t.Fatalf("expected namespaces %v to be kept, got %v", expected, opts.Namespaces)
+	}
+
+	if err := opts.cmd.Flags().Set("namespaces-denylist", "@"+filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected an error for a missing list file")
+	}
+}
+
+func TestWatchListFiles(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "namespaces")
+	writeFile(t, file, "tenant-a\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 1)
+	go WatchListFiles(ctx, []string{file}, 10*time.Millisecond, func() {
+		changes <- struct{}{}
+	})
+
+	select {
+	case <-changes:
+		t.Fatal("expected no change before the file was written")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeFile(t, file, "tenant-b\n")
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change after the file was written")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
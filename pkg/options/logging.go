@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"k8s.io/klog/v2"
+)
+
+// The formats of the logs supported by --log-format.
+const (
+	LogFormatKlog = "klog"
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// debugVerbosity is the klog verbosity enabled by --log-level=debug.
+const debugVerbosity = 4
+
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// SetupLogging sets up klog according to --log-format and --log-level. The
+// text and json formats write the logs with a log/slog handler.
+func (o *Options) SetupLogging() error {
+	handler, err := newLogHandler(o.LogFormat, o.LogLevel, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	if o.LogLevel == "debug" && o.cmd != nil {
+		if f := o.cmd.Flags().Lookup("v"); f != nil && !f.Changed {
+			if err := f.Value.Set(strconv.Itoa(debugVerbosity)); err != nil {
+				return fmt.Errorf("failed to set the log verbosity: %v", err)
+			}
+		}
+	}
+	if handler != nil {
+		klog.SetSlogLogger(slog.New(handler))
+	}
+	return nil
+}
+
+// newLogHandler returns the log/slog handler writing the logs of the given
+// format and minimum level to w. It returns nil for the klog format, whose
+// logs are written by klog itself.
+func newLogHandler(format, level string, w io.Writer) (slog.Handler, error) {
+	l := slog.LevelInfo
+	if level != "" {
+		var ok bool
+		if l, ok = logLevels[level]; !ok {
+			return nil, fmt.Errorf("invalid --log-level %q, must be one of debug, info, warn or error", level)
+		}
+	}
+
+	switch format {
+	case "", LogFormatKlog:
+		if l > slog.LevelInfo {
+			return nil, fmt.Errorf("--log-level %s requires --log-format %s or %s", level, LogFormatText, LogFormatJSON)
+		}
+		return nil, nil
+	case LogFormatText:
+		return slog.NewTextHandler(w, &slog.HandlerOptions{Level: l}), nil
+	case LogFormatJSON:
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: l}), nil
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q, must be one of %s, %s or %s", format, LogFormatKlog, LogFormatText, LogFormatJSON)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestNewLogHandler(t *testing.T) {
+	tests := []struct {
+		format  string
+		level   string
+		klog    bool
+		wantErr bool
+	}{
+		{format: "", level: "", klog: true},
+		{format: LogFormatKlog, level: "debug", klog: true},
+		{format: LogFormatKlog, level: "error", wantErr: true},
+		{format: LogFormatText, level: "warn"},
+		{format: LogFormatJSON, level: ""},
+		{format: LogFormatJSON, level: "trace", wantErr: true},
+		{format: "logfmt", level: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		h, err := newLogHandler(test.format, test.level, &bytes.Buffer{})
+		if (err != nil) != test.wantErr {
+			t.Errorf("format %q, level %q: expected error %v, got %v", test.format, test.level, test.wantErr, err)
+			continue
+		}
+		if err == nil && (h == nil) != test.klog {
+			t.Errorf("format %q, level %q: expected a slog handler %v, got %v", test.format, test.level, !test.klog, h)
+		}
+	}
+}
+
+func TestNewLogHandlerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := newLogHandler(LogFormatJSON, "warn", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := slog.New(h)
+
+	logger.Info("dropped")
+	if h.Enabled(context.Background(), slog.LevelInfo) || buf.Len() != 0 {
+		t.Fatalf("expected info logs to be dropped with --log-level warn, got %q", buf.String())
+	}
+
+	logger.Warn("written", "resource", "*v1.Pod")
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a JSON log entry, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "written" || entry["resource"] != "*v1.Pod" {
+		t.Fatalf("unexpected log entry %v", entry)
+	}
+}
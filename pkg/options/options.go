@@ -40,30 +40,62 @@ var (
 
 // Options are the configurable parameters for kube-state-metrics.
 type Options struct {
-	AnnotationsAllowList LabelsAllowList `yaml:"annotations_allow_list"`
-	LabelsAllowList      LabelsAllowList `yaml:"labels_allow_list"`
-	MetricAllowlist      MetricSet       `yaml:"metric_allowlist"`
-	MetricDenylist       MetricSet       `yaml:"metric_denylist"`
-	MetricOptInList      MetricSet       `yaml:"metric_opt_in_list"`
-	Resources            ResourceSet     `yaml:"resources"`
+	AnnotationsAllowList   LabelsAllowList   `yaml:"annotations_allow_list"`
+	LabelsAllowList        LabelsAllowList   `yaml:"labels_allow_list"`
+	MetricAliases          map[string]string `yaml:"metric_aliases"`
+	MetricLabelsDenylist   LabelsAllowList   `yaml:"metric_labels_denylist"`
+	ResourceFieldSelectors map[string]string `yaml:"resource_field_selectors"`
+	ResourceLabelSelectors map[string]string `yaml:"resource_label_selectors"`
+	MetricAllowlist        MetricSet         `yaml:"metric_allowlist"`
+	MetricDenylist         MetricSet         `yaml:"metric_denylist"`
+	MetricOptInList        MetricSet         `yaml:"metric_opt_in_list"`
+	MetricTimestampInfo    MetricSet         `yaml:"metric_timestamp_info"`
+	Resources              ResourceSet       `yaml:"resources"`
 
 	cmd                      *cobra.Command
 	Apiserver                string   `yaml:"apiserver"`
+	ApiserverCAFile          string   `yaml:"apiserver_ca_file"`
+	ApiserverTLSServerName   string   `yaml:"apiserver_tls_server_name"`
+	Context                  string   `yaml:"context"`
 	CustomResourceConfig     string   `yaml:"custom_resource_config"`
 	CustomResourceConfigFile string   `yaml:"custom_resource_config_file"`
+	DumpTo                   string   `yaml:"dump_to"`
 	Host                     string   `yaml:"host"`
 	Kubeconfig               string   `yaml:"kubeconfig"`
+	LogFormat                string   `yaml:"log_format"`
+	LogLevel                 string   `yaml:"log_level"`
+	MetricStability          string   `yaml:"metric_stability"`
+	ListenUnixSocket         string   `yaml:"listen_unix_socket"`
+	ListenUnixSocketMode     string   `yaml:"listen_unix_socket_mode"`
 	Namespace                string   `yaml:"namespace"`
+	OTLPEndpoint             string   `yaml:"otlp_endpoint"`
+	ObjectsLabelSelector     string   `yaml:"objects_label_selector"`
 	Node                     NodeType `yaml:"node"`
 	Pod                      string   `yaml:"pod"`
 	TLSConfig                string   `yaml:"tls_config"`
 	TelemetryHost            string   `yaml:"telemetry_host"`
 
 	Config string
+	// Profile is only read from the command line, see ApplyProfile.
+	Profile string `yaml:"-"`
+	// listFiles holds the files of the list flags given as @/path/to/file,
+	// see ReloadListFiles.
+	listFiles map[*listFileValue]string
 
 	Namespaces              NamespaceList `yaml:"namespaces"`
 	NamespacesDenylist      NamespaceList `yaml:"namespaces_denylist"`
+	InitialListOrder        []string      `yaml:"initial_list_order"`
+	MetadataOnlyResources   []string      `yaml:"metadata_only_resources"`
+	GenericResources        []string      `yaml:"generic_resources"`
 	AutoGoMemlimitRatio     float64       `yaml:"auto-gomemlimit-ratio"`
+	KubeAPIQPS              float32       `yaml:"kube_api_qps"`
+	ListFilePollInterval    time.Duration `yaml:"list_file_poll_interval"`
+	MetricsCacheTTL         time.Duration `yaml:"metrics_cache_ttl"`
+	OTLPInterval            time.Duration `yaml:"otlp_interval"`
+	RelistPeriod            time.Duration `yaml:"relist_period"`
+	ResyncPeriod            time.Duration `yaml:"resync_period"`
+	InitialListConcurrency  int           `yaml:"initial_list_concurrency"`
+	KubeAPIBurst            int           `yaml:"kube_api_burst"`
 	Port                    int           `yaml:"port"`
 	TelemetryPort           int           `yaml:"telemetry_port"`
 	TotalShards             int           `yaml:"total_shards"`
@@ -72,13 +104,21 @@ type Options struct {
 	ServerIdleTimeout       time.Duration `yaml:"server_idle_timeout"`
 	ServerReadHeaderTimeout time.Duration `yaml:"server_read_header_timeout"`
 
-	Shard                int32 `yaml:"shard"`
-	AutoGoMemlimit       bool  `yaml:"auto-gomemlimit"`
-	CustomResourcesOnly  bool  `yaml:"custom_resources_only"`
-	EnableGZIPEncoding   bool  `yaml:"enable_gzip_encoding"`
-	Help                 bool  `yaml:"help"`
-	TrackUnscheduledPods bool  `yaml:"track_unscheduled_pods"`
-	UseAPIServerCache    bool  `yaml:"use_api_server_cache"`
+	Shard                          int32 `yaml:"shard"`
+	ApiserverInsecureSkipTLSVerify bool  `yaml:"apiserver_insecure_skip_tls_verify"`
+	AutoGoMemlimit                 bool  `yaml:"auto-gomemlimit"`
+	CustomResourcesOnly            bool  `yaml:"custom_resources_only"`
+	DelegateAuth                   bool  `yaml:"delegate_auth"`
+	DryRun                         bool  `yaml:"dry_run"`
+	EnableGZIPEncoding             bool  `yaml:"enable_gzip_encoding"`
+	EnableObjectsEndpoint          bool  `yaml:"enable_objects_endpoint"`
+	EnableReloadEndpoint           bool  `yaml:"enable_reload_endpoint"`
+	EnableZstdEncoding             bool  `yaml:"enable_zstd_encoding"`
+	Help                           bool  `yaml:"help"`
+	TrackNamespaceRecreation       bool  `yaml:"track_namespace_recreation"`
+	TrackUnscheduledPods           bool  `yaml:"track_unscheduled_pods"`
+	UseAPIServerCache              bool  `yaml:"use_api_server_cache"`
+	ValidateOutput                 bool  `yaml:"validate_output"`
 }
 
 // GetConfigFile is the getter for --config value.
@@ -93,8 +133,10 @@ func NewOptions() *Options {
 		MetricAllowlist:      MetricSet{},
 		MetricDenylist:       MetricSet{},
 		MetricOptInList:      MetricSet{},
+		MetricTimestampInfo:  MetricSet{},
 		AnnotationsAllowList: LabelsAllowList{},
 		LabelsAllowList:      LabelsAllowList{},
+		MetricLabelsDenylist: LabelsAllowList{},
 	}
 }
 
@@ -113,16 +155,14 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 		klog.FlushAndExit(klog.ExitFlushTimeout, 0)
 	})
 
-	versionCommand := &cobra.Command{
-		Use:   "version",
-		Short: "Print version information.",
-		Run: func(_ *cobra.Command, _ []string) {
-			fmt.Printf("%s\n", version.Print("kube-state-metrics"))
-			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
-		},
-	}
+	cmd.AddCommand(completionCommand, newDiffCommand())
 
-	cmd.AddCommand(completionCommand, versionCommand)
+	// cobra prints the version template and exits if --version is set. The
+	// flag is defined here as cobra would otherwise take the -v shorthand of
+	// the klog verbosity.
+	cmd.Version = version.Version
+	cmd.SetVersionTemplate(version.Print("kube-state-metrics") + "\n")
+	cmd.Flags().Bool("version", false, "Print version information and exit.")
 
 	o.cmd.Flags().Usage = func() {
 		_, _ = fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
@@ -139,36 +179,72 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 	autoshardingNotice := "When set, it is expected that --pod and --pod-namespace are both set. Most likely this should be passed via the downward API. This is used for auto-detecting sharding. If set, this has preference over statically configured sharding. This is experimental, it may be removed without notice."
 
 	o.cmd.Flags().BoolVar(&o.CustomResourcesOnly, "custom-resource-state-only", false, "Only provide Custom Resource State metrics (experimental)")
+	o.cmd.Flags().BoolVar(&o.DelegateAuth, "delegate-auth", false, "Require a bearer token on requests to the metrics server, which is authenticated with a TokenReview and authorized with a SubjectAccessReview of the requested non-resource URL, e.g. a get of /metrics, against the apiserver. The /healthz and /livez probes stay public. Requires permissions to create tokenreviews and subjectaccessreviews.")
 	o.cmd.Flags().BoolVar(&o.EnableGZIPEncoding, "enable-gzip-encoding", false, "Gzip responses when requested by clients via 'Accept-Encoding: gzip' header.")
+	o.cmd.Flags().BoolVar(&o.EnableZstdEncoding, "enable-zstd-encoding", false, "Compress responses with zstd when requested by clients via 'Accept-Encoding: zstd' header. It is preferred over gzip when clients accept both.")
+	o.cmd.Flags().BoolVar(&o.EnableObjectsEndpoint, "enable-objects-endpoint", false, "Enable the /objects endpoint of the metrics server, which serves the resource, namespace, name, labels and key status fields of the cached objects as JSON. The objects can be restricted with the resource and namespace query parameters. Recording the objects increases the memory usage.")
+	o.cmd.Flags().BoolVar(&o.EnableReloadEndpoint, "enable-reload-endpoint", false, "Enable the /-/reload endpoint of the metrics server, which restarts kube-state-metrics with its current configuration on POST requests, like sending it SIGHUP.")
+	o.cmd.Flags().BoolVar(&o.TrackNamespaceRecreation, "track-namespace-recreation", false, "Restart the reflectors of a namespace of --namespaces when it is deleted and recreated, instead of requiring a restart of kube-state-metrics. Requires permissions to list and watch namespaces. Has no effect when all namespaces are watched.")
 	o.cmd.Flags().BoolVar(&o.TrackUnscheduledPods, "track-unscheduled-pods", false, "This configuration is used in conjunction with node configuration. When this configuration is true, node configuration is empty and the metric of unscheduled pods is fetched from the Kubernetes API Server. This is experimental.")
 	o.cmd.Flags().BoolVarP(&o.Help, "help", "h", false, "Print Help text")
 	o.cmd.Flags().BoolVarP(&o.UseAPIServerCache, "use-apiserver-cache", "", false, "Sets resourceVersion=0 for ListWatch requests, using cached resources from the apiserver instead of an etcd quorum read.")
+	o.cmd.Flags().BoolVar(&o.ValidateOutput, "validate-output", false, "Drop series which are written out more than once in a metrics response, e.g. by two objects generating the same labels, instead of having the whole scrape rejected by Prometheus. Dropped series are logged and counted in kube_state_metrics_duplicate_series_total.")
 	o.cmd.Flags().Int32Var(&o.Shard, "shard", int32(0), "The instances shard nominal (zero indexed) within the total number of shards. (default 0)")
+	o.cmd.Flags().IntVar(&o.InitialListConcurrency, "initial-list-concurrency", 0, "Maximum number of resources performing their initial list at the same time. Zero means no limit. Limiting this lets the resources listed first in --initial-list-order warm up sooner on large clusters.")
+	o.cmd.Flags().IntVar(&o.KubeAPIBurst, "kube-api-burst", 0, "Burst of requests to the apiserver above --kube-api-qps, e.g. while all resources are relisted. Zero keeps the client-go default.")
 	o.cmd.Flags().IntVar(&o.Port, "port", 8080, `Port to expose metrics on.`)
 	o.cmd.Flags().IntVar(&o.TelemetryPort, "telemetry-port", 8081, `Port to expose kube-state-metrics self metrics on.`)
 	o.cmd.Flags().IntVar(&o.TotalShards, "total-shards", 1, "The total number of shards. Sharding is disabled when total shards is set to 1.")
 	o.cmd.Flags().StringVar(&o.Apiserver, "apiserver", "", `The URL of the apiserver to use as a master`)
+	o.cmd.Flags().StringVar(&o.ApiserverCAFile, "apiserver-ca-file", "", "Path to the CA certificate used to verify the apiserver set by --apiserver, instead of the in-cluster or kubeconfig CA certificate. Mutually exclusive with --apiserver-insecure-skip-tls-verify.")
+	o.cmd.Flags().StringVar(&o.ApiserverTLSServerName, "apiserver-tls-server-name", "", "Server name used to verify the certificate of the apiserver set by --apiserver, e.g. 'kubernetes.default.svc' when the host is not covered by the certificate.")
+	o.cmd.Flags().BoolVar(&o.ApiserverInsecureSkipTLSVerify, "apiserver-insecure-skip-tls-verify", false, "Skip the verification of the certificate of the apiserver set by --apiserver. This is insecure and meant for testing only.")
 	o.cmd.Flags().BoolVar(&o.AutoGoMemlimit, "auto-gomemlimit", false, "Automatically set GOMEMLIMIT to match container or system memory limit. (experimental)")
 	o.cmd.Flags().Float64Var(&o.AutoGoMemlimitRatio, "auto-gomemlimit-ratio", float64(0.9), "The ratio of reserved GOMEMLIMIT memory to the detected maximum container or system memory. (experimental)")
+	o.cmd.Flags().Float32Var(&o.KubeAPIQPS, "kube-api-qps", 0, "Maximum number of queries per second to the apiserver. Zero keeps the client-go default.")
 	o.cmd.Flags().StringVar(&o.CustomResourceConfig, "custom-resource-state-config", "", "Inline Custom Resource State Metrics config YAML (experimental)")
 	o.cmd.Flags().StringVar(&o.CustomResourceConfigFile, "custom-resource-state-config-file", "", "Path to a Custom Resource State Metrics config file (experimental)")
+	o.cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Check that the apiserver serves the enabled resources and that they can be listed and watched, print which collectors and metric families would be enabled, or why they are skipped, and exit instead of serving the metrics. Exits with a non-zero code if an enabled resource can not be collected.")
+	o.cmd.Flags().StringVar(&o.DumpTo, "dump-to", "", "Path of a file to which the metrics are written once all enabled resources were listed, after which kube-state-metrics exits instead of serving them. The file is compressed with gzip if its name ends with .gz. Custom resource state metrics are not supported.")
 	o.cmd.Flags().StringVar(&o.Host, "host", "::", `Host to expose metrics on.`)
-	o.cmd.Flags().StringVar(&o.Kubeconfig, "kubeconfig", "", "Absolute path to the kubeconfig file")
+	o.cmd.Flags().StringVar(&o.Context, "context", "", "Name of the kubeconfig context to use instead of the current context. Disables the in-cluster configuration.")
+	o.cmd.Flags().StringVar(&o.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file. Defaults to the in-cluster configuration if the service account token is mounted, otherwise to the files of the KUBECONFIG environment variable or ~/.kube/config.")
+	o.cmd.Flags().StringVar(&o.LogFormat, "log-format", LogFormatKlog, "Format of the logs, one of klog, text or json. The text and json formats write structured logs with log/slog.")
+	o.cmd.Flags().StringVar(&o.LogLevel, "log-level", "", "Minimum level of the logs, one of debug, info, warn or error. warn and error require --log-format text or json. debug enables the verbose logs up to -v=4, including the requests to the servers, unless -v is set.")
+	o.cmd.Flags().StringVar(&o.ListenUnixSocket, "listen-unix-socket", "", "Path of a Unix domain socket to serve the metrics server on instead of --host and --port, e.g. in an emptyDir shared with a scraping sidecar. TLS is not used on the socket.")
+	o.cmd.Flags().StringVar(&o.ListenUnixSocketMode, "listen-unix-socket-mode", "0660", "Permissions of the Unix domain socket given by --listen-unix-socket, in octal.")
+	o.cmd.Flags().StringVar(&o.ObjectsLabelSelector, "objects-label-selector", "", "Label selector the objects of all resources have to match to be watched (Example: 'tenant=team-a'). Objects which don't match it are neither listed nor kept in memory.")
+	o.cmd.Flags().StringVar(&o.OTLPEndpoint, "otlp-endpoint", "", "URL of an OTLP/HTTP metrics endpoint, e.g. 'http://otel-collector:4318/v1/metrics', to which the metrics are pushed every --otlp-interval in addition to being served on /metrics. The namespace and pod labels become the k8s.namespace.name and k8s.pod.name resource attributes. This is experimental.")
 	o.cmd.Flags().StringVar(&o.Namespace, "pod-namespace", "", "Name of the namespace of the pod specified by --pod. "+autoshardingNotice)
 	o.cmd.Flags().StringVar(&o.Pod, "pod", "", "Name of the pod that contains the kube-state-metrics container. "+autoshardingNotice)
 	o.cmd.Flags().StringVar(&o.TLSConfig, "tls-config", "", "Path to the TLS configuration file")
 	o.cmd.Flags().StringVar(&o.TelemetryHost, "telemetry-host", "::", `Host to expose kube-state-metrics self metrics on.`)
 	o.cmd.Flags().StringVar(&o.Config, "config", "", "Path to the kube-state-metrics options config file")
+	o.cmd.Flags().StringVar(&o.Profile, "profile", "", fmt.Sprintf("Preset profile tuning the defaults of other flags for the size of the cluster, one of %s. Flags set on the command line and the options config file take precedence over the profile.", strings.Join(Profiles(), ", ")))
 	o.cmd.Flags().StringVar((*string)(&o.Node), "node", "", "Name of the node that contains the kube-state-metrics pod. Most likely it should be passed via the downward API. This is used for daemonset sharding. Only available for resources (pod metrics) that support spec.nodeName fieldSelector. This is experimental.")
-	o.cmd.Flags().Var(&o.AnnotationsAllowList, "metric-annotations-allowlist", "Comma-separated list of Kubernetes annotations keys that will be used in the resource' labels metric. By default the annotations metrics are not exposed. To include them, provide a list of resource names in their plural form and Kubernetes annotation keys you would like to allow for them (Example: '=namespaces=[kubernetes.io/team,...],pods=[kubernetes.io/team],...)'. A single '*' can be provided per resource instead to allow any annotations, but that has severe performance implications (Example: '=pods=[*]').")
-	o.cmd.Flags().Var(&o.LabelsAllowList, "metric-labels-allowlist", "Comma-separated list of additional Kubernetes label keys that will be used in the resource' labels metric. By default the labels metrics are not exposed. To include them, provide a list of resource names in their plural form and Kubernetes label keys you would like to allow for them (Example: '=namespaces=[k8s-label-1,k8s-label-n,...],pods=[app],...)'. A single '*' can be provided per resource instead to allow any labels, but that has severe performance implications (Example: '=pods=[*]'). Additionally, an asterisk (*) can be provided as a key, which will resolve to all resources, i.e., assuming '--resources=deployments,pods', '=*=[*]' will resolve to '=deployments=[*],pods=[*]'.")
-	o.cmd.Flags().Var(&o.MetricAllowlist, "metric-allowlist", "Comma-separated list of metrics to be exposed. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
-	o.cmd.Flags().Var(&o.MetricDenylist, "metric-denylist", "Comma-separated list of metrics not to be enabled. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
-	o.cmd.Flags().Var(&o.MetricOptInList, "metric-opt-in-list", "Comma-separated list of metrics which are opt-in and not enabled by default. This is in addition to the metric allow- and denylists")
-	o.cmd.Flags().Var(&o.Namespaces, "namespaces", fmt.Sprintf("Comma-separated list of namespaces to be enabled. Defaults to %q", &DefaultNamespaces))
-	o.cmd.Flags().Var(&o.NamespacesDenylist, "namespaces-denylist", "Comma-separated list of namespaces not to be enabled. If namespaces and namespaces-denylist are both set, only namespaces that are excluded in namespaces-denylist will be used.")
-	o.cmd.Flags().Var(&o.Resources, "resources", fmt.Sprintf("Comma-separated list of Resources to be enabled. Defaults to %q", &DefaultResources))
+	o.cmd.Flags().Var(o.listFile(&o.AnnotationsAllowList, func() { o.AnnotationsAllowList = LabelsAllowList{} }), "metric-annotations-allowlist", "Comma-separated list of Kubernetes annotations keys that will be used in the resource' labels metric. By default the annotations metrics are not exposed. To include them, provide a list of resource names in their plural form and Kubernetes annotation keys you would like to allow for them (Example: '=namespaces=[kubernetes.io/team,...],pods=[kubernetes.io/team],...)'. A single '*' can be provided per resource instead to allow any annotations, but that has severe performance implications (Example: '=pods=[*]').")
+	o.cmd.Flags().Var(o.listFile(&o.LabelsAllowList, func() { o.LabelsAllowList = LabelsAllowList{} }), "metric-labels-allowlist", "Comma-separated list of additional Kubernetes label keys that will be used in the resource' labels metric. By default the labels metrics are not exposed. To include them, provide a list of resource names in their plural form and Kubernetes label keys you would like to allow for them (Example: '=namespaces=[k8s-label-1,k8s-label-n,...],pods=[app],...)'. A single '*' can be provided per resource instead to allow any labels, but that has severe performance implications (Example: '=pods=[*]'). Additionally, an asterisk (*) can be provided as a key, which will resolve to all resources, i.e., assuming '--resources=deployments,pods', '=*=[*]' will resolve to '=deployments=[*],pods=[*]'.")
+	o.cmd.Flags().Var(o.listFile(&o.MetricLabelsDenylist, func() { o.MetricLabelsDenylist = LabelsAllowList{} }), "metric-labels-denylist", "Comma-separated list of metric families and the labels which are dropped from their series, to reduce their cardinality without disabling them (Example: '=kube_pod_info=[uid],kube_pod_container_info=[image_id]'). An asterisk (*) can be provided as a metric family to drop the labels from all families (Example: '=*=[uid]'). Dropping a label which distinguishes the series of a family results in duplicate series.")
+	o.cmd.Flags().Var(o.listFile(&o.MetricAllowlist, func() { o.MetricAllowlist = MetricSet{} }), "metric-allowlist", "Comma-separated list of metrics to be exposed. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
+	o.cmd.Flags().Var(o.listFile(&o.MetricDenylist, func() { o.MetricDenylist = MetricSet{} }), "metric-denylist", "Comma-separated list of metrics not to be enabled. This list comprises of exact metric names and/or regex patterns. The allowlist and denylist are mutually exclusive.")
+	o.cmd.Flags().StringToStringVar(&o.MetricAliases, "metric-aliases", nil, "Comma-separated list of metric families which are additionally exposed under a legacy name, given as family=legacy_name (Example: 'kube_pod_container_restart_policy=kube_pod_container_restartpolicy'). This allows renaming a metric family without breaking existing queries during a deprecation window.")
+	o.cmd.Flags().Var(o.listFile(&o.MetricTimestampInfo, func() { o.MetricTimestampInfo = MetricSet{} }), "metric-timestamp-info", "Comma-separated list of timestamp metric families, e.g. kube_pod_created, which are additionally exposed as <family>_info with the timestamp formatted as RFC3339 in the timestamp label.")
+	o.cmd.Flags().Var(o.listFile(&o.MetricOptInList, func() { o.MetricOptInList = MetricSet{} }), "metric-opt-in-list", "Comma-separated list of metrics which are opt-in and not enabled by default. This is in addition to the metric allow- and denylists")
+	o.cmd.Flags().StringVar(&o.MetricStability, "metric-stability", "experimental", "Stability of the metric families to be enabled, one of experimental or stable. experimental enables the families of all stability levels, stable only the STABLE families which are not deprecated. This is in addition to the metric allow- and denylists.")
+	o.cmd.Flags().Var(o.listFile(&o.Namespaces, func() { o.Namespaces = nil }), "namespaces", fmt.Sprintf("Comma-separated list of namespaces to be enabled. Glob patterns such as 'tenant-*' are matched against the namespace of each object, in which case all namespaces are watched. Defaults to %q", &DefaultNamespaces))
+	o.cmd.Flags().Var(o.listFile(&o.NamespacesDenylist, func() { o.NamespacesDenylist = nil }), "namespaces-denylist", "Comma-separated list of namespaces not to be enabled. Glob patterns such as 'tenant-*' are supported. If namespaces and namespaces-denylist are both set, only namespaces that are excluded in namespaces-denylist will be used.")
+	o.cmd.Flags().StringSliceVar(&o.GenericResources, "generic-resources", nil, "Comma-separated list of resources without a store of their own, given as group/version/resource or version/resource for the core group (Example: 'cert-manager.io/v1/certificates,v1/events'). They are watched as unstructured objects and only expose the _info, _created, _labels and _annotations metrics, the labels and annotations being controlled by the allowlists of resource.group, e.g. 'certificates.cert-manager.io'. This is experimental.")
+	o.cmd.Flags().StringSliceVar(&o.InitialListOrder, "initial-list-order", nil, "Comma-separated list of resources whose initial list is started first, in the given order. The remaining resources are started in alphabetical order.")
+	o.cmd.Flags().StringSliceVar(&o.MetadataOnlyResources, "metadata-only-resources", nil, "Comma-separated list of resources which are listed and watched as metadata only, which cuts the memory of decoding their objects. Supported resources are clusterroles, configmaps, leases, roles and secrets. Families which need more than the metadata, e.g. kube_secret_type, are not exposed for them.")
+	o.cmd.Flags().StringToStringVar(&o.ResourceFieldSelectors, "resource-field-selectors", nil, "Comma-separated list of additional field selectors of resources, given as resource=selector (Example: 'pods=status.phase!=Succeeded'). A selector with several requirements has to be quoted (Example: '\"pods=status.phase!=Succeeded,status.phase!=Failed\"'). Objects which don't match the field selector of their resource are not watched, which reduces the memory usage on clusters with many of them.")
+	o.cmd.Flags().StringToStringVar(&o.ResourceLabelSelectors, "resource-label-selectors", nil, "Comma-separated list of label selectors of resources overriding --objects-label-selector, given as resource=selector (Example: 'pods=tenant=team-a'). A selector with several requirements has to be quoted (Example: '\"pods=tenant=team-a,app!=batch\"').")
+	o.cmd.Flags().Var(o.listFile(&o.Resources, func() { o.Resources = ResourceSet{} }), "resources", fmt.Sprintf("Comma-separated list of Resources to be enabled. Defaults to %q", &DefaultResources))
 
+	o.cmd.Flags().DurationVar(&o.ListFilePollInterval, "list-file-poll-interval", 30*time.Second, "Interval at which the files of list flags given as @/path/to/file are polled for changes. kube-state-metrics is reloaded when any of them changed.")
+	o.cmd.Flags().DurationVar(&o.MetricsCacheTTL, "metrics-cache-ttl", 0, "Duration for which rendered /metrics responses are cached and served to further scrapes with the same format, encoding and query, e.g. of several Prometheus replicas. Zero disables the cache.")
+	o.cmd.Flags().DurationVar(&o.OTLPInterval, "otlp-interval", 30*time.Second, "Interval at which the metrics are pushed to --otlp-endpoint.")
+	o.cmd.Flags().DurationVar(&o.RelistPeriod, "relist-period", 0, "Period after which the reflectors of all resources are restarted, which lists the objects from the apiserver again and generates their metrics again, e.g. to recover from a suspected stale watch. Zero disables periodic relists.")
+	o.cmd.Flags().DurationVar(&o.ResyncPeriod, "resync-period", 0, "Resync period of the reflectors, after which they call Resync on their stores. The stores of the built-in resources keep no objects, so this only affects custom stores; use --relist-period to generate the metrics again from a fresh list. Zero disables resyncs.")
 	o.cmd.Flags().DurationVar(&o.ServerReadTimeout, "server-read-timeout", defaultServerReadTimeout, "The maximum duration for reading the entire request, including the body. Align with the scrape interval or timeout of scraping clients. ")
 	o.cmd.Flags().DurationVar(&o.ServerWriteTimeout, "server-write-timeout", defaultServerWriteTimeout, "The maximum duration before timing out writes of the response. Align with the scrape interval or timeout of scraping clients..")
 	o.cmd.Flags().DurationVar(&o.ServerIdleTimeout, "server-idle-timeout", defaultServerIdleTimeout, "The maximum amount of time to wait for the next request when keep-alives are enabled. Align with the idletimeout of your scrape clients.")
@@ -188,6 +264,19 @@ func (o *Options) Usage() {
 
 // Validate validates arguments
 func (o *Options) Validate() error {
+	if o.Apiserver == "" && (o.ApiserverCAFile != "" || o.ApiserverTLSServerName != "" || o.ApiserverInsecureSkipTLSVerify) {
+		return fmt.Errorf("--apiserver-ca-file, --apiserver-tls-server-name and --apiserver-insecure-skip-tls-verify require --apiserver to be set")
+	}
+	if o.ApiserverCAFile != "" && o.ApiserverInsecureSkipTLSVerify {
+		return fmt.Errorf("--apiserver-ca-file and --apiserver-insecure-skip-tls-verify are mutually exclusive")
+	}
+	if o.OTLPEndpoint != "" && o.OTLPInterval <= 0 {
+		return fmt.Errorf("--otlp-interval must be positive")
+	}
+	if o.KubeAPIQPS < 0 || o.KubeAPIBurst < 0 {
+		return fmt.Errorf("--kube-api-qps and --kube-api-burst must not be negative")
+	}
+
 	shardableResource := "pods"
 	if o.Node == "" {
 		return nil
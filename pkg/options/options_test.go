@@ -63,3 +63,45 @@ func TestOptionsParse(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionsValidateAPIServerTLS(t *testing.T) {
+	tests := []struct {
+		Desc         string
+		Opts         Options
+		ExpectsError bool
+	}{
+		{
+			Desc: "apiserver with CA file",
+			Opts: Options{Apiserver: "https://10.0.0.1:6443", ApiserverCAFile: "/etc/ca.crt", ApiserverTLSServerName: "kubernetes.default.svc", AutoGoMemlimitRatio: 0.9},
+		},
+		{
+			Desc:         "CA file without apiserver",
+			Opts:         Options{ApiserverCAFile: "/etc/ca.crt", AutoGoMemlimitRatio: 0.9},
+			ExpectsError: true,
+		},
+		{
+			Desc:         "server name without apiserver",
+			Opts:         Options{ApiserverTLSServerName: "kubernetes.default.svc", AutoGoMemlimitRatio: 0.9},
+			ExpectsError: true,
+		},
+		{
+			Desc:         "CA file and insecure skip verify",
+			Opts:         Options{Apiserver: "https://10.0.0.1:6443", ApiserverCAFile: "/etc/ca.crt", ApiserverInsecureSkipTLSVerify: true, AutoGoMemlimitRatio: 0.9},
+			ExpectsError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			err := test.Opts.Validate()
+
+			if !test.ExpectsError && err != nil {
+				t.Errorf("Error for test with description: %s: %v", test.Desc, err.Error())
+			}
+
+			if test.ExpectsError && err == nil {
+				t.Errorf("Expected error for test with description: %s", test.Desc)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profiles holds the preset profiles selectable with --profile. Each profile
+// maps flag names to the values they default to.
+//
+//go:embed profiles/*.yaml
+var profiles embed.FS
+
+// Profiles returns the names of the available profiles.
+func Profiles() []string {
+	entries, _ := profiles.ReadDir("profiles")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyProfile sets the flags of the profile selected by --profile. Flags
+// which were set on the command line take precedence over the profile, the
+// options config file takes precedence over both.
+func (o *Options) ApplyProfile() error {
+	if o.Profile == "" {
+		return nil
+	}
+
+	data, err := profiles.ReadFile(path.Join("profiles", o.Profile+".yaml"))
+	if err != nil {
+		return fmt.Errorf("unknown profile %q, available profiles are %s", o.Profile, strings.Join(Profiles(), ", "))
+	}
+	values := map[string]string{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return fmt.Errorf("failed to parse profile %q: %w", o.Profile, err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := o.cmd.Flags().Lookup(name)
+		if f == nil {
+			return fmt.Errorf("profile %q sets unknown flag --%s", o.Profile, name)
+		}
+		// Setting the flag marks it as changed, so that the profile is only
+		// applied once when the options are reloaded.
+		if f.Changed {
+			continue
+		}
+		if err := o.cmd.Flags().Set(name, values[name]); err != nil {
+			return fmt.Errorf("profile %q sets invalid value %q for --%s: %w", o.Profile, values[name], name, err)
+		}
+	}
+
+	return nil
+}
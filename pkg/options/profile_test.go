@@ -0,0 +1,107 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		Desc                   string
+		Args                   []string
+		InitialListConcurrency int
+		InitialListOrder       []string
+		UseAPIServerCache      bool
+		ExpectsError           bool
+	}{
+		{
+			Desc:                   "no profile",
+			Args:                   []string{},
+			InitialListConcurrency: 0,
+		},
+		{
+			Desc:                   "large profile",
+			Args:                   []string{"--profile=large"},
+			InitialListConcurrency: 2,
+			InitialListOrder:       []string{"pods", "replicasets", "nodes"},
+			UseAPIServerCache:      true,
+		},
+		{
+			Desc:                   "command line takes precedence over profile",
+			Args:                   []string{"--profile=large", "--initial-list-concurrency=8", "--use-apiserver-cache=false"},
+			InitialListConcurrency: 8,
+			InitialListOrder:       []string{"pods", "replicasets", "nodes"},
+			UseAPIServerCache:      false,
+		},
+		{
+			Desc:         "unknown profile",
+			Args:         []string{"--profile=huge"},
+			ExpectsError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Desc, func(t *testing.T) {
+			opts := NewOptions()
+			opts.AddFlags(&cobra.Command{Use: "kube-state-metrics"})
+			if err := opts.cmd.Flags().Parse(test.Args); err != nil {
+				t.Fatal(err)
+			}
+
+			// Applying a profile again, e.g. on a reload of the options, must not change them.
+			for i := 0; i < 2; i++ {
+				err := opts.ApplyProfile()
+				if test.ExpectsError {
+					if err == nil {
+						t.Fatalf("Expected error for test with description: %s", test.Desc)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Error for test with description: %s: %v", test.Desc, err)
+				}
+			}
+
+			if opts.InitialListConcurrency != test.InitialListConcurrency {
+				t.Errorf("expected initial list concurrency %d, got %d", test.InitialListConcurrency, opts.InitialListConcurrency)
+			}
+			if !reflect.DeepEqual(opts.InitialListOrder, test.InitialListOrder) {
+				t.Errorf("expected initial list order %v, got %v", test.InitialListOrder, opts.InitialListOrder)
+			}
+			if opts.UseAPIServerCache != test.UseAPIServerCache {
+				t.Errorf("expected use apiserver cache %t, got %t", test.UseAPIServerCache, opts.UseAPIServerCache)
+			}
+		})
+	}
+}
+
+func TestProfilesAreValid(t *testing.T) {
+	for _, profile := range Profiles() {
+		t.Run(profile, func(t *testing.T) {
+			opts := NewOptions()
+			opts.AddFlags(&cobra.Command{Use: "kube-state-metrics"})
+			opts.Profile = profile
+			if err := opts.ApplyProfile(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package options
 
 import (
 	"errors"
+	"fmt"
+	"path"
 	"sort"
 	"strings"
 
@@ -224,6 +226,71 @@ func (n *NamespaceList) Type() string {
 	return "string"
 }
 
+// HasPatterns checks if any of the namespaces of the NamespaceList is a glob pattern, e.g. `tenant-*`.
+func (n *NamespaceList) HasPatterns() bool {
+	for _, ns := range *n {
+		if isNamespacePattern(ns) {
+			return true
+		}
+	}
+	return false
+}
+
+func isNamespacePattern(ns string) bool {
+	return strings.ContainsAny(ns, "*?[")
+}
+
+// NamespaceFilter matches namespaces against the glob patterns of an allow and a deny list.
+// Unlike field selectors, it is evaluated against the namespace of each object, so namespaces
+// created after startup are picked up without any restart.
+type NamespaceFilter struct {
+	allow []string
+	deny  []string
+}
+
+// NewNamespaceFilter returns a NamespaceFilter for the given allow and deny lists. It returns nil
+// if neither list contains a glob pattern, in which case the lists are expected to be handled by
+// namespaced reflectors and field selectors.
+func NewNamespaceFilter(allow, deny NamespaceList) (*NamespaceFilter, error) {
+	if !allow.HasPatterns() && !deny.HasPatterns() {
+		return nil, nil
+	}
+
+	f := &NamespaceFilter{}
+	if !allow.IsAllNamespaces() {
+		f.allow = allow
+	}
+	f.deny = deny
+	for _, p := range append(append([]string{}, f.allow...), f.deny...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %w", p, err)
+		}
+	}
+	return f, nil
+}
+
+// Matches returns whether objects of the given namespace should be kept. Objects which are not
+// namespaced are always kept.
+func (f *NamespaceFilter) Matches(ns string) bool {
+	if ns == metav1.NamespaceAll {
+		return true
+	}
+	for _, p := range f.deny {
+		if ok, _ := path.Match(p, ns); ok {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, p := range f.allow {
+		if ok, _ := path.Match(p, ns); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // LabelWildcard allowlists any label
 const LabelWildcard = "*"
 
@@ -155,6 +155,81 @@ func TestNamespaceList_ExcludeNamespacesFieldSelector(t *testing.T) {
 	}
 }
 
+func TestNamespaceFilter(t *testing.T) {
+	tests := []struct {
+		Desc             string
+		Namespaces       NamespaceList
+		DeniedNamespaces NamespaceList
+		Matches          map[string]bool
+		WantNil          bool
+		WantErr          bool
+	}{
+		{
+			Desc:             "no patterns",
+			Namespaces:       NamespaceList{"default", "kube-system"},
+			DeniedNamespaces: NamespaceList{"kube-public"},
+			WantNil:          true,
+		},
+		{
+			Desc:       "allowed pattern",
+			Namespaces: NamespaceList{"tenant-*", "default"},
+			Matches: map[string]bool{
+				"":            true,
+				"default":     true,
+				"tenant-a":    true,
+				"tenant-b":    true,
+				"kube-system": false,
+			},
+		},
+		{
+			Desc:             "denied pattern",
+			Namespaces:       DefaultNamespaces,
+			DeniedNamespaces: NamespaceList{"kube-*"},
+			Matches: map[string]bool{
+				"":            true,
+				"default":     true,
+				"kube-system": false,
+				"kube-public": false,
+			},
+		},
+		{
+			Desc:             "allowed and denied patterns",
+			Namespaces:       NamespaceList{"tenant-*"},
+			DeniedNamespaces: NamespaceList{"tenant-?-test"},
+			Matches: map[string]bool{
+				"tenant-a":      true,
+				"tenant-a-test": false,
+				"default":       false,
+			},
+		},
+		{
+			Desc:       "invalid pattern",
+			Namespaces: NamespaceList{"tenant-[*"},
+			WantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		f, err := NewNamespaceFilter(test.Namespaces, test.DeniedNamespaces)
+		if (err != nil) != test.WantErr {
+			t.Errorf("Test error for Desc: %s. Want error: %t. Got Error: %v", test.Desc, test.WantErr, err)
+			continue
+		}
+		if test.WantErr {
+			continue
+		}
+		if (f == nil) != test.WantNil {
+			t.Errorf("Test error for Desc: %s. Want nil filter: %t. Got: %+v", test.Desc, test.WantNil, f)
+			continue
+		}
+		for ns, want := range test.Matches {
+			if got := f.Matches(ns); got != want {
+				t.Errorf("Test error for Desc: %s. Namespace %q: Want: %t. Got: %t.", test.Desc, ns, want, got)
+			}
+		}
+	}
+}
+
 func TestNodeFieldSelector(t *testing.T) {
 	tests := []struct {
 		Desc   string
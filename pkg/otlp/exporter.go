@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package otlp pushes the metrics of kube-state-metrics to an OpenTelemetry
+// collector with the OTLP/HTTP protocol and its JSON encoding.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/version"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metricsdiff"
+)
+
+// resourceLabels maps the labels which are turned into the attributes of the
+// resource of a series to the name of these attributes.
+var resourceLabels = []struct {
+	label     string
+	attribute string
+}{
+	{label: "namespace", attribute: "k8s.namespace.name"},
+	{label: "pod", attribute: "k8s.pod.name"},
+}
+
+// Exporter periodically converts the metrics written by its write function to
+// OTLP and pushes them to an OTLP/HTTP endpoint. Counters are pushed as
+// cumulative monotonic sums, all other types as gauges.
+type Exporter struct {
+	endpoint string
+	interval time.Duration
+	client   *http.Client
+	write    func(w io.Writer) error
+
+	mtx sync.Mutex
+	// startTimes holds the start time of the cumulative sums by series, which
+	// is the time the series was first pushed.
+	startTimes map[string]string
+}
+
+// NewExporter returns an Exporter pushing the metrics written by write to the
+// given OTLP/HTTP metrics endpoint, e.g. http://otel-collector:4318/v1/metrics,
+// every interval.
+func NewExporter(endpoint string, interval time.Duration, write func(w io.Writer) error) *Exporter {
+	return &Exporter{
+		endpoint: endpoint,
+		interval: interval,
+		client:   &http.Client{Timeout: interval},
+		write:    write,
+
+		startTimes: map[string]string{},
+	}
+}
+
+// Run pushes the metrics every interval until the context is done. Failed
+// pushes are logged and retried with the next interval.
+func (e *Exporter) Run(ctx context.Context) error {
+	klog.InfoS("Started OTLP exporter", "endpoint", e.endpoint, "interval", e.interval)
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if err := e.Export(ctx); err != nil {
+				klog.ErrorS(err, "Failed to push metrics", "endpoint", e.endpoint)
+			}
+		}
+	}
+}
+
+// Export pushes the current metrics once.
+func (e *Exporter) Export(ctx context.Context) error {
+	buf := &bytes.Buffer{}
+	if err := e.write(buf); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	families, err := metricsdiff.Parse(buf)
+	if err != nil {
+		return fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	e.mtx.Lock()
+	body, err := json.Marshal(convert(families, time.Now(), e.startTimes))
+	e.mtx.Unlock()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// convert converts the metric families to an OTLP export request. The series
+// are grouped into one resource per namespace and pod label. The start time of
+// the cumulative sums is looked up in startTimes by series, series which are
+// not found start now. Series which are gone are removed from startTimes.
+func convert(families map[string]*dto.MetricFamily, now time.Time, startTimes map[string]string) *exportRequest {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+	seen := map[string]struct{}{}
+	req := &exportRequest{}
+	resources := map[string]int{}
+	for _, name := range names {
+		family := families[name]
+		// metrics maps the resources to the index of the family among their
+		// metrics.
+		metrics := map[int]int{}
+		for _, m := range family.GetMetric() {
+			attributes, resourceAttributes, key := splitLabels(m.GetLabel())
+			r, ok := resources[key]
+			if !ok {
+				r = len(req.ResourceMetrics)
+				resources[key] = r
+				req.ResourceMetrics = append(req.ResourceMetrics, resourceMetrics{
+					Resource: resource{Attributes: resourceAttributes},
+					ScopeMetrics: []scopeMetrics{
+						{Scope: scope{Name: "kube-state-metrics", Version: version.Version}},
+					},
+				})
+			}
+			sm := &req.ResourceMetrics[r].ScopeMetrics[0]
+			i, ok := metrics[r]
+			if !ok {
+				i = len(sm.Metrics)
+				metrics[r] = i
+				sm.Metrics = append(sm.Metrics, newMetric(family))
+			}
+
+			point := dataPoint{Attributes: attributes, TimeUnixNano: timestamp}
+			if family.GetType() == dto.MetricType_COUNTER {
+				series := seriesKey(name, m.GetLabel())
+				seen[series] = struct{}{}
+				if _, ok := startTimes[series]; !ok {
+					startTimes[series] = timestamp
+				}
+				point.StartTimeUnixNano = startTimes[series]
+				point.AsDouble = doubleValue(m.GetCounter().GetValue())
+				sm.Metrics[i].Sum.DataPoints = append(sm.Metrics[i].Sum.DataPoints, point)
+				continue
+			}
+			point.AsDouble = doubleValue(value(m))
+			sm.Metrics[i].Gauge.DataPoints = append(sm.Metrics[i].Gauge.DataPoints, point)
+		}
+	}
+	for series := range startTimes {
+		if _, ok := seen[series]; !ok {
+			delete(startTimes, series)
+		}
+	}
+	return req
+}
+
+// seriesKey returns a key identifying the series of the given family with the
+// given labels.
+func seriesKey(name string, labels []*dto.LabelPair) string {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, l := range labels {
+		b.WriteString("\x00")
+		b.WriteString(l.GetName())
+		b.WriteString("\x00")
+		b.WriteString(l.GetValue())
+	}
+	return b.String()
+}
+
+func newMetric(family *dto.MetricFamily) otlpMetric {
+	m := otlpMetric{Name: family.GetName(), Description: family.GetHelp()}
+	if family.GetType() == dto.MetricType_COUNTER {
+		m.Sum = &sum{AggregationTemporality: aggregationTemporalityCumulative, IsMonotonic: true}
+	} else {
+		m.Gauge = &gauge{}
+	}
+	return m
+}
+
+func value(m *dto.Metric) float64 {
+	switch {
+	case m.Gauge != nil:
+		return m.GetGauge().GetValue()
+	case m.Untyped != nil:
+		return m.GetUntyped().GetValue()
+	default:
+		return math.NaN()
+	}
+}
+
+// splitLabels splits the labels of a series into the attributes of the series
+// and the attributes of its resource. It also returns a key identifying the
+// resource.
+func splitLabels(labels []*dto.LabelPair) (attributes, resourceAttributes []keyValue, key string) {
+	resourceAttributes = []keyValue{{Key: "service.name", Value: anyValue{StringValue: "kube-state-metrics"}}}
+	values := make([]string, len(resourceLabels))
+	for _, l := range labels {
+		isResource := false
+		for i, rl := range resourceLabels {
+			if l.GetName() == rl.label {
+				values[i] = l.GetValue()
+				isResource = true
+			}
+		}
+		if !isResource {
+			attributes = append(attributes, keyValue{Key: l.GetName(), Value: anyValue{StringValue: l.GetValue()}})
+		}
+	}
+	for i, rl := range resourceLabels {
+		if values[i] != "" {
+			resourceAttributes = append(resourceAttributes, keyValue{Key: rl.attribute, Value: anyValue{StringValue: values[i]}})
+		}
+		key += values[i] + "\x00"
+	}
+	return attributes, resourceAttributes, key
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"k8s.io/kube-state-metrics/v2/pkg/metricsdiff"
+)
+
+const exposition = `# HELP kube_pod_info Information about pod.
+# TYPE kube_pod_info info
+kube_pod_info{namespace="ns1",pod="pod1",node="node1"} 1
+kube_pod_info{namespace="ns1",pod="pod2",node="node1"} 1
+# HELP kube_pod_container_status_restarts_total The number of container restarts per container.
+# TYPE kube_pod_container_status_restarts_total counter
+kube_pod_container_status_restarts_total{namespace="ns1",pod="pod1",container="app"} 3
+# HELP kube_node_info Information about a cluster node.
+# TYPE kube_node_info gauge
+kube_node_info{node="node1"} 1
+`
+
+func TestExporterExport(t *testing.T) {
+	var got exportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected a JSON request, got %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	e := NewExporter(server.URL, time.Second, func(w io.Writer) error {
+		_, err := io.WriteString(w, exposition)
+		return err
+	})
+	if err := e.Export(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	resources := map[string]scopeMetrics{}
+	for _, rm := range got.ResourceMetrics {
+		key := ""
+		for _, a := range rm.Resource.Attributes {
+			key += a.Key + "=" + a.Value.StringValue + ","
+		}
+		resources[key] = rm.ScopeMetrics[0]
+	}
+	if len(resources) != 3 {
+		t.Fatalf("expected a resource per pod and one for the node, got %v", resources)
+	}
+
+	pod1, ok := resources["service.name=kube-state-metrics,k8s.namespace.name=ns1,k8s.pod.name=pod1,"]
+	if !ok {
+		t.Fatalf("expected a resource for pod1, got %v", resources)
+	}
+	if len(pod1.Metrics) != 2 {
+		t.Fatalf("expected two metrics for pod1, got %v", pod1.Metrics)
+	}
+	for _, m := range pod1.Metrics {
+		switch m.Name {
+		case "kube_pod_container_status_restarts_total":
+			if m.Sum == nil || !m.Sum.IsMonotonic || len(m.Sum.DataPoints) != 1 || m.Sum.DataPoints[0].AsDouble != 3 {
+				t.Errorf("expected the counter to be a monotonic sum of 3, got %+v", m)
+			}
+			if attrs := m.Sum.DataPoints[0].Attributes; len(attrs) != 1 || attrs[0].Key != "container" {
+				t.Errorf("expected the container label as the only attribute, got %v", attrs)
+			}
+		case "kube_pod_info":
+			if m.Gauge == nil || len(m.Gauge.DataPoints) != 1 || m.Description != "Information about pod." {
+				t.Errorf("expected the info family to be a gauge, got %+v", m)
+			}
+		default:
+			t.Errorf("unexpected metric %s for pod1", m.Name)
+		}
+	}
+
+	if _, ok := resources["service.name=kube-state-metrics,"]; !ok {
+		t.Fatalf("expected a resource without namespace and pod for the node, got %v", resources)
+	}
+}
+
+func TestConvertStartTime(t *testing.T) {
+	restarts := func(pods ...string) map[string]*dto.MetricFamily {
+		exposition := "# TYPE kube_pod_container_status_restarts_total counter\n"
+		for _, pod := range pods {
+			exposition += fmt.Sprintf("kube_pod_container_status_restarts_total{pod=%q} 1\n", pod)
+		}
+		families, err := metricsdiff.Parse(strings.NewReader(exposition))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return families
+	}
+	startTimes := func(req *exportRequest) map[string]string {
+		got := map[string]string{}
+		for _, rm := range req.ResourceMetrics {
+			pod := rm.Resource.Attributes[len(rm.Resource.Attributes)-1].Value.StringValue
+			got[pod] = rm.ScopeMetrics[0].Metrics[0].Sum.DataPoints[0].StartTimeUnixNano
+		}
+		return got
+	}
+
+	series := map[string]string{}
+	t1, t2, t3 := time.Unix(1, 0), time.Unix(2, 0), time.Unix(3, 0)
+	if got, want := startTimes(convert(restarts("pod1"), t1, series)), map[string]string{"pod1": "1000000000"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected start times %v, got %v", want, got)
+	}
+	if got, want := startTimes(convert(restarts("pod1", "pod2"), t2, series)), map[string]string{"pod1": "1000000000", "pod2": "2000000000"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the start time of a series to be kept, got %v instead of %v", got, want)
+	}
+	convert(restarts("pod2"), t3, series)
+	if got, want := startTimes(convert(restarts("pod1", "pod2"), t3, series)), map[string]string{"pod1": "3000000000", "pod2": "2000000000"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected a series which was gone to start again, got %v instead of %v", got, want)
+	}
+}
+
+func TestExporterExportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e := NewExporter(server.URL, time.Second, func(w io.Writer) error {
+		_, err := io.WriteString(w, exposition)
+		return err
+	})
+	if err := e.Export(context.Background()); err == nil {
+		t.Fatal("expected an error for a failed push")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package sharding
 
 import (
+	"fmt"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
@@ -54,3 +55,36 @@ func TestSharding(t *testing.T) {
 		t.Fatal("Shard two should not pick up the object.")
 	}
 }
+
+func TestShardingMovesMinimalObjectsOnResharding(t *testing.T) {
+	const objects = 10000
+	const totalShards = 4
+
+	shardOf := func(o metav1.Object, totalShards int) int32 {
+		for shard := int32(0); shard < int32(totalShards); shard++ {
+			if (&sharding{shard: shard, totalShards: totalShards}).keep(o) {
+				return shard
+			}
+		}
+		t.Fatalf("no shard picked up object %s", o.GetUID())
+		return -1
+	}
+
+	moved := 0
+	for i := 0; i < objects; i++ {
+		cm := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{UID: types.UID(fmt.Sprintf("uid-%d", i))}}
+		before, after := shardOf(cm, totalShards), shardOf(cm, totalShards+1)
+		if before == after {
+			continue
+		}
+		if after != totalShards {
+			t.Fatalf("object %s moved from shard %d to existing shard %d", cm.UID, before, after)
+		}
+		moved++
+	}
+
+	// About 1/(n+1) of the objects are expected to move to the new shard.
+	if expected := objects / (totalShards + 1); moved < expected*8/10 || moved > expected*12/10 {
+		t.Fatalf("expected about %d objects to move to the new shard, got %d", expected, moved)
+	}
+}
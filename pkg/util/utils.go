@@ -18,8 +18,10 @@ package util
 
 import (
 	"fmt"
+	"os"
 	"runtime"
 	"strings"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -27,34 +29,179 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
-	testUnstructuredMock "k8s.io/sample-controller/pkg/apis/samplecontroller/v1alpha1"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/version"
 
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
-var config *rest.Config
-var currentKubeClient clientset.Interface
-var currentDiscoveryClient *discovery.DiscoveryClient
+// serviceAccountCAFile is the CA certificate used by the in-cluster configuration.
+const serviceAccountCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
 
-// CreateKubeClient creates a Kubernetes clientset and a custom resource clientset.
-func CreateKubeClient(apiserver string, kubeconfig string) (clientset.Interface, error) {
-	if currentKubeClient != nil {
-		return currentKubeClient, nil
+// serviceAccountTokenFile is the service account token mounted into pods,
+// whose presence selects the in-cluster configuration.
+var serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// The client configuration and clients are memoized for the options they were
+// built with, and are rebuilt once the options change.
+var (
+	clientsMtx             sync.Mutex
+	configOptions          ClientOptions
+	config                 *rest.Config
+	currentKubeClient      clientset.Interface
+	currentDiscoveryClient *discovery.DiscoveryClient
+)
+
+// ClientOptions holds the settings the client configuration of this package is
+// built from.
+type ClientOptions struct {
+	// Apiserver overrides the host of the in-cluster or kubeconfig
+	// configuration.
+	Apiserver string
+	// Kubeconfig is the path of the kubeconfig, the KUBECONFIG environment
+	// variable or ~/.kube/config are used if empty.
+	Kubeconfig string
+	// Context is the kubeconfig context to use instead of the current context.
+	Context string
+	// QPS and Burst limit the requests to the apiserver. Zero values keep the
+	// client-go defaults.
+	QPS   float32
+	Burst int
+	// TLS is applied to the client configuration if Apiserver is set.
+	TLS APIServerTLSConfig
+}
+
+// NewClientOptions returns the client options set by the given command line
+// options.
+func NewClientOptions(opts *options.Options) ClientOptions {
+	return ClientOptions{
+		Apiserver:  opts.Apiserver,
+		Kubeconfig: opts.Kubeconfig,
+		Context:    opts.Context,
+		QPS:        opts.KubeAPIQPS,
+		Burst:      opts.KubeAPIBurst,
+		TLS: APIServerTLSConfig{
+			CAFile:             opts.ApiserverCAFile,
+			ServerName:         opts.ApiserverTLSServerName,
+			InsecureSkipVerify: opts.ApiserverInsecureSkipTLSVerify,
+		},
 	}
+}
 
-	var err error
+// APIServerTLSConfig holds the TLS settings which are applied to the client
+// configuration when --apiserver overrides the host of the in-cluster or
+// kubeconfig configuration.
+type APIServerTLSConfig struct {
+	CAFile             string
+	ServerName         string
+	InsecureSkipVerify bool
+}
 
-	if config == nil {
-		config, err = clientcmd.BuildConfigFromFlags(apiserver, kubeconfig)
-		if err != nil {
-			return nil, err
+// BuildConfig builds the client configuration from the given options. The TLS
+// settings of the options are only applied if the apiserver URL is set.
+func BuildConfig(o ClientOptions) (*rest.Config, error) {
+	c, err := loadConfig(o)
+	if err != nil {
+		return nil, err
+	}
+	if o.QPS > 0 {
+		c.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		c.Burst = o.Burst
+	}
+	if o.Apiserver == "" {
+		return c, nil
+	}
+
+	switch {
+	case o.TLS.InsecureSkipVerify:
+		// client-go refuses to use root certificates together with the insecure flag.
+		c.TLSClientConfig.Insecure = true
+		c.TLSClientConfig.CAFile = ""
+		c.TLSClientConfig.CAData = nil
+	case o.TLS.CAFile != "":
+		c.TLSClientConfig.CAFile = o.TLS.CAFile
+		c.TLSClientConfig.CAData = nil
+	case o.TLS.ServerName == "" && c.TLSClientConfig.CAFile == serviceAccountCAFile:
+		// The in-cluster CA certificate is usually only valid for the service
+		// names of the apiserver, so a different host fails with x509 errors.
+		klog.InfoS("The --apiserver host overrides the in-cluster host while still verifying it with the service account CA certificate. If this fails with x509 errors, set --apiserver-tls-server-name or --apiserver-ca-file", "apiserver", o.Apiserver)
+	}
+	if o.TLS.ServerName != "" {
+		c.TLSClientConfig.ServerName = o.TLS.ServerName
+	}
+
+	return c, nil
+}
+
+// loadConfig returns the in-cluster configuration if neither a kubeconfig path
+// nor a kubeconfig context is given and the service account token is mounted.
+// Otherwise it returns the configuration of the given context, or of the
+// current context, of the given kubeconfig or of the files of the KUBECONFIG
+// environment variable, defaulting to ~/.kube/config. Exec credential plugins
+// of the kubeconfig users may prompt for input if stdin is a terminal. The
+// apiserver URL, if set, overrides the host of either.
+func loadConfig(o ClientOptions) (*rest.Config, error) {
+	if o.Kubeconfig == "" && o.Context == "" {
+		if _, err := os.Stat(serviceAccountTokenFile); err == nil {
+			c, err := rest.InClusterConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the in-cluster configuration: %w", err)
+			}
+			if o.Apiserver != "" {
+				c.Host = o.Apiserver
+			}
+			return c, nil
 		}
 	}
 
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{
+		ClusterInfo:    clientcmdapi.Cluster{Server: o.Apiserver},
+		CurrentContext: o.Context,
+	}
+	c, err := clientcmd.NewInteractiveDeferredLoadingClientConfig(loadingRules, overrides, os.Stdin).ClientConfig()
+	if clientcmd.IsEmptyConfig(err) {
+		return nil, fmt.Errorf("no service account token mounted at %s and no kubeconfig found, set --kubeconfig or KUBECONFIG", serviceAccountTokenFile)
+	}
+	return c, err
+}
+
+// memoizedConfig returns the memoized client configuration if it was built from
+// the given options. Otherwise it builds the configuration and drops the clients
+// memoized for the previous options. clientsMtx has to be held.
+func memoizedConfig(o ClientOptions) (*rest.Config, error) {
+	if config != nil && configOptions == o {
+		return config, nil
+	}
+	c, err := BuildConfig(o)
+	if err != nil {
+		return nil, err
+	}
+	config, configOptions = c, o
+	currentKubeClient, currentDiscoveryClient = nil, nil
+	return config, nil
+}
+
+// CreateKubeClient creates a Kubernetes clientset and a custom resource clientset.
+func CreateKubeClient(o ClientOptions) (clientset.Interface, error) {
+	clientsMtx.Lock()
+	defer clientsMtx.Unlock()
+
+	config, err := memoizedConfig(o)
+	if err != nil {
+		return nil, err
+	}
+	if currentKubeClient != nil {
+		return currentKubeClient, nil
+	}
+
 	config.UserAgent = fmt.Sprintf("%s/%s (%s/%s) kubernetes/%s", "kube-state-metrics", version.Version, runtime.GOOS, runtime.GOARCH, version.Revision)
 	config.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
 	config.ContentType = "application/vnd.kubernetes.protobuf"
@@ -80,14 +227,14 @@ func CreateKubeClient(apiserver string, kubeconfig string) (clientset.Interface,
 }
 
 // CreateCustomResourceClients creates a custom resource clientset.
-func CreateCustomResourceClients(apiserver string, kubeconfig string, factories ...customresource.RegistryFactory) (map[string]interface{}, error) {
+func CreateCustomResourceClients(o ClientOptions, factories ...customresource.RegistryFactory) (map[string]interface{}, error) {
+	clientsMtx.Lock()
+	defer clientsMtx.Unlock()
+
 	// Not relying on memoized clients here because the factories are subject to change.
-	var err error
-	if config == nil {
-		config, err = clientcmd.BuildConfigFromFlags(apiserver, kubeconfig)
-		if err != nil {
-			return nil, err
-		}
+	config, err := memoizedConfig(o)
+	if err != nil {
+		return nil, err
 	}
 	customResourceClients := make(map[string]interface{}, len(factories))
 	for _, f := range factories {
@@ -95,36 +242,45 @@ func CreateCustomResourceClients(apiserver string, kubeconfig string, factories
 		if err != nil {
 			return nil, err
 		}
-		gvrString := GVRFromType(f.Name(), f.ExpectedType()).String()
+		gvrString := f.Name()
+		if gvr := GVRFromType(f.Name(), f.ExpectedType()); gvr != nil {
+			gvrString = gvr.String()
+		}
 		customResourceClients[gvrString] = customResourceClient
 	}
 	return customResourceClients, nil
 }
 
 // CreateDiscoveryClient creates a Kubernetes discovery client.
-func CreateDiscoveryClient(apiserver string, kubeconfig string) (*discovery.DiscoveryClient, error) {
+func CreateDiscoveryClient(o ClientOptions) (*discovery.DiscoveryClient, error) {
+	clientsMtx.Lock()
+	defer clientsMtx.Unlock()
+
+	config, err := memoizedConfig(o)
+	if err != nil {
+		return nil, err
+	}
 	if currentDiscoveryClient != nil {
 		return currentDiscoveryClient, nil
 	}
-	var err error
-	if config == nil {
-		var err error
-		config, err = clientcmd.BuildConfigFromFlags(apiserver, kubeconfig)
-		if err != nil {
-			return nil, err
-		}
-	}
 	currentDiscoveryClient, err = discovery.NewDiscoveryClientForConfig(config)
 	return currentDiscoveryClient, err
 }
 
 // GVRFromType returns the GroupVersionResource for a given type.
 func GVRFromType(resourceName string, expectedType interface{}) *schema.GroupVersionResource {
-	if _, ok := expectedType.(*testUnstructuredMock.Foo); ok {
-		// testUnstructuredMock.Foo is a mock type for testing
+	u, ok := expectedType.(*unstructured.Unstructured)
+	if !ok {
+		// Typed objects, e.g. the ones of out-of-tree collectors, are
+		// identified by the resource name.
+		return nil
+	}
+	apiVersion, ok := u.Object["apiVersion"].(string)
+	if !ok {
+		// Objects without an apiVersion, e.g. the ones of generic resources,
+		// are identified by the resource name.
 		return nil
 	}
-	apiVersion := expectedType.(*unstructured.Unstructured).Object["apiVersion"].(string)
 	expectedTypeSlice := strings.Split(apiVersion, "/")
 	g := expectedTypeSlice[0]
 	v := expectedTypeSlice[1]
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://kubeconfig.example.com:6443
+- name: other
+  cluster:
+    server: https://other.example.com:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+- name: other
+  context:
+    cluster: other
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	serviceAccountTokenFile = filepath.Join(dir, "token")
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc       string
+		env        string
+		apiserver  string
+		kubeconfig string
+		context    string
+		wantHost   string
+		wantErr    bool
+	}{
+		{desc: "kubeconfig flag", env: filepath.Join(dir, "missing"), kubeconfig: kubeconfig, wantHost: "https://kubeconfig.example.com:6443"},
+		{desc: "KUBECONFIG environment variable", env: kubeconfig, wantHost: "https://kubeconfig.example.com:6443"},
+		{desc: "kubeconfig context", env: kubeconfig, context: "other", wantHost: "https://other.example.com:6443"},
+		{desc: "unknown kubeconfig context", env: kubeconfig, context: "missing", wantErr: true},
+		{desc: "apiserver override", env: kubeconfig, apiserver: "https://apiserver.example.com", wantHost: "https://apiserver.example.com"},
+		{desc: "no configuration", env: filepath.Join(dir, "missing"), wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", test.env)
+			c, err := loadConfig(ClientOptions{Apiserver: test.apiserver, Kubeconfig: test.kubeconfig, Context: test.context})
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got the host %s", c.Host)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Host != test.wantHost {
+				t.Errorf("expected the host %s, got %s", test.wantHost, c.Host)
+			}
+		})
+	}
+}
+
+func TestCreateDiscoveryClientRebuildsConfig(t *testing.T) {
+	dir := t.TempDir()
+	serviceAccountTokenFile = filepath.Join(dir, "token")
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		context  string
+		wantHost string
+	}{
+		{context: "test", wantHost: "https://kubeconfig.example.com:6443"},
+		{context: "test", wantHost: "https://kubeconfig.example.com:6443"},
+		{context: "other", wantHost: "https://other.example.com:6443"},
+	} {
+		c, err := CreateDiscoveryClient(ClientOptions{Kubeconfig: kubeconfig, Context: test.context})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.RESTClient().Get().URL().Host; "https://"+got != test.wantHost {
+			t.Errorf("expected the host %s for the context %s, got %s", test.wantHost, test.context, got)
+		}
+	}
+}
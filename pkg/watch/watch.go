@@ -17,23 +17,29 @@ limitations under the License.
 package watch
 
 import (
+	"strconv"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
 )
 
-// ListWatchMetrics stores the pointers of kube_state_metrics_[list|watch]_total metrics.
+// ListWatchMetrics stores the pointers of kube_state_metrics_[list|watch]_total
+// and kube_state_metrics_last_resource_version metrics.
 type ListWatchMetrics struct {
-	WatchTotal *prometheus.CounterVec
-	ListTotal  *prometheus.CounterVec
+	WatchTotal          *prometheus.CounterVec
+	ListTotal           *prometheus.CounterVec
+	LastResourceVersion *prometheus.GaugeVec
 }
 
 // NewListWatchMetrics takes in a prometheus registry and initializes
-// and registers the kube_state_metrics_list_total and
-// kube_state_metrics_watch_total metrics. It returns those registered metrics.
+// and registers the kube_state_metrics_list_total,
+// kube_state_metrics_watch_total and kube_state_metrics_last_resource_version
+// metrics. It returns those registered metrics.
 func NewListWatchMetrics(r prometheus.Registerer) *ListWatchMetrics {
 	return &ListWatchMetrics{
 		WatchTotal: promauto.With(r).NewCounterVec(
@@ -50,6 +56,13 @@ func NewListWatchMetrics(r prometheus.Registerer) *ListWatchMetrics {
 			},
 			[]string{"result", "resource"},
 		),
+		LastResourceVersion: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "kube_state_metrics_last_resource_version",
+				Help: "Resource version of the last successful list or watch event of a resource in kube-state-metrics",
+			},
+			[]string{"resource", "namespace"},
+		),
 	}
 }
 
@@ -59,15 +72,19 @@ type InstrumentedListerWatcher struct {
 	lw                cache.ListerWatcher
 	metrics           *ListWatchMetrics
 	resource          string
+	namespace         string
 	useAPIServerCache bool
 }
 
-// NewInstrumentedListerWatcher returns a new InstrumentedListerWatcher.
-func NewInstrumentedListerWatcher(lw cache.ListerWatcher, metrics *ListWatchMetrics, resource string, useAPIServerCache bool) cache.ListerWatcher {
+// NewInstrumentedListerWatcher returns a new InstrumentedListerWatcher. The
+// namespace is the one the ListerWatcher is restricted to, or empty for all
+// namespaces.
+func NewInstrumentedListerWatcher(lw cache.ListerWatcher, metrics *ListWatchMetrics, resource string, namespace string, useAPIServerCache bool) cache.ListerWatcher {
 	return &InstrumentedListerWatcher{
 		lw:                lw,
 		metrics:           metrics,
 		resource:          resource,
+		namespace:         namespace,
 		useAPIServerCache: useAPIServerCache,
 	}
 }
@@ -87,11 +104,15 @@ func (i *InstrumentedListerWatcher) List(options metav1.ListOptions) (runtime.Ob
 	}
 
 	i.metrics.ListTotal.WithLabelValues("success", i.resource).Inc()
+	if list, err := meta.ListAccessor(res); err == nil {
+		i.setLastResourceVersion(list.GetResourceVersion())
+	}
 	return res, nil
 }
 
 // Watch is a wrapper func around the cache.ListerWatcher.Watch func. It increases the success/error
-// counters based on the outcome of the Watch operation it instruments.
+// counters based on the outcome of the Watch operation it instruments. The last resource version is
+// updated with the resource version of every event of the watch, including bookmarks.
 func (i *InstrumentedListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
 	res, err := i.lw.Watch(options)
 	if err != nil {
@@ -100,5 +121,24 @@ func (i *InstrumentedListerWatcher) Watch(options metav1.ListOptions) (watch.Int
 	}
 
 	i.metrics.WatchTotal.WithLabelValues("success", i.resource).Inc()
-	return res, nil
+	i.setLastResourceVersion(options.ResourceVersion)
+	return watch.Filter(res, func(e watch.Event) (watch.Event, bool) {
+		if obj, err := meta.Accessor(e.Object); err == nil && e.Type != watch.Error {
+			i.setLastResourceVersion(obj.GetResourceVersion())
+		}
+		return e, true
+	}), nil
+}
+
+// setLastResourceVersion updates the last resource version of the resource.
+// Resource versions are opaque to clients, so versions which are not numeric
+// are ignored. ListerWatchers of the same resource type and namespace, e.g. of
+// custom resources which are all unstructured, share the gauge, in which case
+// the last writer wins.
+func (i *InstrumentedListerWatcher) setLastResourceVersion(resourceVersion string) {
+	rv, err := strconv.ParseUint(resourceVersion, 10, 64)
+	if err != nil {
+		return
+	}
+	i.metrics.LastResourceVersion.WithLabelValues(i.resource, i.namespace).Set(float64(rv))
 }
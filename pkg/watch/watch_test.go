@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watch
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestInstrumentedListerWatcherLastResourceVersion(t *testing.T) {
+	metrics := NewListWatchMetrics(prometheus.NewRegistry())
+	fake := watch.NewFake()
+	lw := NewInstrumentedListerWatcher(&cache.ListWatch{
+		ListFunc: func(_ metav1.ListOptions) (runtime.Object, error) {
+			return &v1.PodList{ListMeta: metav1.ListMeta{ResourceVersion: "10"}}, nil
+		},
+		WatchFunc: func(_ metav1.ListOptions) (watch.Interface, error) {
+			return fake, nil
+		},
+	}, metrics, "*v1.Pod", "default", false)
+
+	lastResourceVersion := func() float64 {
+		return testutil.ToFloat64(metrics.LastResourceVersion.WithLabelValues("*v1.Pod", "default"))
+	}
+
+	if _, err := lw.List(metav1.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastResourceVersion(); got != 10 {
+		t.Fatalf("expected the resource version of the list, got %v", got)
+	}
+
+	w, err := lw.Watch(metav1.ListOptions{ResourceVersion: "10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	events := []struct {
+		event watch.Event
+		want  float64
+	}{
+		{event: watch.Event{Type: watch.Added, Object: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", ResourceVersion: "11"}}}, want: 11},
+		{event: watch.Event{Type: watch.Bookmark, Object: &v1.Pod{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "20"}}}, want: 20},
+		{event: watch.Event{Type: watch.Error, Object: &metav1.Status{Status: metav1.StatusFailure}}, want: 20},
+		{event: watch.Event{Type: watch.Deleted, Object: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", ResourceVersion: "21"}}}, want: 21},
+	}
+	for _, e := range events {
+		go fake.Action(e.event.Type, e.event.Object)
+		got := <-w.ResultChan()
+		if got.Type != e.event.Type {
+			t.Fatalf("expected a %s event to be passed on, got %s", e.event.Type, got.Type)
+		}
+		if got := lastResourceVersion(); got != e.want {
+			t.Fatalf("expected the resource version %v after a %s event, got %v", e.want, e.event.Type, got)
+		}
+	}
+}
@@ -194,6 +194,97 @@ func getLabelsDocumentation() (map[string][]string, error) {
 	return documentedMetrics, nil
 }
 
+func TestDocumentedHelpAndType(t *testing.T) {
+	typesDocumentation, err := getTypesDocumentation()
+	if err != nil {
+		t.Fatal("Cannot get types documentation", err)
+	}
+
+	metricFamilies, err := framework.ParseMetrics(framework.KsmClient.Metrics)
+	if err != nil {
+		t.Fatal("Failed to get or decode metrics", err)
+	}
+
+	for _, metricFamily := range metricFamilies {
+		metric := metricFamily.GetName()
+		if metricFamily.GetHelp() == "" {
+			t.Errorf("Metric %s has no HELP text.", metric)
+		}
+
+		documentedType, ok := typesDocumentation[metric]
+		if !ok {
+			// Undocumented metrics are reported by TestDocumentation.
+			continue
+		}
+		if got := metricFamily.GetType().String(); got != strings.ToUpper(documentedType) {
+			t.Errorf("Metric %s has TYPE %s, but is documented as %s.", metric, got, documentedType)
+		}
+	}
+}
+
+// getTypesDocumentation is a helper function that gets the documented metric
+// types. It returns a map where keys are metric names, and values are their
+// types, e.g. Gauge.
+func getTypesDocumentation() (map[string]string, error) {
+	documentedTypes := map[string]string{}
+
+	// Match file names such as daemonset-metrics.md
+	fileRe := regexp.MustCompile(`^([a-z]*)-metrics.md$`)
+	// Match doc lines such as | kube_node_created | Gauge | ...
+	lineRe := regexp.MustCompile(`^\| *(kube_[a-z_]+) *\| *([a-zA-Z]+) *\|`)
+
+	err := filepath.WalkDir("../../docs", func(p string, d fs.DirEntry, _ error) error {
+		if d.IsDir() || !fileRe.MatchString(d.Name()) {
+			// Ignore the entry
+			return nil
+		}
+
+		content, e := os.ReadFile(filepath.Clean(p))
+		if e != nil {
+			return fmt.Errorf("cannot read file %s: %w", p, e)
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			if params := lineRe.FindStringSubmatch(line); len(params) == 3 {
+				documentedTypes[params[1]] = params[2]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot walk the documentation directory: %w", err)
+	}
+
+	return documentedTypes, nil
+}
+
+func TestNoDuplicateSeries(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := framework.KsmClient.Metrics(buf)
+	if err != nil {
+		t.Fatalf("failed to get metrics from kube-state-metrics: %v", err)
+	}
+
+	seen := map[string]struct{}{}
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// The series is everything but the value at the end of the line.
+		series := line[:strings.LastIndex(line, " ")]
+		if _, ok := seen[series]; ok {
+			t.Errorf("Duplicate series %s", series)
+		}
+		seen[series] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("failed to scan metrics: %v", err)
+	}
+}
+
 func TestKubeStateMetricsErrorMetrics(t *testing.T) {
 	metricFamilies, err := framework.ParseMetrics(framework.KsmClient.TelemetryMetrics)
 	if err != nil {